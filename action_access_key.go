@@ -92,6 +92,27 @@ func (c *Client) GetAccessKeys(ctx context.Context, metadataConditions *Metadata
 	return accessKeys, nil
 }
 
+// GetAccessKeysWithCursor will get a page of access keys from the Datastore using cursor pagination
+//
+// Returns the next cursor (empty if there are no more access keys)
+func (c *Client) GetAccessKeysWithCursor(ctx context.Context, metadataConditions *Metadata,
+	conditions *map[string]interface{}, cursorParams *CursorQueryParams, opts ...ModelOps,
+) ([]*AccessKey, string, error) {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "get_access_keys_with_cursor")
+
+	// Get the access keys
+	accessKeys, nextCursor, err := getModelsByCursor[*AccessKey](
+		ctx, ModelAccessKey, metadataConditions, conditions, cursorParams,
+		c.DefaultModelOptions(opts...)...,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return accessKeys, nextCursor, nil
+}
+
 // GetAccessKeysCount will get a count of all the access keys from the Datastore
 func (c *Client) GetAccessKeysCount(ctx context.Context, metadataConditions *Metadata,
 	conditions *map[string]interface{}, opts ...ModelOps) (int64, error) {
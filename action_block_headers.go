@@ -0,0 +1,49 @@
+package bux
+
+import (
+	"context"
+	"io"
+)
+
+// ImportBlockHeaders will import raw (80 bytes) block headers, IE: from a headers file, into the local header store
+//
+// The first header must connect to the stored headers, or it starts the header chain at startHeight if the
+// store is empty. Returns the number of imported headers
+func (c *Client) ImportBlockHeaders(ctx context.Context, reader io.Reader, startHeight uint64) (uint64, error) {
+	if !c.options.chainstate.localHeaders {
+		return 0, ErrLocalHeaderStoreDisabled
+	}
+
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "import_block_headers")
+
+	return newBlockHeaderStore(c).importHeaders(ctx, reader, startHeight)
+}
+
+// SyncBlockHeaders will extend the local header store with the headers of the block header source
+//
+// Returns the number of added headers
+func (c *Client) SyncBlockHeaders(ctx context.Context) (uint64, error) {
+	if !c.options.chainstate.localHeaders {
+		return 0, ErrLocalHeaderStoreDisabled
+	} else if c.options.chainstate.headerSource == nil {
+		return 0, ErrMissingBlockHeaderSource
+	}
+
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "sync_block_headers")
+
+	return newBlockHeaderStore(c).syncHeaders(ctx, c.options.chainstate.headerSource)
+}
+
+// GetBlockHeaderTip will get the block header at the tip of the longest chain of the local header store
+func (c *Client) GetBlockHeaderTip(ctx context.Context) (*BlockHeader, error) {
+	if !c.options.chainstate.localHeaders {
+		return nil, ErrLocalHeaderStoreDisabled
+	}
+
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "get_block_header_tip")
+
+	return getChainTip(ctx, c.DefaultModelOptions()...)
+}
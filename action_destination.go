@@ -95,6 +95,27 @@ func (c *Client) GetDestinations(ctx context.Context, metadataConditions *Metada
 	return destinations, nil
 }
 
+// GetDestinationsWithCursor will get a page of destinations from the Datastore using cursor pagination
+//
+// Returns the next cursor (empty if there are no more destinations)
+func (c *Client) GetDestinationsWithCursor(ctx context.Context, metadataConditions *Metadata,
+	conditions *map[string]interface{}, cursorParams *CursorQueryParams, opts ...ModelOps,
+) ([]*Destination, string, error) {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "get_destinations_with_cursor")
+
+	// Get the destinations
+	destinations, nextCursor, err := getModelsByCursor[*Destination](
+		ctx, ModelDestination, metadataConditions, conditions, cursorParams,
+		c.DefaultModelOptions(opts...)...,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return destinations, nextCursor, nil
+}
+
 // GetDestinationsCount will get a count of all the destinations from the Datastore
 func (c *Client) GetDestinationsCount(ctx context.Context, metadataConditions *Metadata,
 	conditions *map[string]interface{}, opts ...ModelOps,
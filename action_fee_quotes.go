@@ -0,0 +1,69 @@
+package bux
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/BuxOrg/bux/chainstate"
+	"github.com/BuxOrg/bux/cluster"
+	"github.com/BuxOrg/bux/utils"
+	"github.com/mrz1836/go-datastore"
+)
+
+// RefreshFeeUnit will refresh the fee unit from the fee quotes of the providers (see WithFeeQuotesRefresh)
+//
+// The refresh is recorded in the fee history and a changed fee unit is published to the other nodes of the cluster
+func (c *Client) RefreshFeeUnit(ctx context.Context) (*chainstate.FeeUnitRefresh, error) {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "refresh_fee_unit")
+
+	refresh, err := c.Chainstate().RefreshFeeUnit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Record the refresh
+	if err = newFeeHistory(refresh, c.DefaultModelOptions(New())...).Save(ctx); err != nil {
+		return nil, err
+	}
+
+	// Publish the new fee unit to the other nodes
+	if refresh.Changed && c.Cluster() != nil {
+		var data []byte
+		if data, err = json.Marshal(refresh.FeeUnit); err != nil {
+			return nil, err
+		}
+		if err = c.Cluster().Publish(cluster.FeeUnitUpdated, string(data)); err != nil {
+			c.Logger().Error().Err(err).Msg("failed to publish the fee unit update")
+		}
+	}
+
+	return refresh, nil
+}
+
+// GetFeeHistory will get the recorded fee quotes refreshes
+func (c *Client) GetFeeHistory(ctx context.Context, conditions *map[string]interface{},
+	queryParams *datastore.QueryParams, opts ...ModelOps,
+) ([]*FeeHistory, error) {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "get_fee_history")
+
+	return getFeeHistory(ctx, conditions, queryParams, c.DefaultModelOptions(opts...)...)
+}
+
+// subscribeFeeUnitUpdates will set the fee unit selected by another node of the cluster
+func (c *Client) subscribeFeeUnitUpdates() error {
+	if c.options.chainstate.feeQuotesRefresh <= 0 || c.Cluster() == nil {
+		return nil
+	}
+
+	_, err := c.Cluster().Subscribe(cluster.FeeUnitUpdated, func(data string) {
+		feeUnit := new(utils.FeeUnit)
+		if err := json.Unmarshal([]byte(data), feeUnit); err != nil {
+			c.Logger().Error().Err(err).Msg("invalid fee unit update")
+			return
+		}
+		c.Chainstate().SetFeeUnit(feeUnit)
+	})
+	return err
+}
@@ -0,0 +1,66 @@
+package bux
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BuxOrg/bux/chainstate"
+	"github.com/BuxOrg/bux/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chainStateFeeQuotes is a chainstate returning a new fee unit on each refresh
+type chainStateFeeQuotes struct {
+	chainStateEverythingOnChain
+	feeUnit *utils.FeeUnit
+	updates []utils.FeeUnit // fee units set by the cluster
+}
+
+func (c *chainStateFeeQuotes) FeeUnit() *utils.FeeUnit {
+	return c.feeUnit
+}
+
+func (c *chainStateFeeQuotes) RefreshFeeUnit(context.Context) (*chainstate.FeeUnitRefresh, error) {
+	previous := c.feeUnit
+	c.feeUnit = &utils.FeeUnit{Satoshis: previous.Satoshis + 1, Bytes: previous.Bytes}
+	return &chainstate.FeeUnitRefresh{
+		Changed:  true,
+		FeeUnit:  c.feeUnit,
+		Previous: previous,
+		Provider: chainstate.ProviderMinercraft,
+		Quotes:   []utils.FeeUnit{*c.feeUnit},
+	}, nil
+}
+
+func (c *chainStateFeeQuotes) SetFeeUnit(feeUnit *utils.FeeUnit) {
+	c.updates = append(c.updates, *feeUnit)
+}
+
+// TestClient_RefreshFeeUnit will test the method RefreshFeeUnit()
+func TestClient_RefreshFeeUnit(t *testing.T) {
+	chain := &chainStateFeeQuotes{feeUnit: &utils.FeeUnit{Satoshis: 1, Bytes: 1000}}
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+		WithCustomChainstate(chain), WithFeeQuotesRefresh(time.Minute, chainstate.FeeUnitPolicy{}))
+	defer deferMe()
+
+	refresh, err := client.RefreshFeeUnit(ctx)
+	require.NoError(t, err)
+	assert.True(t, refresh.Changed)
+	assert.Equal(t, utils.FeeUnit{Satoshis: 2, Bytes: 1000}, *refresh.FeeUnit)
+
+	// the change is published to the nodes of the cluster
+	assert.Equal(t, []utils.FeeUnit{*refresh.FeeUnit}, chain.updates)
+
+	// recorded in the fee history
+	var history []*FeeHistory
+	history, err = client.GetFeeHistory(ctx, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, 2, history[0].Satoshis)
+	assert.Equal(t, 1, history[0].PreviousSatoshis)
+	assert.True(t, history[0].Changed)
+	assert.Equal(t, chainstate.ProviderMinercraft, history[0].Provider)
+	assert.Equal(t, 1, history[0].Quotes)
+}
@@ -45,6 +45,27 @@ func (c *Client) GetPaymailAddresses(ctx context.Context, metadataConditions *Me
 	return paymailAddresses, nil
 }
 
+// GetPaymailAddressesWithCursor will get a page of paymail addresses from the Datastore using cursor pagination
+//
+// Returns the next cursor (empty if there are no more paymail addresses)
+func (c *Client) GetPaymailAddressesWithCursor(ctx context.Context, metadataConditions *Metadata,
+	conditions *map[string]interface{}, cursorParams *CursorQueryParams, opts ...ModelOps,
+) ([]*PaymailAddress, string, error) {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "get_paymail_addresses_with_cursor")
+
+	// Get the paymail addresses
+	paymailAddresses, nextCursor, err := getModelsByCursor[*PaymailAddress](
+		ctx, ModelPaymailAddress, metadataConditions, conditions, cursorParams,
+		c.DefaultModelOptions(opts...)...,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return paymailAddresses, nextCursor, nil
+}
+
 // GetPaymailAddressesCount will get a count of all the paymail addresses from the Datastore
 func (c *Client) GetPaymailAddressesCount(ctx context.Context, metadataConditions *Metadata,
 	conditions *map[string]interface{}, opts ...ModelOps) (int64, error) {
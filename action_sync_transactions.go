@@ -0,0 +1,220 @@
+package bux
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mrz1836/go-datastore"
+)
+
+// Admin actions on the sync transactions (see SyncResult)
+const (
+	syncAdminCancel   = "canceled by admin"
+	syncAdminRetry    = "retry forced by admin"
+	syncAdminSkip     = "skipped by admin"
+	syncProviderAdmin = "admin"
+)
+
+// SyncQueueSummary is the summary of the sync queue (for dashboards)
+type SyncQueueSummary struct {
+	Broadcast        map[string]int64 `json:"broadcast"`          // Sync transactions per broadcast status
+	OldestPendingAge time.Duration    `json:"oldest_pending_age"` // Age of the oldest unfinished sync transaction (0: none)
+	OldestPendingID  string           `json:"oldest_pending_id"`  // ID of the oldest unfinished sync transaction
+	P2P              map[string]int64 `json:"p2p"`                // Sync transactions per p2p status
+	Sync             map[string]int64 `json:"sync"`               // Sync transactions per on-chain sync status
+}
+
+// unfinishedSyncStatuses are the statuses of the actions which are still processed (or waiting to be)
+var unfinishedSyncStatuses = []SyncStatus{
+	SyncStatusPending, SyncStatusProcessing, SyncStatusReady, SyncStatusScheduled,
+}
+
+// GetSyncTransactions will get the sync transactions (with their results) using the given conditions
+//
+// IE: map[string]interface{}{"broadcast_status": "deadletter"}
+func (c *Client) GetSyncTransactions(ctx context.Context, conditions *map[string]interface{},
+	queryParams *datastore.QueryParams, opts ...ModelOps,
+) ([]*SyncTransaction, error) {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "get_sync_transactions")
+
+	return getSyncTransactions(ctx, conditions, queryParams, c.DefaultModelOptions(opts...)...)
+}
+
+// GetSyncTransactionsCount will get a count of the sync transactions using the given conditions
+func (c *Client) GetSyncTransactionsCount(ctx context.Context, conditions *map[string]interface{},
+	opts ...ModelOps,
+) (int64, error) {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "get_sync_transactions_count")
+
+	return getSyncTransactionsCount(ctx, conditions, c.DefaultModelOptions(opts...)...)
+}
+
+// GetSyncQueueSummary will get the count of the sync transactions per status and the oldest unfinished one
+func (c *Client) GetSyncQueueSummary(ctx context.Context, opts ...ModelOps) (*SyncQueueSummary, error) {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "get_sync_queue_summary")
+
+	defaultOpts := c.DefaultModelOptions(opts...)
+	summary := new(SyncQueueSummary)
+
+	var err error
+	if summary.Broadcast, err = getSyncTransactionsPerStatus(ctx, broadcastStatusField, defaultOpts...); err != nil {
+		return nil, err
+	}
+	if summary.P2P, err = getSyncTransactionsPerStatus(ctx, p2pStatusField, defaultOpts...); err != nil {
+		return nil, err
+	}
+	if summary.Sync, err = getSyncTransactionsPerStatus(ctx, syncStatusField, defaultOpts...); err != nil {
+		return nil, err
+	}
+
+	// Get the oldest unfinished sync transaction
+	or := make([]map[string]interface{}, 0, 3*len(unfinishedSyncStatuses))
+	for _, field := range []string{broadcastStatusField, p2pStatusField, syncStatusField} {
+		for _, status := range unfinishedSyncStatuses {
+			or = append(or, map[string]interface{}{field: status.String()})
+		}
+	}
+	var oldest []*SyncTransaction
+	if oldest, err = getSyncTransactions(
+		ctx, &map[string]interface{}{conditionOr: or}, &datastore.QueryParams{
+			OrderByField:  createdAtField,
+			Page:          1,
+			PageSize:      1,
+			SortDirection: datastore.SortAsc,
+		}, defaultOpts...,
+	); err != nil {
+		return nil, err
+	} else if len(oldest) > 0 {
+		summary.OldestPendingAge = time.Since(oldest[0].CreatedAt)
+		summary.OldestPendingID = oldest[0].ID
+	}
+
+	return summary, nil
+}
+
+// RetrySyncTransactions will force a retry of the broadcast and the on-chain sync of the sync transactions
+//
+// Failed (error, dead-letter), scheduled and backing off actions are ready right away, the attempts are reset.
+// P2P notifications are not retried (a failed notification reverts the transaction).
+func (c *Client) RetrySyncTransactions(ctx context.Context, ids []string, opts ...ModelOps) ([]*SyncTransaction, error) {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "retry_sync_transactions")
+
+	return c.updateSyncTransactions(ctx, ids, func(syncTx *SyncTransaction) {
+		switch syncTx.BroadcastStatus {
+		case SyncStatusError, SyncStatusDeadLetter, SyncStatusReady, SyncStatusScheduled:
+			syncTx.BroadcastStatus = SyncStatusReady
+			syncTx.addAdminResult(syncActionBroadcast, syncAdminRetry)
+		}
+		switch syncTx.SyncStatus {
+		case SyncStatusError, SyncStatusDeadLetter, SyncStatusReady:
+			syncTx.SyncStatus = SyncStatusReady
+			syncTx.addAdminResult(syncActionSync, syncAdminRetry)
+		}
+		syncTx.resetAttempts()
+	}, opts...)
+}
+
+// CancelSyncTransactions will cancel the unfinished actions (broadcast, p2p, sync) of the sync transactions
+func (c *Client) CancelSyncTransactions(ctx context.Context, ids []string, opts ...ModelOps) ([]*SyncTransaction, error) {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "cancel_sync_transactions")
+
+	return c.updateSyncTransactions(ctx, ids, func(syncTx *SyncTransaction) {
+		syncTx.setUnfinishedStatus(SyncStatusCanceled, syncAdminCancel)
+	}, opts...)
+}
+
+// SkipSyncTransactions will mark the unfinished actions (broadcast, p2p, sync) of the sync transactions as skipped
+func (c *Client) SkipSyncTransactions(ctx context.Context, ids []string, opts ...ModelOps) ([]*SyncTransaction, error) {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "skip_sync_transactions")
+
+	return c.updateSyncTransactions(ctx, ids, func(syncTx *SyncTransaction) {
+		syncTx.setUnfinishedStatus(SyncStatusSkipped, syncAdminSkip)
+	}, opts...)
+}
+
+// updateSyncTransactions will update and save the sync transactions (locked against a running broadcast)
+func (c *Client) updateSyncTransactions(ctx context.Context, ids []string, update func(syncTx *SyncTransaction),
+	opts ...ModelOps,
+) ([]*SyncTransaction, error) {
+	syncTxs := make([]*SyncTransaction, 0, len(ids))
+	for _, id := range ids {
+		syncTx, err := c.updateSyncTransaction(ctx, id, update, opts...)
+		if err != nil {
+			return syncTxs, err
+		}
+		syncTxs = append(syncTxs, syncTx)
+	}
+	return syncTxs, nil
+}
+
+// updateSyncTransaction will update and save a sync transaction (locked against a running broadcast)
+//
+// The claim lock is taken as well, a worker processing the sync transaction does not overwrite the update
+// (see saveClaimed)
+func (c *Client) updateSyncTransaction(ctx context.Context, id string, update func(syncTx *SyncTransaction),
+	opts ...ModelOps,
+) (*SyncTransaction, error) {
+	unlock, err := newWriteLock(ctx, fmt.Sprintf(lockKeyProcessBroadcastTx, id), c.Cachestore())
+	defer unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var unlockClaim func()
+	unlockClaim, err = newWaitWriteLock(ctx, fmt.Sprintf(lockKeyClaimSyncTx, id), c.Cachestore())
+	defer unlockClaim()
+	if err != nil {
+		return nil, err
+	}
+
+	var syncTx *SyncTransaction
+	if syncTx, err = GetSyncTransactionByID(ctx, id, c.DefaultModelOptions(opts...)...); err != nil {
+		return nil, err
+	} else if syncTx == nil {
+		return nil, fmt.Errorf("%w: %s", ErrMissingSyncTransaction, id)
+	}
+
+	update(syncTx)
+	if err = syncTx.Save(ctx); err != nil {
+		return nil, err
+	}
+	return syncTx, nil
+}
+
+// setUnfinishedStatus will set the status of the unfinished actions (and record the admin action)
+func (m *SyncTransaction) setUnfinishedStatus(status SyncStatus, message string) {
+	for _, item := range []struct {
+		action string
+		status *SyncStatus
+	}{
+		{syncActionBroadcast, &m.BroadcastStatus},
+		{syncActionP2P, &m.P2PStatus},
+		{syncActionSync, &m.SyncStatus},
+	} {
+		switch *item.status {
+		case SyncStatusComplete, SyncStatusCanceled, SyncStatusSkipped, SyncStatusUnverifiable:
+			continue
+		}
+		*item.status = status
+		m.addAdminResult(item.action, message)
+	}
+	m.NextAttempt = nextAttemptAt(time.Now().UTC())
+}
+
+// addAdminResult will record the admin action in the results
+func (m *SyncTransaction) addAdminResult(action, message string) {
+	m.Results.LastMessage = message
+	m.Results.Results = append(m.Results.Results, &SyncResult{
+		Action:        action,
+		ExecutedAt:    time.Now().UTC(),
+		Provider:      syncProviderAdmin,
+		StatusMessage: message,
+	})
+}
@@ -0,0 +1,90 @@
+package bux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSyncTxID2 = "b2a2f4c4b9d6d2a1e4bcd6b0dfd8ec0ff2b6d64e8e4b2c7f1bd0a4d2f6c1e3a9"
+
+// TestClient_SyncTransactions will test the admin methods of the sync queue
+func TestClient_SyncTransactions(t *testing.T) {
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup())
+	defer deferMe()
+
+	opts := append(client.DefaultModelOptions(), New())
+	deadLetter := newSyncTransaction(testTxID, &SyncConfig{Broadcast: true, SyncOnChain: true}, opts...)
+	deadLetter.BroadcastStatus = SyncStatusDeadLetter
+	deadLetter.SyncStatus = SyncStatusPending
+	deadLetter.Attempts = 10
+	require.NoError(t, deadLetter.Save(ctx))
+	synced := newSyncTransaction(testSyncTxID2, &SyncConfig{SyncOnChain: true}, opts...)
+	synced.SyncStatus = SyncStatusComplete
+	require.NoError(t, synced.Save(ctx))
+
+	t.Run("list and count by status", func(t *testing.T) {
+		conditions := map[string]interface{}{broadcastStatusField: SyncStatusDeadLetter}
+		syncTxs, err := client.GetSyncTransactions(ctx, &conditions, nil)
+		require.NoError(t, err)
+		require.Len(t, syncTxs, 1)
+		assert.Equal(t, testTxID, syncTxs[0].ID)
+
+		var count int64
+		count, err = client.GetSyncTransactionsCount(ctx, &map[string]interface{}{
+			syncStatusField: SyncStatusComplete,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("queue summary", func(t *testing.T) {
+		summary, err := client.GetSyncQueueSummary(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int64{statusDeadLetter: 1, statusSkipped: 1}, summary.Broadcast)
+		assert.Equal(t, map[string]int64{statusSkipped: 2}, summary.P2P)
+		assert.Equal(t, map[string]int64{statusPending: 1, statusComplete: 1}, summary.Sync)
+		assert.Equal(t, testTxID, summary.OldestPendingID)
+		assert.Greater(t, summary.OldestPendingAge, time.Duration(0))
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		syncTxs, err := client.RetrySyncTransactions(ctx, []string{testTxID})
+		require.NoError(t, err)
+		require.Len(t, syncTxs, 1)
+
+		syncTx, err := GetSyncTransactionByID(ctx, testTxID, client.DefaultModelOptions()...)
+		require.NoError(t, err)
+		assert.Equal(t, SyncStatusReady, syncTx.BroadcastStatus)
+		assert.Equal(t, SyncStatusPending, syncTx.SyncStatus)
+		assert.Equal(t, uint32(0), syncTx.Attempts)
+		assert.Equal(t, syncAdminRetry, syncTx.Results.LastMessage)
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		_, err := client.SkipSyncTransactions(ctx, []string{testTxID})
+		require.NoError(t, err)
+
+		syncTx, err := GetSyncTransactionByID(ctx, testTxID, client.DefaultModelOptions()...)
+		require.NoError(t, err)
+		assert.Equal(t, SyncStatusSkipped, syncTx.BroadcastStatus)
+		assert.Equal(t, SyncStatusSkipped, syncTx.SyncStatus)
+	})
+
+	t.Run("cancel keeps the finished actions", func(t *testing.T) {
+		_, err := client.CancelSyncTransactions(ctx, []string{testSyncTxID2})
+		require.NoError(t, err)
+
+		syncTx, err := GetSyncTransactionByID(ctx, testSyncTxID2, client.DefaultModelOptions()...)
+		require.NoError(t, err)
+		assert.Equal(t, SyncStatusComplete, syncTx.SyncStatus)
+		assert.Equal(t, SyncStatusSkipped, syncTx.BroadcastStatus)
+	})
+
+	t.Run("unknown sync transaction", func(t *testing.T) {
+		_, err := client.CancelSyncTransactions(ctx, []string{"unknown"})
+		require.ErrorIs(t, err, ErrMissingSyncTransaction)
+	})
+}
@@ -51,6 +51,26 @@ func (c *Client) RecordRawTransaction(ctx context.Context, txHex string,
 	return saveRawTransaction(ctx, c, true, txHex, opts...)
 }
 
+// RecordBEEFTransaction will decode the BEEF transaction, verify it using SPV and save it (and its ancestors) into the Datastore
+//
+// The merkle roots of all the BUMPs are verified against the HeaderService, the input scripts of all the unmined
+// transactions are validated and the fee of the transaction is checked against the current fee unit
+//
+// beefHex is the BEEF (BRC-62), BEEF V2 (BRC-96) or Atomic BEEF (BRC-95) hex of the transaction
+// opts are model options and can include "metadata"
+func (c *Client) RecordBEEFTransaction(ctx context.Context, beefHex string,
+	opts ...ModelOps,
+) (*Transaction, error) {
+	ctx = c.GetOrStartTxn(ctx, "record_beef_transaction")
+
+	dBeef, err := verifyBEEF(ctx, c, beefHex)
+	if err != nil {
+		return nil, err
+	}
+
+	return recordBEEFTransaction(ctx, c, dBeef, opts...)
+}
+
 // NewTransaction will create a new draft transaction and return it
 //
 // ctx is the context
@@ -79,6 +99,13 @@ func (c *Client) NewTransaction(ctx context.Context, rawXpubKey string, config *
 		c.DefaultModelOptions(append(opts, New())...)...,
 	)
 
+	// Set the fee unit of the fee priority (if no fee unit was given)
+	if config.FeeUnit == nil {
+		if err = c.setDraftFeePriority(ctx, draftTransaction); err != nil {
+			return nil, err
+		}
+	}
+
 	// Save the model
 	if err = draftTransaction.Save(ctx); err != nil {
 		return nil, err
@@ -107,6 +134,27 @@ func (c *Client) GetTransaction(ctx context.Context, xPubID, txID string) (*Tran
 	return transaction, nil
 }
 
+// GetTransactionBEEF will get the BEEF (hex) of a transaction, with its ancestry up to the first mined ancestors
+//
+// The BEEF is cached until one of its unmined transactions gets mined
+func (c *Client) GetTransactionBEEF(ctx context.Context, xPubID, txID string) (string, error) {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "get_transaction_beef")
+
+	// Get the transaction by ID
+	transaction, err := getTransactionByID(
+		ctx, xPubID, txID, c.DefaultModelOptions()...,
+	)
+	if err != nil {
+		return "", err
+	}
+	if transaction == nil {
+		return "", ErrMissingTransaction
+	}
+
+	return getTransactionBEEF(ctx, c, transaction)
+}
+
 // GetTransactionsByIDs returns array of transactions by their IDs from the Datastore
 func (c *Client) GetTransactionsByIDs(ctx context.Context, txIDs []string) ([]*Transaction, error) {
 	// Check for existing NewRelic transaction
@@ -157,6 +205,67 @@ func (c *Client) GetTransactions(ctx context.Context, metadataConditions *Metada
 	return transactions, nil
 }
 
+// GetTransactionsWithCursor will get a page of transactions from the Datastore using cursor pagination
+//
+// Returns the next cursor (empty if there are no more transactions)
+func (c *Client) GetTransactionsWithCursor(ctx context.Context, metadataConditions *Metadata,
+	conditions *map[string]interface{}, cursorParams *CursorQueryParams, opts ...ModelOps,
+) ([]*Transaction, string, error) {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "get_transactions_with_cursor")
+
+	// Get the transactions
+	transactions, nextCursor, err := getModelsByCursor[*Transaction](
+		ctx, ModelTransaction, metadataConditions, conditions, cursorParams,
+		c.DefaultModelOptions(opts...)...,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err = loadTransactionBUMPs(ctx, transactions, c.DefaultModelOptions()...); err != nil {
+		return nil, "", err
+	}
+
+	return transactions, nextCursor, nil
+}
+
+// IterateTransactions will stream all the transactions matching the conditions to the given function
+//
+// The transactions are read in batches (see WithPageSize()) to keep the memory bounded, which makes this
+// usable for exports, audits and migrations. The iteration stops on the first error returned by fn
+func (c *Client) IterateTransactions(ctx context.Context, conditions *map[string]interface{},
+	fn func(*Transaction) error, opts ...ModelOps,
+) error {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "iterate_transactions")
+
+	return iterateModelBatches[*Transaction](
+		ctx, ModelTransaction, conditions, func(transactions []*Transaction) error {
+			if err := loadTransactionBUMPs(ctx, transactions, c.DefaultModelOptions()...); err != nil {
+				return err
+			}
+			for _, transaction := range transactions {
+				if err := fn(transaction); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, c.DefaultModelOptions(opts...)...,
+	)
+}
+
+// MigrateLegacyMerkleProofs will backfill the BUMP of all the mined transactions which only have a legacy
+// TSC merkle proof (converted), an inline BUMP (moved to the block BUMP), or neither (queried again through chainstate)
+//
+// The transactions which cannot be resolved get the "unresolved" block BUMP ID and are skipped by the next runs
+func (c *Client) MigrateLegacyMerkleProofs(ctx context.Context) (*MerkleProofMigration, error) {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "migrate_legacy_merkle_proofs")
+
+	return migrateLegacyMerkleProofs(ctx, c, c.DefaultModelOptions()...)
+}
+
 // GetTransactionsCount will get a count of all the transactions from the Datastore
 func (c *Client) GetTransactionsCount(ctx context.Context, metadataConditions *Metadata,
 	conditions *map[string]interface{}, opts ...ModelOps,
@@ -273,7 +382,10 @@ func (c *Client) RevertTransaction(ctx context.Context, id string) error {
 		return errors.New("could not find the draft transaction for this transaction, cannot revert")
 	}
 
-	// check whether transaction is not already on chain
+	// check whether transaction is not already on chain (impossible without a transaction lookup)
+	if c.IsSPVOnlyEnabled() {
+		return ErrTransactionUnverifiable
+	}
 	var info *chainstate.TransactionInfo
 	if info, err = c.Chainstate().QueryTransaction(ctx, transaction.ID, chainstate.RequiredInMempool, 30*time.Second); err != nil {
 		if !errors.Is(err, chainstate.ErrTransactionNotFound) {
@@ -411,6 +523,10 @@ func (c *Client) UpdateTransaction(ctx context.Context, callbackResp *broadcast.
 	if err != nil {
 		c.options.logger.Err(err).Msgf("failed to get sync transaction by tx id: %v", txInfo.ID)
 		return err
+	} else if syncTx == nil {
+		// the transaction is not synced by bux (no sync transaction)
+		tx.setChainInfo(txInfo)
+		return tx.Save(ctx)
 	}
 
 	return processSyncTxSave(ctx, txInfo, syncTx, tx)
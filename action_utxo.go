@@ -29,6 +29,45 @@ func (c *Client) GetUtxos(ctx context.Context, metadataConditions *Metadata,
 	return utxos, nil
 }
 
+// GetUtxosWithCursor will get a page of utxos from the Datastore using cursor pagination
+//
+// Returns the next cursor (empty if there are no more utxos)
+func (c *Client) GetUtxosWithCursor(ctx context.Context, metadataConditions *Metadata,
+	conditions *map[string]interface{}, cursorParams *CursorQueryParams, opts ...ModelOps,
+) ([]*Utxo, string, error) {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "get_utxos_with_cursor")
+
+	// Get the utxos
+	utxos, nextCursor, err := getModelsByCursor[*Utxo](
+		ctx, ModelUtxo, metadataConditions, conditions, cursorParams,
+		c.DefaultModelOptions(opts...)...,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// add the transaction linked to the utxos
+	c.enrichUtxoTransactions(ctx, utxos)
+
+	return utxos, nextCursor, nil
+}
+
+// IterateUtxos will stream all the utxos matching the conditions to the given function
+//
+// The utxos are read in batches (see WithPageSize()) to keep the memory bounded, which makes this
+// usable for exports, audits and migrations. The iteration stops on the first error returned by fn
+func (c *Client) IterateUtxos(ctx context.Context, conditions *map[string]interface{},
+	fn func(*Utxo) error, opts ...ModelOps,
+) error {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "iterate_utxos")
+
+	return iterateModels[*Utxo](
+		ctx, ModelUtxo, conditions, fn, c.DefaultModelOptions(opts...)...,
+	)
+}
+
 // GetUtxosCount will get a count of all the utxos from the Datastore
 func (c *Client) GetUtxosCount(ctx context.Context, metadataConditions *Metadata,
 	conditions *map[string]interface{}, opts ...ModelOps,
@@ -2,7 +2,9 @@ package bux
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/BuxOrg/bux/utils"
 	"github.com/mrz1836/go-datastore"
 )
 
@@ -109,6 +111,27 @@ func (c *Client) GetXPubs(ctx context.Context, metadataConditions *Metadata,
 	return xPubs, nil
 }
 
+// GetXPubsWithCursor will get a page of xpubs from the Datastore using cursor pagination
+//
+// Returns the next cursor (empty if there are no more xpubs)
+func (c *Client) GetXPubsWithCursor(ctx context.Context, metadataConditions *Metadata,
+	conditions *map[string]interface{}, cursorParams *CursorQueryParams, opts ...ModelOps,
+) ([]*Xpub, string, error) {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "get_xpubs_with_cursor")
+
+	// Get the xpubs
+	xPubs, nextCursor, err := getModelsByCursor[*Xpub](
+		ctx, ModelXPub, metadataConditions, conditions, cursorParams,
+		c.DefaultModelOptions(opts...)...,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return xPubs, nextCursor, nil
+}
+
 // GetXPubsCount gets a count of all xpubs matching the conditions
 func (c *Client) GetXPubsCount(ctx context.Context, metadataConditions *Metadata,
 	conditions *map[string]interface{}, opts ...ModelOps) (int64, error) {
@@ -126,3 +149,113 @@ func (c *Client) GetXPubsCount(ctx context.Context, metadataConditions *Metadata
 
 	return count, nil
 }
+
+// RescanXpub will recover the past transactions and utxos of an existing xPub from the address history provider
+//
+// The external and internal addresses are scanned until gapLimit consecutive addresses are unused (0: default 20),
+// the destinations of the used addresses are created and the next nums of the xPub are advanced past them
+func (c *Client) RescanXpub(ctx context.Context, xPubKey string, gapLimit uint32) (*XpubRescan, error) {
+	if c.options.chainstate.addressHistory == nil {
+		return nil, ErrMissingAddressHistoryProvider
+	}
+	if gapLimit == 0 {
+		gapLimit = defaultGapLimit
+	}
+
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "rescan_xpub")
+
+	hdKey, err := utils.ValidateXPub(xPubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the lock and set the release for after the function completes
+	unlock, err := newWaitWriteLock(
+		ctx, fmt.Sprintf(lockKeyProcessXpub, utils.Hash(xPubKey)), c.Cachestore(),
+	)
+	defer unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := c.DefaultModelOptions()
+	xPub, err := getXpubWithCache(ctx, c, xPubKey, "", opts...)
+	if err != nil {
+		return nil, err
+	} else if xPub == nil {
+		return nil, ErrMissingXpub
+	}
+
+	// Request the history of the addresses
+	scan := &xpubScan{
+		gapLimit:     gapLimit,
+		hdKey:        hdKey,
+		lastExternal: -1,
+		lastInternal: -1,
+		provider:     c.options.chainstate.addressHistory,
+		transactions: make(map[string]*AddressHistoryTransaction),
+	}
+	if err = scan.scanAddresses(ctx); err != nil {
+		return nil, err
+	}
+
+	// Create the destinations of the used addresses (the utxos are only recorded for known destinations)
+	for _, chain := range []struct {
+		chain    uint32
+		lastUsed int64
+	}{{utils.ChainExternal, scan.lastExternal}, {utils.ChainInternal, scan.lastInternal}} {
+		for num := int64(0); num <= chain.lastUsed; num++ {
+			if err = c.saveRescannedDestination(ctx, xPubKey, chain.chain, uint32(num)); err != nil {
+				return nil, err
+			}
+		}
+		if err = xPub.advanceNextNum(ctx, chain.chain, uint32(chain.lastUsed+1)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Record the transactions (parents first)
+	var history []*AddressHistoryTransaction
+	if history, err = scan.sortedTransactions(); err != nil {
+		return nil, err
+	}
+	result := &XpubRescan{ScannedAddresses: scan.scanned}
+	for _, historyTx := range history {
+		var tx *Transaction
+		if tx, err = getTransactionByID(ctx, "", historyTx.ID, opts...); err != nil {
+			return nil, err
+		} else if tx != nil {
+			// The destinations created above could be unknown when the transaction was recorded
+			if err = tx.processRescannedUtxos(ctx); err != nil {
+				return nil, err
+			}
+			result.Skipped++
+			continue
+		}
+		if _, err = saveHistoryTransaction(ctx, c, historyTx); err != nil {
+			return nil, err
+		}
+		result.Transactions++
+	}
+
+	result.NextExternalNum = xPub.NextExternalNum
+	result.NextInternalNum = xPub.NextInternalNum
+	return result, nil
+}
+
+// saveRescannedDestination will save the destination of the xPub at the given chain and num (if not known yet)
+func (c *Client) saveRescannedDestination(ctx context.Context, xPubKey string, chain, num uint32) error {
+	destination, err := newAddress(xPubKey, chain, num, c.DefaultModelOptions(New())...)
+	if err != nil {
+		return err
+	}
+
+	var existing *Destination
+	if existing, err = getDestinationByID(ctx, destination.ID, c.DefaultModelOptions()...); err != nil {
+		return err
+	} else if existing != nil {
+		return nil
+	}
+	return destination.Save(ctx)
+}
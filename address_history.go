@@ -0,0 +1,211 @@
+package bux
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/BuxOrg/bux/utils"
+	"github.com/libsv/go-bk/bip32"
+	"github.com/libsv/go-bt/v2"
+)
+
+// defaultGapLimit is the number of consecutive unused addresses ending a rescan (BIP-44)
+const defaultGapLimit = uint32(20)
+
+// AddressHistoryProvider is a provider of the past transactions of the addresses (IE: an indexer)
+type AddressHistoryProvider interface {
+	// GetAddressHistory will return the transactions paying to or spending from the address
+	// (empty if the address was never used)
+	GetAddressHistory(ctx context.Context, address string) ([]*AddressHistoryTransaction, error)
+}
+
+// AddressHistoryTransaction is a transaction of the history of an address
+type AddressHistoryTransaction struct {
+	BlockHash   string `json:"block_hash"`   // Empty if not mined yet
+	BlockHeight uint64 `json:"block_height"` // Zero if not mined yet
+	Hex         string `json:"hex"`          // Raw transaction hex
+	ID          string `json:"id"`           // Transaction ID
+}
+
+// XpubRescan is the result of a rescan of an xPub (see RescanXpub)
+type XpubRescan struct {
+	NextExternalNum  uint32 `json:"next_external_num"` // Next external num after the rescan
+	NextInternalNum  uint32 `json:"next_internal_num"` // Next internal num after the rescan
+	ScannedAddresses int    `json:"scanned_addresses"` // Number of addresses requested from the provider
+	Skipped          int    `json:"skipped"`           // Transactions of the history which were already recorded
+	Transactions     int    `json:"transactions"`      // Transactions of the history which were recorded
+}
+
+// addressHistoryFile is the content of a file-backed address history
+type addressHistoryFile struct {
+	Addresses    map[string][]string                   `json:"addresses"`    // Address to the ids of its transactions
+	Transactions map[string]*AddressHistoryTransaction `json:"transactions"` // Transaction ID to the transaction
+}
+
+// fileAddressHistory is an address history loaded from a JSON file (stand-in for an indexer)
+type fileAddressHistory struct {
+	history addressHistoryFile
+}
+
+// NewAddressHistoryFromFile will load an address history from a JSON file, IE: an export of an indexer
+//
+// The file contains the transactions by id, and the transaction ids of each address:
+// {"addresses": {"<address>": ["<tx id>"]}, "transactions": {"<tx id>": {"hex": "...", "block_height": 1}}}
+func NewAddressHistoryFromFile(path string) (AddressHistoryProvider, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // the path is given by the configuration
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &fileAddressHistory{}
+	if err = json.Unmarshal(data, &provider.history); err != nil {
+		return nil, err
+	}
+	for id, tx := range provider.history.Transactions {
+		if tx == nil || len(tx.Hex) == 0 {
+			return nil, ErrMissingTxHex
+		}
+		tx.ID = id
+	}
+	return provider, nil
+}
+
+// GetAddressHistory will return the transactions of the address in the file
+func (f *fileAddressHistory) GetAddressHistory(_ context.Context, address string) ([]*AddressHistoryTransaction, error) {
+	txIDs := f.history.Addresses[address]
+	history := make([]*AddressHistoryTransaction, 0, len(txIDs))
+	for _, txID := range txIDs {
+		tx, ok := f.history.Transactions[txID]
+		if !ok {
+			return nil, ErrMissingTransaction
+		}
+		history = append(history, tx)
+	}
+	return history, nil
+}
+
+// xpubScan is the state of the scan of an xPub
+type xpubScan struct {
+	gapLimit     uint32
+	hdKey        *bip32.ExtendedKey
+	lastExternal int64 // last used external num (-1: none)
+	lastInternal int64 // last used internal num (-1: none)
+	provider     AddressHistoryProvider
+	scanned      int
+	transactions map[string]*AddressHistoryTransaction
+}
+
+// scanAddresses will request the history of the external and internal addresses until the gap limit is reached
+func (s *xpubScan) scanAddresses(ctx context.Context) error {
+	for num := uint32(0); ; num++ {
+		externalDone := int64(num)-s.lastExternal > int64(s.gapLimit)
+		internalDone := int64(num)-s.lastInternal > int64(s.gapLimit)
+		if externalDone && internalDone {
+			return nil
+		}
+
+		external, internal, err := utils.DeriveAddresses(s.hdKey, num)
+		if err != nil {
+			return err
+		}
+
+		if !externalDone {
+			var used bool
+			if used, err = s.scanAddress(ctx, external); err != nil {
+				return err
+			} else if used {
+				s.lastExternal = int64(num)
+			}
+		}
+		if !internalDone {
+			var used bool
+			if used, err = s.scanAddress(ctx, internal); err != nil {
+				return err
+			} else if used {
+				s.lastInternal = int64(num)
+			}
+		}
+	}
+}
+
+// scanAddress will add the history of the address, returns true if the address was used
+func (s *xpubScan) scanAddress(ctx context.Context, address string) (bool, error) {
+	s.scanned++
+	history, err := s.provider.GetAddressHistory(ctx, address)
+	if err != nil {
+		return false, err
+	}
+	for _, tx := range history {
+		s.transactions[tx.ID] = tx
+	}
+	return len(history) > 0, nil
+}
+
+// sortedTransactions will return the transactions of the history in chain order (parents before children)
+func (s *xpubScan) sortedTransactions() ([]*AddressHistoryTransaction, error) {
+	type historyTx struct {
+		tx      *AddressHistoryTransaction
+		parents []string
+	}
+
+	pending := make([]*historyTx, 0, len(s.transactions))
+	for _, tx := range s.transactions {
+		parsed, err := bt.NewTxFromString(tx.Hex)
+		if err != nil {
+			return nil, err
+		}
+		htx := &historyTx{tx: tx}
+		for _, input := range parsed.Inputs {
+			htx.parents = append(htx.parents, hex.EncodeToString(input.PreviousTxID()))
+		}
+		pending = append(pending, htx)
+	}
+
+	// Mined transactions by height, then the unmined transactions
+	height := func(tx *AddressHistoryTransaction) uint64 {
+		if len(tx.BlockHash) == 0 {
+			return math.MaxUint64
+		}
+		return tx.BlockHeight
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		if height(pending[i].tx) != height(pending[j].tx) {
+			return height(pending[i].tx) < height(pending[j].tx)
+		}
+		return pending[i].tx.ID < pending[j].tx.ID
+	})
+
+	// Parents of the same block are moved before their children
+	sorted := make([]*AddressHistoryTransaction, 0, len(pending))
+	added := make(map[string]bool, len(pending))
+	for len(pending) > 0 {
+		next := pending[0]
+		for _, candidate := range pending {
+			ready := true
+			for _, parent := range candidate.parents {
+				if _, inHistory := s.transactions[parent]; inHistory && !added[parent] && parent != candidate.tx.ID {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				next = candidate
+				break
+			}
+		}
+
+		sorted = append(sorted, next.tx)
+		added[next.tx.ID] = true
+		for i := range pending {
+			if pending[i] == next {
+				pending = append(pending[:i], pending[i+1:]...)
+				break
+			}
+		}
+	}
+	return sorted, nil
+}
@@ -0,0 +1,167 @@
+package bux
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BuxOrg/bux/utils"
+	"github.com/libsv/go-bt/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_RescanXpub will test the method RescanXpub()
+func TestClient_RescanXpub(t *testing.T) {
+	hdKey, err := utils.ValidateXPub(testXPub)
+	require.NoError(t, err)
+	deriveAddress := func(chain, num uint32) string {
+		address, deriveErr := utils.DeriveAddress(hdKey, chain, num)
+		require.NoError(t, deriveErr)
+		return address
+	}
+	external0 := deriveAddress(utils.ChainExternal, 0)
+	external2 := deriveAddress(utils.ChainExternal, 2)
+	internal1 := deriveAddress(utils.ChainInternal, 1)
+
+	// funding to the external addresses 0 & 2, then a payment from the external address 0 with change
+	funding := bt.NewTx()
+	require.NoError(t, funding.From(testTxID, 0, testLockingScript, 20000))
+	require.NoError(t, funding.PayToAddress(external0, 10000))
+	require.NoError(t, funding.PayToAddress(external2, 5000))
+	payment := bt.NewTx()
+	require.NoError(t, payment.From(funding.TxID(), 0, testLockingScript, 10000))
+	require.NoError(t, payment.PayToAddress(internal1, 9000))
+	require.NoError(t, payment.PayToAddress("1A1PjKqjWMNBzTVdcBru27EV1PHcXWc63W", 900))
+
+	history := addressHistoryFile{
+		Addresses: map[string][]string{
+			external0: {payment.TxID(), funding.TxID()},
+			external2: {funding.TxID()},
+			internal1: {payment.TxID()},
+		},
+		Transactions: map[string]*AddressHistoryTransaction{
+			funding.TxID(): {Hex: funding.String(), BlockHash: "0000000000000000031fa2c1d7a3d1b7e3ad4e8f1b3f5d6c4e2a1b0c9d8e7f6a", BlockHeight: 800000},
+			payment.TxID(): {Hex: payment.String()},
+		},
+	}
+	data, err := json.Marshal(history)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "history.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	provider, err := NewAddressHistoryFromFile(path)
+	require.NoError(t, err)
+
+	t.Run("missing provider", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup())
+		defer deferMe()
+
+		_, err = client.RescanXpub(ctx, testXPub, 0)
+		require.ErrorIs(t, err, ErrMissingAddressHistoryProvider)
+	})
+
+	t.Run("missing xPub", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+			WithAddressHistoryProvider(provider))
+		defer deferMe()
+
+		_, err = client.RescanXpub(ctx, testXPub, 0)
+		require.ErrorIs(t, err, ErrMissingXpub)
+	})
+
+	t.Run("recover the transactions and utxos", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+			WithAddressHistoryProvider(provider))
+		defer deferMe()
+
+		_, err = client.NewXpub(ctx, testXPub, client.DefaultModelOptions()...)
+		require.NoError(t, err)
+
+		var result *XpubRescan
+		result, err = client.RescanXpub(ctx, testXPub, 3)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(3), result.NextExternalNum)
+		assert.Equal(t, uint32(2), result.NextInternalNum)
+		assert.Equal(t, 2, result.Transactions)
+		assert.Equal(t, 0, result.Skipped)
+		assert.Equal(t, 6+5, result.ScannedAddresses) // gap of 3 after the external 2 and after the internal 1
+
+		var xPub *Xpub
+		xPub, err = client.GetXpub(ctx, testXPub)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(14000), xPub.CurrentBalance)
+		assert.Equal(t, uint32(3), xPub.NextExternalNum)
+		assert.Equal(t, uint32(2), xPub.NextInternalNum)
+
+		var utxo *Utxo
+		utxo, err = client.GetUtxoByTransactionID(ctx, funding.TxID(), 0)
+		require.NoError(t, err)
+		assert.Equal(t, payment.TxID(), utxo.SpendingTxID.String)
+		utxo, err = client.GetUtxoByTransactionID(ctx, funding.TxID(), 1)
+		require.NoError(t, err)
+		assert.False(t, utxo.SpendingTxID.Valid)
+		utxo, err = client.GetUtxoByTransactionID(ctx, payment.TxID(), 0)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(9000), utxo.Satoshis)
+
+		var tx *Transaction
+		tx, err = client.GetTransaction(ctx, "", funding.TxID())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(800000), tx.BlockHeight)
+
+		// the next rescan only skips the recorded transactions
+		result, err = client.RescanXpub(ctx, testXPub, 3)
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Transactions)
+		assert.Equal(t, 2, result.Skipped)
+
+		xPub, err = client.GetXpub(ctx, testXPub)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(14000), xPub.CurrentBalance)
+	})
+	t.Run("recover the utxos of the recorded transactions", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+			WithAddressHistoryProvider(provider))
+		defer deferMe()
+
+		_, err = client.NewXpub(ctx, testXPub, client.DefaultModelOptions()...)
+		require.NoError(t, err)
+
+		// the funding was recorded when only the external address 0 was known
+		require.NoError(t, client.(*Client).saveRescannedDestination(ctx, testXPub, utils.ChainExternal, 0))
+		_, err = saveRawTransaction(ctx, client, false, funding.String())
+		require.NoError(t, err)
+
+		var result *XpubRescan
+		result, err = client.RescanXpub(ctx, testXPub, 3)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Transactions)
+		assert.Equal(t, 1, result.Skipped)
+
+		var xPub *Xpub
+		xPub, err = client.GetXpub(ctx, testXPub)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(14000), xPub.CurrentBalance)
+
+		var utxo *Utxo
+		utxo, err = client.GetUtxoByTransactionID(ctx, funding.TxID(), 1)
+		require.NoError(t, err)
+		require.NotNil(t, utxo)
+		assert.Equal(t, uint64(5000), utxo.Satoshis)
+		assert.False(t, utxo.SpendingTxID.Valid)
+
+		var tx *Transaction
+		tx, err = client.GetTransaction(ctx, "", funding.TxID())
+		require.NoError(t, err)
+		assert.Equal(t, int64(15000), tx.XpubOutputValue[xPub.ID])
+
+		// the next rescan does not count the utxos again
+		_, err = client.RescanXpub(ctx, testXPub, 3)
+		require.NoError(t, err)
+		xPub, err = client.GetXpub(ctx, testXPub)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(14000), xPub.CurrentBalance)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/BuxOrg/bux/utils"
 	"github.com/libsv/go-bt/v2"
 )
 
@@ -62,9 +63,20 @@ func prepareBEEFFactors(ctx context.Context, tx *Transaction, store TransactionG
 		return nil, nil, err
 	}
 
-	txIDs := make([]string, 0, len(tx.draftTransaction.Configuration.Inputs))
-	for _, input := range tx.draftTransaction.Configuration.Inputs {
-		txIDs = append(txIDs, input.UtxoPointer.TransactionID)
+	var txIDs []string
+	if tx.draftTransaction != nil {
+		txIDs = make([]string, 0, len(tx.draftTransaction.Configuration.Inputs))
+		for _, input := range tx.draftTransaction.Configuration.Inputs {
+			txIDs = append(txIDs, input.UtxoPointer.TransactionID)
+		}
+	} else {
+		// transactions not created from a draft (received)
+		txIDs = make([]string, 0, len(btTxsNeededForBUMP[0].Inputs))
+		for _, input := range btTxsNeededForBUMP[0].Inputs {
+			if txID := input.PreviousTxIDStr(); !utils.StringInSlice(txID, txIDs) {
+				txIDs = append(txIDs, txID)
+			}
+		}
 	}
 
 	inputTxs, err := getRequiredTransactions(ctx, txIDs, store)
@@ -10,38 +10,87 @@ import (
 
 const maxBeefVer = uint32(0xFFFF) // value from BRC-62
 
+// Versions of BEEF
+const (
+	beefVersion1 = uint32(1) // BRC-62
+	beefVersion2 = uint32(2) // BRC-96
+)
+
 type beefTx struct {
 	version      uint32
 	bumps        BUMPs
 	transactions []*bt.Tx
+	knownTxIDs   map[string]bool // BEEF V2: transactions known by the receiver, encoded as txid only
 }
 
 // ToBeef generates BEEF Hex for transaction
 func ToBeef(ctx context.Context, tx *Transaction, store TransactionGetter) (string, error) {
-	if err := hydrateTransaction(ctx, tx); err != nil {
+	bumps, sortedTxs, err := prepareBEEF(ctx, tx, store)
+	if err != nil {
 		return "", err
 	}
 
-	bumpBtFactors, bumpFactors, err := prepareBEEFFactors(ctx, tx, store)
+	beefHex, err := toBeefHex(bumps, sortedTxs)
 	if err != nil {
-		return "", fmt.Errorf("prepareBUMPFactors() error: %w", err)
+		return "", fmt.Errorf("ToBeef() error: %w", err)
 	}
 
-	bumps, err := calculateMergedBUMP(bumpFactors)
+	return beefHex, nil
+}
+
+// ToBeefV2 generates BEEF V2 Hex for transaction
+//
+// The ancestors known by the receiver (knownTxIDs) are encoded as txid only
+func ToBeefV2(ctx context.Context, tx *Transaction, store TransactionGetter, knownTxIDs ...string) (string, error) {
+	bumps, sortedTxs, err := prepareBEEF(ctx, tx, store)
 	if err != nil {
 		return "", err
 	}
-	sortedTxs := kahnTopologicalSortTransactions(bumpBtFactors)
-	beefHex, err := toBeefHex(bumps, sortedTxs)
+
+	beefHex, err := toBeefV2Hex(bumps, sortedTxs, knownTxIDs)
 	if err != nil {
-		return "", fmt.Errorf("ToBeef() error: %w", err)
+		return "", fmt.Errorf("ToBeefV2() error: %w", err)
 	}
 
 	return beefHex, nil
 }
 
+// ToAtomicBeef generates Atomic BEEF Hex for transaction (BEEF framed with the subject transaction ID)
+func ToAtomicBeef(ctx context.Context, tx *Transaction, store TransactionGetter) (string, error) {
+	bumps, sortedTxs, err := prepareBEEF(ctx, tx, store)
+	if err != nil {
+		return "", err
+	}
+
+	beefHex, err := toAtomicBeefHex(beefVersion1, bumps, sortedTxs, nil)
+	if err != nil {
+		return "", fmt.Errorf("ToAtomicBeef() error: %w", err)
+	}
+
+	return beefHex, nil
+}
+
+// prepareBEEF will get the merged BUMPs and the sorted transactions (subject transaction last) for the BEEF
+func prepareBEEF(ctx context.Context, tx *Transaction, store TransactionGetter) (BUMPs, []*bt.Tx, error) {
+	if err := hydrateTransaction(ctx, tx); err != nil {
+		return nil, nil, err
+	}
+
+	bumpBtFactors, bumpFactors, err := prepareBEEFFactors(ctx, tx, store)
+	if err != nil {
+		return nil, nil, fmt.Errorf("prepareBUMPFactors() error: %w", err)
+	}
+
+	bumps, err := calculateMergedBUMP(bumpFactors)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return bumps, kahnTopologicalSortTransactions(bumpBtFactors), nil
+}
+
 func toBeefHex(bumps BUMPs, parentTxs []*bt.Tx) (string, error) {
-	beef, err := newBeefTx(1, bumps, parentTxs)
+	beef, err := newBeefTx(beefVersion1, bumps, parentTxs, nil)
 	if err != nil {
 		return "", fmt.Errorf("ToBeefHex() error: %w", err)
 	}
@@ -54,13 +103,47 @@ func toBeefHex(bumps BUMPs, parentTxs []*bt.Tx) (string, error) {
 	return hex.EncodeToString(beefBytes), nil
 }
 
-func newBeefTx(version uint32, bumps BUMPs, parentTxs []*bt.Tx) (*beefTx, error) {
+func toBeefV2Hex(bumps BUMPs, parentTxs []*bt.Tx, knownTxIDs []string) (string, error) {
+	beef, err := newBeefTx(beefVersion2, bumps, parentTxs, knownTxIDs)
+	if err != nil {
+		return "", fmt.Errorf("ToBeefV2Hex() error: %w", err)
+	}
+
+	beefBytes, err := beef.toBeefBytes()
+	if err != nil {
+		return "", fmt.Errorf("ToBeefV2Hex() error: %w", err)
+	}
+
+	return hex.EncodeToString(beefBytes), nil
+}
+
+func toAtomicBeefHex(version uint32, bumps BUMPs, parentTxs []*bt.Tx, knownTxIDs []string) (string, error) {
+	beef, err := newBeefTx(version, bumps, parentTxs, knownTxIDs)
+	if err != nil {
+		return "", fmt.Errorf("ToAtomicBeefHex() error: %w", err)
+	}
+
+	beefBytes, err := beef.toAtomicBeefBytes()
+	if err != nil {
+		return "", fmt.Errorf("ToAtomicBeefHex() error: %w", err)
+	}
+
+	return hex.EncodeToString(beefBytes), nil
+}
+
+func newBeefTx(version uint32, bumps BUMPs, parentTxs []*bt.Tx, knownTxIDs []string) (*beefTx, error) {
 	if version > maxBeefVer {
 		return nil, fmt.Errorf("version above 0x%X", maxBeefVer)
 	}
+	if len(knownTxIDs) > 0 && version != beefVersion2 {
+		return nil, fmt.Errorf("txid only transactions are not supported in BEEF version %d", version)
+	}
 
-	if err := validateBumps(bumps); err != nil {
-		return nil, err
+	// all the ancestors can be known by the receiver in BEEF V2
+	if len(knownTxIDs) == 0 || len(bumps) > 0 {
+		if err := validateBumps(bumps); err != nil {
+			return nil, err
+		}
 	}
 
 	beef := &beefTx{
@@ -69,11 +152,19 @@ func newBeefTx(version uint32, bumps BUMPs, parentTxs []*bt.Tx) (*beefTx, error)
 		transactions: parentTxs,
 	}
 
+	if len(knownTxIDs) > 0 {
+		beef.knownTxIDs = make(map[string]bool, len(knownTxIDs))
+		for _, txID := range knownTxIDs {
+			beef.knownTxIDs[txID] = true
+		}
+	}
+
 	return beef, nil
 }
 
 func hydrateTransaction(ctx context.Context, tx *Transaction) error {
-	if tx.draftTransaction == nil {
+	// received transactions are not created from a draft
+	if tx.draftTransaction == nil && tx.DraftID != "" {
 		dTx, err := getDraftTransactionID(
 			ctx, tx.XPubID, tx.DraftID, tx.GetOptions(false)...,
 		)
@@ -9,12 +9,40 @@ import (
 var (
 	hasBUMP   = byte(0x01)
 	hasNoBUMP = byte(0x00)
+
+	// formats of the transactions in BEEF V2
+	rawTx         = byte(0x00)
+	rawTxWithBUMP = byte(0x01)
+	txIDOnly      = byte(0x02)
+
+	// atomicBeefPrefix is the prefix of Atomic BEEF (BRC-95)
+	atomicBeefPrefix = []byte{0x01, 0x01, 0x01, 0x01}
 )
 
+func (beefTx *beefTx) toAtomicBeefBytes() ([]byte, error) {
+	beefBytes, err := beefTx.toBeefBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	// the subject transaction is the last one (kahn's ordering)
+	subjectTxID := beefTx.transactions[len(beefTx.transactions)-1].TxIDBytes()
+
+	buffer := make([]byte, 0, len(atomicBeefPrefix)+len(subjectTxID)+len(beefBytes))
+	buffer = append(buffer, atomicBeefPrefix...)
+	buffer = append(buffer, bt.ReverseBytes(subjectTxID)...)
+	buffer = append(buffer, beefBytes...)
+
+	return buffer, nil
+}
+
 func (beefTx *beefTx) toBeefBytes() ([]byte, error) {
-	if len(beefTx.bumps) == 0 || len(beefTx.transactions) < 2 { // valid BEEF contains at least two transactions (new transaction and one parent transaction)
+	if (len(beefTx.bumps) == 0 && len(beefTx.knownTxIDs) == 0) || len(beefTx.transactions) < 2 { // valid BEEF contains at least two transactions (new transaction and one parent transaction)
 		return nil, errors.New("beef tx is incomplete")
 	}
+	if beefTx.knownTxIDs[beefTx.transactions[len(beefTx.transactions)-1].TxID()] {
+		return nil, errors.New("subject transaction cannot be txid only")
+	}
 
 	// get beef bytes
 	beefSize := 0
@@ -36,7 +64,12 @@ func (beefTx *beefTx) toBeefBytes() ([]byte, error) {
 	transactions := make([][]byte, 0, len(beefTx.transactions))
 
 	for _, t := range beefTx.transactions {
-		txBytes := toBeefBytes(t, beefTx.bumps)
+		var txBytes []byte
+		if beefTx.version == beefVersion2 {
+			txBytes = toBeefV2Bytes(t, beefTx.bumps, beefTx.knownTxIDs)
+		} else {
+			txBytes = toBeefBytes(t, beefTx.bumps)
+		}
 		transactions = append(transactions, txBytes)
 		beefSize += len(txBytes)
 	}
@@ -70,6 +103,20 @@ func toBeefBytes(tx *bt.Tx, bumps BUMPs) []byte {
 	return txBeefBytes
 }
 
+func toBeefV2Bytes(tx *bt.Tx, bumps BUMPs, knownTxIDs map[string]bool) []byte {
+	if knownTxIDs[tx.TxID()] {
+		return append([]byte{txIDOnly}, bt.ReverseBytes(tx.TxIDBytes())...)
+	}
+
+	bumpIdx := getBumpPathIndex(tx, bumps)
+	if bumpIdx > -1 {
+		txBeefBytes := append([]byte{rawTxWithBUMP}, bt.VarInt(bumpIdx).Bytes()...)
+		return append(txBeefBytes, tx.Bytes()...)
+	}
+
+	return append([]byte{rawTx}, tx.Bytes()...)
+}
+
 func getBumpPathIndex(tx *bt.Tx, bumps BUMPs) int {
 	bumpIndex := -1
 
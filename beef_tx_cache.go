@@ -0,0 +1,130 @@
+package bux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/BuxOrg/bux/utils"
+	"github.com/mrz1836/go-cachestore"
+)
+
+// cachedTransactionBEEF is the BEEF of a transaction stored in the cachestore
+type cachedTransactionBEEF struct {
+	BEEF         string   `json:"beef"`           // BEEF hex
+	UnminedTxIDs []string `json:"unmined_tx_ids"` // Transactions of the BEEF without BUMP (subject transaction included)
+}
+
+// getTransactionBEEF will get the BEEF of the transaction, from the cachestore if found
+//
+// The ancestry is pruned at the first mined ancestor, the cached BEEF is invalidated once one of
+// its unmined transactions gets mined (see invalidateTransactionBEEFs)
+func getTransactionBEEF(ctx context.Context, client ClientInterface, tx *Transaction) (string, error) {
+	cacheKey := fmt.Sprintf(cacheKeyTransactionBEEF, tx.ID)
+
+	// Attempt to get from cache
+	cached := new(cachedTransactionBEEF)
+	if err := client.Cachestore().GetModel(ctx, cacheKey, cached); err != nil && !errors.Is(err, cachestore.ErrKeyNotFound) {
+		return "", err
+	} else if err == nil && len(cached.BEEF) > 0 {
+		return cached.BEEF, nil
+	}
+
+	bumps, sortedTxs, err := prepareBEEF(ctx, tx, client)
+	if err != nil {
+		return "", err
+	}
+
+	cached.BEEF, err = toBeefHex(bumps, sortedTxs)
+	if err != nil {
+		return "", fmt.Errorf("GetTransactionBEEF() error: %w", err)
+	}
+
+	// The transactions of the BEEF which are not mined, contained in none of the BUMPs
+	minedTxIDs := make([]string, 0)
+	for _, bump := range bumps {
+		for _, leaf := range bump.Path[0] {
+			if leaf.TxID {
+				minedTxIDs = append(minedTxIDs, leaf.Hash)
+			}
+		}
+	}
+	for _, btTx := range sortedTxs {
+		if txID := btTx.TxID(); !utils.StringInSlice(txID, minedTxIDs) {
+			cached.UnminedTxIDs = append(cached.UnminedTxIDs, txID)
+		}
+	}
+
+	// Save to cache, the BEEF is still valid if the invalidation cannot be registered (only not pruned)
+	for _, txID := range cached.UnminedTxIDs {
+		if err = addTransactionBEEFDependent(ctx, client, txID, tx.ID); err != nil {
+			client.Logger().Warn().
+				Str("txID", tx.ID).
+				Err(err).
+				Msg("could not register the BEEF invalidation")
+			return cached.BEEF, nil
+		}
+	}
+	if err = client.Cachestore().SetModel(ctx, cacheKey, cached, cacheTTLTransactionBEEF); err != nil {
+		client.Logger().Warn().
+			Str("txID", tx.ID).
+			Err(err).
+			Msg("could not cache the BEEF")
+	}
+
+	return cached.BEEF, nil
+}
+
+// addTransactionBEEFDependent will register the cached BEEF (dependentTxID) to be invalidated when the transaction is mined
+func addTransactionBEEFDependent(ctx context.Context, client ClientInterface, txID, dependentTxID string) error {
+	unlock, err := newWaitWriteLock(ctx, fmt.Sprintf(lockKeyTransactionBEEF, txID), client.Cachestore())
+	defer unlock()
+	if err != nil {
+		return err
+	}
+
+	cacheKey := fmt.Sprintf(cacheKeyTransactionBEEFDependents, txID)
+	dependents := make([]string, 0)
+	if err = client.Cachestore().GetModel(ctx, cacheKey, &dependents); err != nil && !errors.Is(err, cachestore.ErrKeyNotFound) {
+		return err
+	}
+	if utils.StringInSlice(dependentTxID, dependents) {
+		return nil
+	}
+
+	return client.Cachestore().SetModel(ctx, cacheKey, append(dependents, dependentTxID), cacheTTLTransactionBEEF)
+}
+
+// invalidateTransactionBEEFs will remove the cached BEEFs containing the (now mined) transaction without its BUMP
+func invalidateTransactionBEEFs(ctx context.Context, client ClientInterface, txID string) error {
+	// Most of the transactions are in no cached BEEF, check before locking
+	cacheKey := fmt.Sprintf(cacheKeyTransactionBEEFDependents, txID)
+	if value, err := client.Cachestore().Get(ctx, cacheKey); err != nil {
+		return err
+	} else if len(value) == 0 {
+		return nil
+	}
+
+	unlock, err := newWaitWriteLock(ctx, fmt.Sprintf(lockKeyTransactionBEEF, txID), client.Cachestore())
+	defer unlock()
+	if err != nil {
+		return err
+	}
+
+	dependents := make([]string, 0)
+	if err = client.Cachestore().GetModel(ctx, cacheKey, &dependents); err != nil {
+		if errors.Is(err, cachestore.ErrKeyNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	for _, dependentTxID := range dependents {
+		if err = client.Cachestore().Delete(
+			ctx, fmt.Sprintf(cacheKeyTransactionBEEF, dependentTxID),
+		); err != nil {
+			return err
+		}
+	}
+	return client.Cachestore().Delete(ctx, cacheKey)
+}
@@ -0,0 +1,76 @@
+package bux
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/BuxOrg/bux/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_GetTransactionBEEF will test the method GetTransactionBEEF()
+func TestClient_GetTransactionBEEF(t *testing.T) {
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup())
+	defer deferMe()
+
+	// mined parent, received child (no draft) not mined yet
+	bumps, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+	parent, err := txFromHex(txs[0].String(), append(client.DefaultModelOptions(), New())...)
+	require.NoError(t, err)
+	parent.BUMP = *bumps[0]
+	parent.BlockHeight = bumps[0].BlockHeight
+	require.NoError(t, parent.Save(ctx))
+
+	var child *Transaction
+	child, err = txFromHex(txs[1].String(), append(client.DefaultModelOptions(), New())...)
+	require.NoError(t, err)
+	require.NoError(t, child.Save(ctx))
+
+	t.Run("missing transaction", func(t *testing.T) {
+		randomID, _ := utils.RandomHex(32)
+		_, err := client.GetTransactionBEEF(ctx, "", randomID)
+		require.ErrorIs(t, err, ErrMissingTransaction)
+	})
+
+	t.Run("pruned at the first mined ancestor", func(t *testing.T) {
+		beefHex, err := client.GetTransactionBEEF(ctx, "", child.ID)
+		require.NoError(t, err)
+
+		dBeef, _, err := decodeBEEFHex(beefHex)
+		require.NoError(t, err)
+		require.Len(t, dBeef.Transactions, 2)
+		require.Len(t, dBeef.BUMPs, 1)
+		assert.Equal(t, parent.ID, dBeef.Transactions[0].Transaction.TxID())
+		assert.Equal(t, child.ID, dBeef.Transactions[1].Transaction.TxID())
+
+		// the BEEF is cached
+		cached := new(cachedTransactionBEEF)
+		require.NoError(t, client.Cachestore().GetModel(ctx, fmt.Sprintf(cacheKeyTransactionBEEF, child.ID), cached))
+		assert.Equal(t, beefHex, cached.BEEF)
+		assert.Equal(t, []string{child.ID}, cached.UnminedTxIDs)
+	})
+
+	t.Run("invalidated when mined", func(t *testing.T) {
+		childBUMP, _ := createTestBlockBUMPs(t, child.ID)
+		childBUMP.BlockHeight++
+		child.BUMP = childBUMP
+		child.BlockHeight = childBUMP.BlockHeight
+		require.NoError(t, child.Save(ctx))
+
+		value, err := client.Cachestore().Get(ctx, fmt.Sprintf(cacheKeyTransactionBEEF, child.ID))
+		require.NoError(t, err)
+		assert.Empty(t, value)
+
+		var beefHex string
+		beefHex, err = client.GetTransactionBEEF(ctx, "", child.ID)
+		require.NoError(t, err)
+
+		dBeef, _, err := decodeBEEFHex(beefHex)
+		require.NoError(t, err)
+		require.Len(t, dBeef.Transactions, 2)
+		require.Len(t, dBeef.BUMPs, 2)
+		assert.Equal(t, child.ID, dBeef.Transactions[1].Transaction.TxID())
+		assert.NotNil(t, dBeef.Transactions[1].BumpIndex)
+	})
+}
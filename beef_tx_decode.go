@@ -0,0 +1,364 @@
+package bux
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/bitcoin-sv/go-paymail/beef"
+	"github.com/libsv/go-bt/v2"
+)
+
+const beefTxIDBytesCount = 32
+
+// decodeBEEFHex will decode BEEF (BRC-62), BEEF V2 (BRC-96) or Atomic BEEF (BRC-95)
+//
+// The txid only transactions of BEEF V2 are not part of the decoded BEEF, their ids are returned separately
+func decodeBEEFHex(beefHex string) (*beef.DecodedBEEF, []string, error) {
+	beefBytes, err := hex.DecodeString(beefHex)
+	if err != nil {
+		return nil, nil, errors.New("invalid beef hex stream")
+	}
+
+	// Atomic BEEF: prefix, subject txid and the BEEF
+	if bytes.HasPrefix(beefBytes, atomicBeefPrefix) {
+		return decodeAtomicBEEF(beefBytes[len(atomicBeefPrefix):])
+	}
+
+	return decodeBEEFBytes(beefBytes)
+}
+
+// decodeAtomicBEEF will decode the Atomic BEEF (without the prefix) and check it only contains the subject
+// transaction and its ancestors
+func decodeAtomicBEEF(beefBytes []byte) (*beef.DecodedBEEF, []string, error) {
+	if len(beefBytes) < beefTxIDBytesCount {
+		return nil, nil, errors.New("invalid atomic beef - missing subject txid")
+	}
+	subjectTxID := hex.EncodeToString(bt.ReverseBytes(beefBytes[:beefTxIDBytesCount]))
+
+	dBeef, knownTxIDs, err := decodeBEEFBytes(beefBytes[beefTxIDBytesCount:])
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(dBeef.Transactions) == 0 || dBeef.Transactions[len(dBeef.Transactions)-1].GetTxID() != subjectTxID {
+		return nil, nil, fmt.Errorf("invalid atomic beef - subject transaction %s is not the last transaction", subjectTxID)
+	}
+
+	// walk the ancestors of the subject transaction
+	txs := make(map[string]*bt.Tx, len(dBeef.Transactions))
+	for _, tx := range dBeef.Transactions {
+		txs[tx.GetTxID()] = tx.Transaction
+	}
+	ancestors := map[string]bool{subjectTxID: true}
+	queue := []*bt.Tx{txs[subjectTxID]}
+	for len(queue) > 0 {
+		tx := queue[0]
+		queue = queue[1:]
+		for _, input := range tx.Inputs {
+			parentID := input.PreviousTxIDStr()
+			if ancestors[parentID] {
+				continue
+			}
+			ancestors[parentID] = true
+			if parent, ok := txs[parentID]; ok {
+				queue = append(queue, parent)
+			}
+		}
+	}
+
+	for _, tx := range dBeef.Transactions {
+		if !ancestors[tx.GetTxID()] {
+			return nil, nil, fmt.Errorf("invalid atomic beef - transaction %s is not an ancestor of the subject", tx.GetTxID())
+		}
+	}
+	for _, txID := range knownTxIDs {
+		if !ancestors[txID] {
+			return nil, nil, fmt.Errorf("invalid atomic beef - transaction %s is not an ancestor of the subject", txID)
+		}
+	}
+
+	return dBeef, knownTxIDs, nil
+}
+
+// decodeBEEFBytes will decode BEEF (version 1) or BEEF V2 (version 2)
+func decodeBEEFBytes(beefBytes []byte) (*beef.DecodedBEEF, []string, error) {
+	if len(beefBytes) < 4 || beefBytes[2] != beef.BEEFMarkerPart1 || beefBytes[3] != beef.BEEFMarkerPart2 {
+		return nil, nil, errors.New("invalid beef - missing marker")
+	}
+
+	version := uint32(beefBytes[0]) | uint32(beefBytes[1])<<8
+	switch version {
+	case beefVersion1, beefVersion2:
+		return decodeBEEFVersion(version, beefBytes[4:])
+	default:
+		return nil, nil, fmt.Errorf("invalid beef - unsupported version %d", version)
+	}
+}
+
+// decodeBEEFVersion will decode the BEEF (without the version and the marker)
+//
+// BEEF V1 flags the BUMP index after each raw transaction, BEEF V2 sets a format before each transaction (raw,
+// raw with a BUMP index or txid only). The counts are untrusted: the slices are not preallocated from them,
+// a count past the bytes fails on the missing item
+func decodeBEEFVersion(version uint32, beefBytes []byte) (*beef.DecodedBEEF, []string, error) {
+	bumps, beefBytes, err := decodeBEEFBUMPs(beefBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	if version == beefVersion1 && len(bumps) == 0 {
+		return nil, nil, errors.New("invalid beef - missing bumps")
+	}
+
+	if len(beefBytes) == 0 {
+		return nil, nil, errors.New("invalid beef - missing transactions")
+	}
+	nTransactions, bytesUsed := bt.NewVarIntFromBytes(beefBytes)
+	beefBytes = beefBytes[bytesUsed:]
+
+	dBeef := &beef.DecodedBEEF{
+		BUMPs:        bumps,
+		Transactions: make([]*beef.TxData, 0),
+	}
+	knownTxIDs := make([]string, 0)
+	for i := uint64(0); i < uint64(nTransactions); i++ {
+		if len(beefBytes) == 0 {
+			return nil, nil, fmt.Errorf("invalid beef - missing transaction at index %d", i)
+		}
+		if version == beefVersion1 {
+			tx, bumpIndex, remainingBytes, err := decodeBEEFV1Tx(beefBytes, len(bumps), i)
+			if err != nil {
+				return nil, nil, err
+			}
+			beefBytes = remainingBytes
+			dBeef.Transactions = append(dBeef.Transactions, &beef.TxData{
+				Transaction: tx,
+				BumpIndex:   bumpIndex,
+			})
+			continue
+		}
+
+		format := beefBytes[0]
+		beefBytes = beefBytes[1:]
+
+		if format == txIDOnly {
+			if len(beefBytes) < beefTxIDBytesCount {
+				return nil, nil, fmt.Errorf("invalid beef - missing txid at index %d", i)
+			}
+			knownTxIDs = append(knownTxIDs, hex.EncodeToString(bt.ReverseBytes(beefBytes[:beefTxIDBytesCount])))
+			beefBytes = beefBytes[beefTxIDBytesCount:]
+			continue
+		}
+
+		var bumpIndex *bt.VarInt
+		switch format {
+		case rawTxWithBUMP:
+			if len(beefBytes) == 0 {
+				return nil, nil, fmt.Errorf("invalid beef - missing bump index at index %d", i)
+			}
+			index, bytesUsed := bt.NewVarIntFromBytes(beefBytes)
+			if uint64(index) >= uint64(len(bumps)) {
+				return nil, nil, fmt.Errorf("invalid beef - bump index %d out of range at index %d", index, i)
+			}
+			bumpIndex = &index
+			beefBytes = beefBytes[bytesUsed:]
+		case rawTx:
+		default:
+			return nil, nil, fmt.Errorf("invalid beef - unknown transaction format %d at index %d", format, i)
+		}
+
+		tx, bytesUsed, err := bt.NewTxFromStream(beefBytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		beefBytes = beefBytes[bytesUsed:]
+
+		dBeef.Transactions = append(dBeef.Transactions, &beef.TxData{
+			Transaction: tx,
+			BumpIndex:   bumpIndex,
+		})
+	}
+
+	if len(dBeef.Transactions) == 0 {
+		return nil, nil, errors.New("invalid beef - missing subject transaction")
+	}
+	if len(dBeef.Transactions)+len(knownTxIDs) < 2 {
+		return nil, nil, errors.New("invalid beef - not enough transactions")
+	}
+
+	return dBeef, knownTxIDs, nil
+}
+
+// decodeBEEFV1Tx will decode a BEEF V1 transaction (raw transaction and BUMP flag), returning the remaining bytes
+func decodeBEEFV1Tx(beefBytes []byte, nBUMPs int, i uint64) (*bt.Tx, *bt.VarInt, []byte, error) {
+	tx, bytesUsed, err := bt.NewTxFromStream(beefBytes)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	beefBytes = beefBytes[bytesUsed:]
+
+	if len(beefBytes) == 0 {
+		return nil, nil, nil, fmt.Errorf("invalid beef - missing bump flag at index %d", i)
+	}
+	flag := beefBytes[0]
+	beefBytes = beefBytes[1:]
+
+	switch flag {
+	case rawTx:
+		return tx, nil, beefBytes, nil
+	case rawTxWithBUMP:
+		if len(beefBytes) == 0 {
+			return nil, nil, nil, fmt.Errorf("invalid beef - missing bump index at index %d", i)
+		}
+		index, bytesUsed := bt.NewVarIntFromBytes(beefBytes)
+		if uint64(index) >= uint64(nBUMPs) {
+			return nil, nil, nil, fmt.Errorf("invalid beef - bump index %d out of range at index %d", index, i)
+		}
+		return tx, &index, beefBytes[bytesUsed:], nil
+	default:
+		return nil, nil, nil, fmt.Errorf("invalid beef - invalid bump flag %d at index %d", flag, i)
+	}
+}
+
+// decodeBEEFBUMPs will decode the BUMPs of the BEEF, returning the remaining bytes
+func decodeBEEFBUMPs(beefBytes []byte) (beef.BUMPs, []byte, error) {
+	if len(beefBytes) == 0 {
+		return nil, nil, errors.New("invalid beef - missing bumps")
+	}
+	nBUMPs, bytesUsed := bt.NewVarIntFromBytes(beefBytes)
+	beefBytes = beefBytes[bytesUsed:]
+
+	bumps := make(beef.BUMPs, 0)
+	for i := uint64(0); i < uint64(nBUMPs); i++ {
+		if len(beefBytes) < 2 {
+			return nil, nil, fmt.Errorf("invalid beef - missing bump %d", i)
+		}
+		blockHeight, bytesUsed := bt.NewVarIntFromBytes(beefBytes)
+		beefBytes = beefBytes[bytesUsed:]
+
+		if len(beefBytes) == 0 || int(beefBytes[0]) > maxBumpHeight {
+			return nil, nil, fmt.Errorf("invalid beef - invalid tree height of bump %d", i)
+		}
+		treeHeight := int(beefBytes[0])
+		beefBytes = beefBytes[1:]
+
+		bump := &beef.BUMP{
+			BlockHeight: uint64(blockHeight),
+			Path:        make([][]beef.BUMPLeaf, 0, treeHeight),
+		}
+		for level := 0; level < treeHeight; level++ {
+			if len(beefBytes) == 0 {
+				return nil, nil, fmt.Errorf("invalid beef - missing level %d of bump %d", level, i)
+			}
+			nLeaves, bytesUsed := bt.NewVarIntFromBytes(beefBytes)
+			beefBytes = beefBytes[bytesUsed:]
+
+			leaves := make([]beef.BUMPLeaf, 0)
+			for leaf := uint64(0); leaf < uint64(nLeaves); leaf++ {
+				if len(beefBytes) < 2 {
+					return nil, nil, fmt.Errorf("invalid beef - missing leaf of bump %d", i)
+				}
+				offset, bytesUsed := bt.NewVarIntFromBytes(beefBytes)
+				beefBytes = beefBytes[bytesUsed:]
+				if len(beefBytes) == 0 {
+					return nil, nil, fmt.Errorf("invalid beef - missing leaf flag of bump %d", i)
+				}
+				flag := beefBytes[0]
+				beefBytes = beefBytes[1:]
+
+				bumpLeaf := beef.BUMPLeaf{Offset: uint64(offset)}
+				switch flag {
+				case flags(false, true):
+					bumpLeaf.Duplicate = true
+				case flags(false, false), flags(true, false):
+					if len(beefBytes) < beefTxIDBytesCount {
+						return nil, nil, fmt.Errorf("invalid beef - missing leaf hash of bump %d", i)
+					}
+					bumpLeaf.Hash = hex.EncodeToString(bt.ReverseBytes(beefBytes[:beefTxIDBytesCount]))
+					bumpLeaf.TxId = flag == flags(true, false)
+					beefBytes = beefBytes[beefTxIDBytesCount:]
+				default:
+					return nil, nil, fmt.Errorf("invalid beef - invalid leaf flag %d of bump %d", flag, i)
+				}
+				leaves = append(leaves, bumpLeaf)
+			}
+			bump.Path = append(bump.Path, leaves)
+		}
+		bumps = append(bumps, bump)
+	}
+
+	return bumps, beefBytes, nil
+}
+
+// hydrateKnownBEEFTxs will add the txid only transactions of BEEF V2 to the decoded BEEF, using the stored
+// transactions
+//
+// The known transactions must be mined (with a BUMP) to be verified
+func hydrateKnownBEEFTxs(ctx context.Context, c ClientInterface, dBeef *beef.DecodedBEEF,
+	knownTxIDs []string,
+) error {
+	if len(knownTxIDs) == 0 {
+		return nil
+	}
+
+	txs, err := c.GetTransactionsByIDs(ctx, knownTxIDs)
+	if err != nil {
+		return err
+	}
+
+	found := make(map[string]*Transaction, len(txs))
+	for _, tx := range txs {
+		found[tx.ID] = tx
+	}
+
+	known := make([]*beef.TxData, 0, len(knownTxIDs))
+	for _, txID := range knownTxIDs {
+		tx, ok := found[txID]
+		if !ok {
+			return fmt.Errorf("%w: unknown txid only transaction %s", ErrInvalidBEEF, txID)
+		}
+		if len(tx.BUMP.Path) == 0 {
+			return fmt.Errorf("%w: txid only transaction %s is not mined", ErrInvalidBEEF, txID)
+		}
+
+		var btTx *bt.Tx
+		if btTx, err = bt.NewTxFromString(tx.Hex); err != nil {
+			return err
+		}
+
+		bumpIndex := bt.VarInt(len(dBeef.BUMPs))
+		dBeef.BUMPs = append(dBeef.BUMPs, toPaymailBUMP(&tx.BUMP))
+		known = append(known, &beef.TxData{
+			Transaction: btTx,
+			BumpIndex:   &bumpIndex,
+		})
+	}
+
+	// ancestors first, the subject transaction stays the last one
+	dBeef.Transactions = append(known, dBeef.Transactions...)
+
+	return nil
+}
+
+// toPaymailBUMP will convert the BUMP into the BUMP of the paymail BEEF
+func toPaymailBUMP(bump *BUMP) *beef.BUMP {
+	paths := make([][]beef.BUMPLeaf, 0, len(bump.Path))
+	for _, path := range bump.Path {
+		leaves := make([]beef.BUMPLeaf, 0, len(path))
+		for _, leaf := range path {
+			leaves = append(leaves, beef.BUMPLeaf{
+				Hash:      leaf.Hash,
+				TxId:      leaf.TxID,
+				Duplicate: leaf.Duplicate,
+				Offset:    leaf.Offset,
+			})
+		}
+		paths = append(paths, leaves)
+	}
+
+	return &beef.BUMP{
+		BlockHeight: bump.BlockHeight,
+		Path:        paths,
+	}
+}
@@ -0,0 +1,109 @@
+package bux
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/libsv/go-bt/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test_decodeBEEFHex will test the method decodeBEEFHex()
+func Test_decodeBEEFHex(t *testing.T) {
+	bumps, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+
+	t.Run("beef", func(t *testing.T) {
+		beefHex, err := toBeefHex(bumps, txs)
+		require.NoError(t, err)
+
+		dBeef, knownTxIDs, err := decodeBEEFHex(beefHex)
+		require.NoError(t, err)
+		assert.Empty(t, knownTxIDs)
+		require.Len(t, dBeef.Transactions, 2)
+		assert.Equal(t, txs[1].TxID(), dBeef.GetLatestTx().TxID())
+	})
+
+	t.Run("beef v2", func(t *testing.T) {
+		beefHex, err := toBeefV2Hex(bumps, txs, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "0200beef", beefHex[:8])
+
+		dBeef, knownTxIDs, err := decodeBEEFHex(beefHex)
+		require.NoError(t, err)
+		assert.Empty(t, knownTxIDs)
+		require.Len(t, dBeef.Transactions, 2)
+		assert.Equal(t, txs[0].TxID(), dBeef.Transactions[0].GetTxID())
+		require.NotNil(t, dBeef.Transactions[0].BumpIndex)
+		assert.True(t, dBeef.Transactions[1].Unmined())
+		assert.Equal(t, txs[1].TxID(), dBeef.GetLatestTx().TxID())
+
+		require.Len(t, dBeef.BUMPs, 1)
+		assert.Equal(t, bumps[0].BlockHeight, dBeef.BUMPs[0].BlockHeight)
+		assert.Equal(t, toPaymailBUMP(bumps[0]).Path, dBeef.BUMPs[0].Path)
+	})
+
+	t.Run("beef v2 with txid only", func(t *testing.T) {
+		beefHex, err := toBeefV2Hex(nil, txs, []string{txs[0].TxID()})
+		require.NoError(t, err)
+
+		dBeef, knownTxIDs, err := decodeBEEFHex(beefHex)
+		require.NoError(t, err)
+		assert.Equal(t, []string{txs[0].TxID()}, knownTxIDs)
+		require.Len(t, dBeef.Transactions, 1)
+		assert.Equal(t, txs[1].TxID(), dBeef.GetLatestTx().TxID())
+	})
+
+	t.Run("subject cannot be txid only", func(t *testing.T) {
+		_, err := toBeefV2Hex(bumps, txs, []string{txs[1].TxID()})
+		require.Error(t, err)
+	})
+
+	t.Run("txid only is not supported in beef v1", func(t *testing.T) {
+		_, err := newBeefTx(beefVersion1, bumps, txs, []string{txs[0].TxID()})
+		require.Error(t, err)
+	})
+
+	t.Run("atomic beef", func(t *testing.T) {
+		for _, version := range []uint32{beefVersion1, beefVersion2} {
+			beefHex, err := toAtomicBeefHex(version, bumps, txs, nil)
+			require.NoError(t, err)
+			assert.Equal(t, "01010101", beefHex[:8])
+
+			dBeef, _, err := decodeBEEFHex(beefHex)
+			require.NoError(t, err)
+			require.Len(t, dBeef.Transactions, 2)
+			assert.Equal(t, txs[1].TxID(), dBeef.GetLatestTx().TxID())
+		}
+	})
+
+	t.Run("atomic beef with wrong subject", func(t *testing.T) {
+		beefHex, err := toAtomicBeefHex(beefVersion1, bumps, txs, nil)
+		require.NoError(t, err)
+
+		// replace the subject txid by the parent txid
+		parentID := hex.EncodeToString(bt.ReverseBytes(txs[0].TxIDBytes()))
+		_, _, err = decodeBEEFHex(beefHex[:8] + parentID + beefHex[72:])
+		require.Error(t, err)
+	})
+
+	t.Run("atomic beef with unrelated transaction", func(t *testing.T) {
+		otherBumps, otherTxs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+		beefHex, err := toAtomicBeefHex(beefVersion1, append(bumps, otherBumps...),
+			[]*bt.Tx{txs[0], otherTxs[0], txs[1]}, nil)
+		require.NoError(t, err)
+
+		_, _, err = decodeBEEFHex(beefHex)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid beef", func(t *testing.T) {
+		for _, beefHex := range []string{
+			"", "invalid", "0300beef00", "0200beef", "0200beef0000", "01010101",
+			"0200beefffffffffffffffff7f", "0100beefffffffffffffffff7f", "0200beef01feffffff7f0001ffffffffffffffff7f",
+		} {
+			_, _, err := decodeBEEFHex(beefHex)
+			require.Error(t, err, beefHex)
+		}
+	})
+}
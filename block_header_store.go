@@ -0,0 +1,297 @@
+package bux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/BuxOrg/bux/chainstate"
+	"github.com/libsv/go-bc"
+	"github.com/mrz1836/go-datastore"
+)
+
+const (
+	blockHeaderSize           = 80   // Size in bytes of a raw block header
+	blockHeadersBatchSize     = 2000 // Number of headers stored (or requested from the source) per batch
+	blockHeaderLocatorDensity = 10   // Number of consecutive blocks from the tip in the locator
+)
+
+// BlockHeaderSource is a source of block headers used to extend the local header store (IE: a node, a header service)
+type BlockHeaderSource interface {
+	// GetHeadersAfter will return up to limit headers (in chain order) following the first block of the locator
+	// (block hashes from the tip backwards) that is on the best chain of the source; when the locator is empty
+	// the headers start from the genesis block
+	GetHeadersAfter(ctx context.Context, locator []string, limit int) ([]*bc.BlockHeader, error)
+}
+
+// blockHeaderStore is the local header store, a header chain persisted in the Datastore
+//
+// It implements the chainstate.HeaderService so merkle roots can be verified without an external service
+type blockHeaderStore struct {
+	client *Client
+}
+
+// newBlockHeaderStore will create a header store for the client
+func newBlockHeaderStore(client *Client) *blockHeaderStore {
+	return &blockHeaderStore{client: client}
+}
+
+// VerifyMerkleRoots will verify the merkle roots against the headers of the longest chain
+//
+// Merkle roots at heights which are not in the store (IE: above the tip) are not confirmed,
+// unlike Pulse the store is offline and cannot tell a forged BUMP from a header which is not synced yet
+func (s *blockHeaderStore) VerifyMerkleRoots(ctx context.Context,
+	merkleRoots []chainstate.MerkleRootConfirmationRequestItem,
+) error {
+	for _, item := range merkleRoots {
+		header, err := getBlockHeaderByHeight(ctx, item.BlockHeight, s.client.DefaultModelOptions()...)
+		if err != nil {
+			return err
+		} else if header == nil {
+			s.client.Logger().Warn().
+				Uint64("blockHeight", item.BlockHeight).
+				Str("merkleRoot", item.MerkleRoot).
+				Msg("Block height is not synced")
+			return fmt.Errorf("%w: %d", ErrBlockHeightNotSynced, item.BlockHeight)
+		}
+		if header.MerkleRoot != item.MerkleRoot {
+			s.client.Logger().Warn().
+				Uint64("blockHeight", item.BlockHeight).
+				Str("merkleRoot", item.MerkleRoot).
+				Msg("Not all merkle roots confirmed")
+			return ErrMerkleRootNotConfirmed
+		}
+	}
+
+	return nil
+}
+
+// importHeaders will read raw (80 bytes) headers and store them in batches
+//
+// The first header must connect to the stored headers, or start the chain at startHeight if the store is empty
+func (s *blockHeaderStore) importHeaders(ctx context.Context, reader io.Reader, startHeight uint64) (uint64, error) {
+	var imported uint64
+	headers := make([]*bc.BlockHeader, 0, blockHeadersBatchSize)
+	raw := make([]byte, blockHeaderSize)
+	for {
+		_, err := io.ReadFull(reader, raw)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return imported, ErrInvalidBlockHeader
+		}
+
+		var header *bc.BlockHeader
+		if header, err = bc.NewBlockHeaderFromBytes(raw); err != nil {
+			return imported, ErrInvalidBlockHeader
+		}
+		if headers = append(headers, header); len(headers) < blockHeadersBatchSize {
+			continue
+		}
+
+		var added uint64
+		added, err = s.addHeaders(ctx, headers, startHeight)
+		imported += added
+		if err != nil {
+			return imported, err
+		}
+		headers = headers[:0]
+	}
+
+	added, err := s.addHeaders(ctx, headers, startHeight)
+	return imported + added, err
+}
+
+// syncHeaders will extend the header chain with the headers of the source, until the source has no newer headers
+func (s *blockHeaderStore) syncHeaders(ctx context.Context, source BlockHeaderSource) (uint64, error) {
+	var synced uint64
+	for {
+		locator, err := s.locator(ctx)
+		if err != nil {
+			return synced, err
+		}
+
+		var headers []*bc.BlockHeader
+		if headers, err = source.GetHeadersAfter(ctx, locator, blockHeadersBatchSize); err != nil {
+			return synced, err
+		}
+
+		var added uint64
+		added, err = s.addHeaders(ctx, headers, 0)
+		synced += added
+		if err != nil || added == 0 || len(headers) < blockHeadersBatchSize {
+			return synced, err
+		}
+	}
+}
+
+// locator will return the hashes of the longest chain from the tip backwards: the last blocks one by one,
+// then doubling the step, and the first stored block
+func (s *blockHeaderStore) locator(ctx context.Context) ([]string, error) {
+	opts := s.client.DefaultModelOptions()
+	tip, err := getChainTip(ctx, opts...)
+	if err != nil || tip == nil {
+		return nil, err
+	}
+
+	locator := []string{tip.ID}
+	step := uint64(1)
+	for height := tip.Height; height > step; {
+		height -= step
+		var header *BlockHeader
+		if header, err = getBlockHeaderByHeight(ctx, height, opts...); err != nil {
+			return nil, err
+		} else if header == nil {
+			break
+		}
+		locator = append(locator, header.ID)
+		if len(locator) >= blockHeaderLocatorDensity {
+			step *= 2
+		}
+	}
+
+	var first []*BlockHeader
+	if first, err = getBlockHeaders(ctx, &map[string]interface{}{longestChainField: true}, &datastore.QueryParams{
+		Page:          1,
+		PageSize:      1,
+		OrderByField:  heightField,
+		SortDirection: datastore.SortAsc,
+	}, opts...); err != nil {
+		return nil, err
+	}
+	if len(first) > 0 && first[0].ID != locator[len(locator)-1] {
+		locator = append(locator, first[0].ID)
+	}
+
+	return locator, nil
+}
+
+// addHeaders will validate and store the headers, switching the longest chain to the branch with the most work
+//
+// Headers must connect to a known header, except the first header of an empty store which is stored at startHeight.
+// The store is locked while adding (the tip and the longest chain flags are read, then updated)
+func (s *blockHeaderStore) addHeaders(ctx context.Context, headers []*bc.BlockHeader, startHeight uint64) (uint64, error) {
+	unlock, err := newWaitWriteLock(ctx, lockKeyBlockHeaders, s.client.Cachestore())
+	defer unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	return s._addHeaders(ctx, headers, startHeight)
+}
+
+// _addHeaders will validate and store the headers (see addHeaders(), the store must be locked)
+func (s *blockHeaderStore) _addHeaders(ctx context.Context, headers []*bc.BlockHeader, startHeight uint64) (uint64, error) {
+	opts := s.client.DefaultModelOptions()
+	tip, err := getChainTip(ctx, opts...)
+	if err != nil {
+		return 0, err
+	}
+
+	// headers of this batch, which might not be reloaded from the Datastore
+	batch := make(map[string]*BlockHeader, len(headers))
+	lookup := func(hash string) (*BlockHeader, error) {
+		if header, ok := batch[hash]; ok {
+			return header, nil
+		}
+		return getBlockHeader(ctx, hash, opts...)
+	}
+
+	var added uint64
+	for _, header := range headers {
+		if !header.Valid() {
+			return added, ErrInvalidBlockHeader
+		}
+
+		var existing *BlockHeader
+		if existing, err = lookup(blockHeaderHash(header)); err != nil {
+			return added, err
+		} else if existing != nil {
+			continue
+		}
+
+		var previous *BlockHeader
+		if previous, err = lookup(header.HashPrevBlockStr()); err != nil {
+			return added, err
+		}
+
+		work, _ := blockWork(header.BitsStr())
+		height := startHeight
+		if previous != nil {
+			height = previous.Height + 1
+			work.Add(work, previous.chainWork())
+		} else if tip != nil {
+			return added, ErrBlockHeaderNotConnected
+		}
+
+		blockHeader := newBlockHeader(header, height, work, append(opts, New())...)
+		if tip == nil || work.Cmp(tip.chainWork()) > 0 {
+			if tip != nil && previous != nil && previous.ID != tip.ID {
+				if err = s.switchLongestChain(ctx, previous, lookup, batch); err != nil {
+					return added, err
+				}
+			}
+			blockHeader.LongestChain = true
+			tip = blockHeader
+		}
+
+		if err = blockHeader.Save(ctx); err != nil {
+			return added, err
+		}
+		batch[blockHeader.ID] = blockHeader
+		added++
+	}
+
+	return added, nil
+}
+
+// switchLongestChain will make the branch ending with the header the longest chain (reorg, the store must be locked)
+func (s *blockHeaderStore) switchLongestChain(ctx context.Context, header *BlockHeader,
+	lookup func(hash string) (*BlockHeader, error), batch map[string]*BlockHeader,
+) (err error) {
+	// find the fork point
+	branch := make([]*BlockHeader, 0)
+	for header != nil && !header.LongestChain {
+		branch = append(branch, header)
+		if header, err = lookup(header.HashPreviousBlock); err != nil {
+			return err
+		}
+	}
+	if header == nil {
+		return ErrBlockHeaderNotConnected
+	}
+
+	s.client.Logger().Info().
+		Uint64("forkHeight", header.Height).
+		Int("branchLength", len(branch)).
+		Msg("switching the longest chain of the block headers")
+
+	// remove the old branch from the longest chain
+	var stale []*BlockHeader
+	if stale, err = getBlockHeaders(ctx, &map[string]interface{}{
+		longestChainField: true,
+		heightField:       map[string]interface{}{conditionGreaterThan: header.Height},
+	}, nil, s.client.DefaultModelOptions()...); err != nil {
+		return err
+	}
+	for _, staleHeader := range stale {
+		staleHeader.LongestChain = false
+		if err = staleHeader.Save(ctx); err != nil {
+			return err
+		}
+		if batchHeader, ok := batch[staleHeader.ID]; ok {
+			batchHeader.LongestChain = false
+		}
+	}
+
+	// add the new branch to the longest chain
+	for _, branchHeader := range branch {
+		branchHeader.LongestChain = true
+		if err = branchHeader.Save(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
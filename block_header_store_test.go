@@ -0,0 +1,235 @@
+package bux
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/BuxOrg/bux/chainstate"
+	"github.com/BuxOrg/bux/utils"
+	"github.com/libsv/go-bc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockBlockHeaderSource is a block header source serving the headers of the chain
+type mockBlockHeaderSource struct {
+	chain []*bc.BlockHeader
+}
+
+func (m *mockBlockHeaderSource) GetHeadersAfter(_ context.Context, locator []string, limit int) ([]*bc.BlockHeader, error) {
+	start := 0
+	for _, hash := range locator {
+		for index, header := range m.chain {
+			if blockHeaderHash(header) == hash {
+				start = index + 1
+				break
+			}
+		}
+		if start > 0 {
+			break
+		}
+	}
+	end := start + limit
+	if end > len(m.chain) {
+		end = len(m.chain)
+	}
+	return m.chain[start:end], nil
+}
+
+// mineTestBlockHeaders will mine regtest block headers (trivial proof of work) following the previous header
+func mineTestBlockHeaders(t *testing.T, previous *bc.BlockHeader, count int) []*bc.BlockHeader {
+	previousHash := make([]byte, 32)
+	if previous != nil {
+		var err error
+		previousHash, err = hex.DecodeString(blockHeaderHash(previous))
+		require.NoError(t, err)
+	}
+
+	headers := make([]*bc.BlockHeader, 0, count)
+	for i := 0; i < count; i++ {
+		merkleRoot, err := utils.RandomHex(32)
+		require.NoError(t, err)
+
+		header := &bc.BlockHeader{
+			Version:       1,
+			Time:          uint32(1700000000 + i),
+			HashPrevBlock: previousHash,
+			Bits:          []byte{0x20, 0x7f, 0xff, 0xff},
+		}
+		header.HashMerkleRoot, _ = hex.DecodeString(merkleRoot)
+		for !header.Valid() {
+			header.Nonce++
+		}
+
+		headers = append(headers, header)
+		previousHash, _ = hex.DecodeString(blockHeaderHash(header))
+	}
+	return headers
+}
+
+// blockHeadersBytes will return the raw headers (IE: a headers file)
+func blockHeadersBytes(headers []*bc.BlockHeader) []byte {
+	raw := make([]byte, 0, len(headers)*blockHeaderSize)
+	for _, header := range headers {
+		raw = append(raw, header.Bytes()...)
+	}
+	return raw
+}
+
+// TestClient_ImportBlockHeaders will test the method ImportBlockHeaders()
+func TestClient_ImportBlockHeaders(t *testing.T) {
+	t.Run("import a headers file", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(), WithLocalHeaderStore(nil))
+		defer deferMe()
+
+		headers := mineTestBlockHeaders(t, nil, 5)
+		imported, err := client.ImportBlockHeaders(ctx, bytes.NewReader(blockHeadersBytes(headers)), 100)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(5), imported)
+
+		var tip *BlockHeader
+		tip, err = client.GetBlockHeaderTip(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, tip)
+		assert.Equal(t, uint64(104), tip.Height)
+		assert.Equal(t, blockHeaderHash(headers[4]), tip.ID)
+		assert.Equal(t, headers[4].HashMerkleRootStr(), tip.MerkleRoot)
+
+		// importing again is a no-op
+		imported, err = client.ImportBlockHeaders(ctx, bytes.NewReader(blockHeadersBytes(headers)), 100)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), imported)
+	})
+
+	t.Run("headers must connect", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(), WithLocalHeaderStore(nil))
+		defer deferMe()
+
+		_, err := client.ImportBlockHeaders(ctx, bytes.NewReader(blockHeadersBytes(mineTestBlockHeaders(t, nil, 2))), 0)
+		require.NoError(t, err)
+
+		_, err = client.ImportBlockHeaders(ctx, bytes.NewReader(blockHeadersBytes(mineTestBlockHeaders(t, nil, 2))), 0)
+		require.ErrorIs(t, err, ErrBlockHeaderNotConnected)
+	})
+
+	t.Run("invalid headers", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(), WithLocalHeaderStore(nil))
+		defer deferMe()
+
+		headers := mineTestBlockHeaders(t, nil, 2)
+		_, err := client.ImportBlockHeaders(ctx, bytes.NewReader(blockHeadersBytes(headers)[:100]), 0)
+		require.ErrorIs(t, err, ErrInvalidBlockHeader)
+
+		// proof of work is not satisfied
+		headers[0].Bits = []byte{0x1d, 0x00, 0xff, 0xff}
+		_, err = client.ImportBlockHeaders(ctx, bytes.NewReader(blockHeadersBytes(headers[:1])), 0)
+		require.ErrorIs(t, err, ErrInvalidBlockHeader)
+	})
+
+	t.Run("local header store is not enabled", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup())
+		defer deferMe()
+
+		_, err := client.ImportBlockHeaders(ctx, bytes.NewReader(nil), 0)
+		require.ErrorIs(t, err, ErrLocalHeaderStoreDisabled)
+
+		_, err = client.SyncBlockHeaders(ctx)
+		require.ErrorIs(t, err, ErrLocalHeaderStoreDisabled)
+	})
+}
+
+// TestClient_SyncBlockHeaders will test the method SyncBlockHeaders()
+func TestClient_SyncBlockHeaders(t *testing.T) {
+	t.Run("sync and switch to the chain with the most work", func(t *testing.T) {
+		chain := mineTestBlockHeaders(t, nil, 10)
+		source := &mockBlockHeaderSource{chain: chain}
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(), WithLocalHeaderStore(source))
+		defer deferMe()
+
+		synced, err := client.SyncBlockHeaders(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(10), synced)
+
+		// the source switches to a longer fork of the chain
+		fork := mineTestBlockHeaders(t, chain[5], 6)
+		source.chain = append(chain[:6:6], fork...)
+
+		synced, err = client.SyncBlockHeaders(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(6), synced)
+
+		var tip *BlockHeader
+		tip, err = client.GetBlockHeaderTip(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(11), tip.Height)
+		assert.Equal(t, blockHeaderHash(fork[5]), tip.ID)
+
+		opts := client.DefaultModelOptions()
+		for height, header := range source.chain {
+			var stored *BlockHeader
+			stored, err = getBlockHeaderByHeight(ctx, uint64(height), opts...)
+			require.NoError(t, err)
+			require.NotNil(t, stored)
+			assert.Equal(t, blockHeaderHash(header), stored.ID)
+		}
+
+		var stale *BlockHeader
+		stale, err = getBlockHeader(ctx, blockHeaderHash(chain[9]), opts...)
+		require.NoError(t, err)
+		require.NotNil(t, stale)
+		assert.False(t, stale.LongestChain)
+	})
+
+	t.Run("missing block header source", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(), WithLocalHeaderStore(nil))
+		defer deferMe()
+
+		_, err := client.SyncBlockHeaders(ctx)
+		require.ErrorIs(t, err, ErrMissingBlockHeaderSource)
+	})
+}
+
+// Test_blockHeaderStore_VerifyMerkleRoots will test the method VerifyMerkleRoots()
+func Test_blockHeaderStore_VerifyMerkleRoots(t *testing.T) {
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(), WithLocalHeaderStore(nil))
+	defer deferMe()
+
+	headers := mineTestBlockHeaders(t, nil, 3)
+	_, err := client.ImportBlockHeaders(ctx, bytes.NewReader(blockHeadersBytes(headers)), 800000)
+	require.NoError(t, err)
+
+	t.Run("confirmed merkle roots", func(t *testing.T) {
+		err = client.Chainstate().VerifyMerkleRoots(ctx, []chainstate.MerkleRootConfirmationRequestItem{
+			{MerkleRoot: headers[0].HashMerkleRootStr(), BlockHeight: 800000},
+			{MerkleRoot: headers[2].HashMerkleRootStr(), BlockHeight: 800002},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid merkle root", func(t *testing.T) {
+		err = client.Chainstate().VerifyMerkleRoots(ctx, []chainstate.MerkleRootConfirmationRequestItem{
+			{MerkleRoot: headers[0].HashMerkleRootStr(), BlockHeight: 800001},
+		})
+		require.ErrorIs(t, err, ErrMerkleRootNotConfirmed)
+	})
+
+	t.Run("height above the tip is not synced", func(t *testing.T) {
+		err = client.Chainstate().VerifyMerkleRoots(ctx, []chainstate.MerkleRootConfirmationRequestItem{
+			{MerkleRoot: headers[0].HashMerkleRootStr(), BlockHeight: 800000},
+			{MerkleRoot: headers[0].HashMerkleRootStr(), BlockHeight: 900000},
+		})
+		require.ErrorIs(t, err, ErrBlockHeightNotSynced)
+	})
+
+	t.Run("forged BEEF at a future height", func(t *testing.T) {
+		bumps, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+		bumps[0].BlockHeight = 900000
+		beefHex, beefErr := toBeefHex(bumps, txs)
+		require.NoError(t, beefErr)
+
+		_, err = client.RecordBEEFTransaction(ctx, beefHex)
+		require.ErrorIs(t, err, ErrBEEFVerificationFailed)
+	})
+}
@@ -0,0 +1,42 @@
+package bux
+
+import (
+	"context"
+
+	"github.com/BuxOrg/bux/chainstate"
+)
+
+// broadcastAttemptRecorder is the history of the broadcast attempts, persisted in the Datastore
+//
+// It implements the chainstate.BroadcastAttemptRecorder, each attempt to a provider is saved as a BroadcastAttempt
+type broadcastAttemptRecorder struct {
+	client *Client
+}
+
+// newBroadcastAttemptRecorder will create a broadcast attempt recorder for the client
+func newBroadcastAttemptRecorder(client *Client) *broadcastAttemptRecorder {
+	return &broadcastAttemptRecorder{client: client}
+}
+
+// RecordBroadcastAttempt will save the broadcast attempt
+func (r *broadcastAttemptRecorder) RecordBroadcastAttempt(ctx context.Context,
+	attempt *chainstate.BroadcastAttempt,
+) error {
+	return newBroadcastAttempt(attempt, r.client.DefaultModelOptions(New())...).Save(ctx)
+}
+
+// GetBroadcastAttempts will get the broadcast attempts of the transaction to each provider (oldest first)
+//
+// The attempts are only recorded if enabled (see WithBroadcastAttempts)
+func (c *Client) GetBroadcastAttempts(ctx context.Context, txID string,
+	opts ...ModelOps,
+) ([]*BroadcastAttempt, error) {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "get_broadcast_attempts")
+
+	if len(txID) == 0 {
+		return nil, ErrMissingFieldID
+	}
+
+	return getBroadcastAttempts(ctx, txID, c.DefaultModelOptions(opts...)...)
+}
@@ -0,0 +1,78 @@
+package bux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BuxOrg/bux/chainstate"
+	"github.com/bitcoin-sv/go-broadcast-client/broadcast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_GetBroadcastAttempts will test the method GetBroadcastAttempts()
+func TestClient_GetBroadcastAttempts(t *testing.T) {
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(), WithBroadcastAttempts())
+	defer deferMe()
+	recorder := newBroadcastAttemptRecorder(client.(*Client))
+
+	startedAt := time.Now().UTC().Truncate(time.Millisecond)
+	rejected := &chainstate.BroadcastAttempt{
+		Error:           "dust",
+		ErrorClass:      chainstate.ErrorClassRejected,
+		Latency:         120 * time.Millisecond,
+		Provider:        "miner-x",
+		ResponseCode:    "failure",
+		ResponseMessage: "dust",
+		StartedAt:       startedAt,
+		TxID:            testTxID,
+		TxStatus:        broadcast.Rejected,
+	}
+	accepted := &chainstate.BroadcastAttempt{
+		Latency:      80 * time.Millisecond,
+		Provider:     "miner-y",
+		ResponseCode: "success",
+		StartedAt:    startedAt.Add(time.Second),
+		Success:      true,
+		TxID:         testTxID,
+		TxStatus:     broadcast.SeenOnNetwork,
+	}
+	other := &chainstate.BroadcastAttempt{
+		Provider:  "miner-x",
+		StartedAt: startedAt,
+		Success:   true,
+		TxID:      "b2a2f4c4b9d6d2a1e4bcd6b0dfd8ec0ff2b6d64e8e4b2c7f1bd0a4d2f6c1e3a9",
+	}
+	for _, attempt := range []*chainstate.BroadcastAttempt{accepted, rejected, other} {
+		require.NoError(t, recorder.RecordBroadcastAttempt(ctx, attempt))
+	}
+
+	t.Run("attempts of the transaction (oldest first)", func(t *testing.T) {
+		attempts, err := client.GetBroadcastAttempts(ctx, testTxID)
+		require.NoError(t, err)
+		require.Len(t, attempts, 2)
+
+		assert.Equal(t, "miner-x", attempts[0].Provider)
+		assert.False(t, attempts[0].Success)
+		assert.Equal(t, "failure", attempts[0].ResponseCode)
+		assert.Equal(t, "dust", attempts[0].ResponseMessage)
+		assert.Equal(t, string(broadcast.Rejected), attempts[0].TxStatus)
+		assert.Equal(t, chainstate.ErrorClassRejected, attempts[0].ErrorClass)
+		assert.Equal(t, int64(120), attempts[0].LatencyMs)
+
+		assert.Equal(t, "miner-y", attempts[1].Provider)
+		assert.True(t, attempts[1].Success)
+		assert.Equal(t, string(broadcast.SeenOnNetwork), attempts[1].TxStatus)
+	})
+
+	t.Run("no attempts", func(t *testing.T) {
+		attempts, err := client.GetBroadcastAttempts(ctx, testXPubID)
+		require.NoError(t, err)
+		assert.Empty(t, attempts)
+	})
+
+	t.Run("missing tx id", func(t *testing.T) {
+		_, err := client.GetBroadcastAttempts(ctx, "")
+		require.ErrorIs(t, err, ErrMissingFieldID)
+	})
+}
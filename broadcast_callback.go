@@ -0,0 +1,209 @@
+package bux
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/bitcoin-sv/go-broadcast-client/broadcast"
+	"github.com/libsv/go-bc"
+	"github.com/mrz1836/go-datastore"
+)
+
+// maxBroadcastCallbackSize is the max size of a broadcast callback payload (1MB)
+const maxBroadcastCallbackSize = 1 << 20
+
+// broadcastCallbackHandler is the http.Handler of the broadcast (ARC) callbacks
+type broadcastCallbackHandler struct {
+	client ClientInterface
+	token  string
+}
+
+// BroadcastCallbackHandler will return the http.Handler for the broadcast (ARC) callbacks
+//
+// The callback token set with WithCallback is required as bearer token (Authorization header),
+// all the callbacks are rejected if no token is set
+func (c *Client) BroadcastCallbackHandler() http.Handler {
+	return &broadcastCallbackHandler{
+		client: c,
+		token:  c.options.chainstate.callbackToken,
+	}
+}
+
+// ServeHTTP will validate the callback request and process the callback
+func (h *broadcastCallbackHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if len(h.token) == 0 || subtle.ConstantTimeCompare(
+		[]byte(req.Header.Get("Authorization")), []byte("Bearer "+h.token),
+	) != 1 {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	callback := new(broadcast.SubmittedTx)
+	if err := json.NewDecoder(io.LimitReader(req.Body, maxBroadcastCallbackSize)).Decode(callback); err != nil {
+		http.Error(w, ErrInvalidBroadcastCallback.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.client.ProcessBroadcastCallback(req.Context(), callback); err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidBroadcastCallback):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, ErrBroadcastCallbackNotVerified):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			h.client.Logger().Error().
+				Str("txID", callback.TxID).
+				Err(err).
+				Msg("failed to process broadcast callback")
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// ProcessBroadcastCallback will verify the merkle path of the broadcast (ARC) callback with the header service
+// and update the transaction
+//
+// Duplicate callbacks are ignored, callbacks of unknown transactions, late callbacks (transaction already
+// synced in another block) and callbacks with an invalid merkle path are recorded (see GetBroadcastCallbacks)
+func (c *Client) ProcessBroadcastCallback(ctx context.Context, callback *broadcast.SubmittedTx) error {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "process_broadcast_callback")
+
+	if err := validateBroadcastCallback(callback); err != nil {
+		return err
+	}
+
+	// Only the mined transactions are updated
+	if callback.TxStatus != broadcast.Mined {
+		return nil
+	}
+
+	// Duplicate callbacks can be received at the same time
+	unlock, err := newWaitWriteLock(ctx, fmt.Sprintf(lockKeyBroadcastCallback, callback.TxID), c.Cachestore())
+	defer unlock()
+	if err != nil {
+		return err
+	}
+
+	var transaction *Transaction
+	if transaction, err = getTransactionByID(
+		ctx, "", callback.TxID, c.DefaultModelOptions()...,
+	); err != nil {
+		return err
+	} else if transaction == nil {
+		return c.recordBroadcastCallback(ctx, callback, BroadcastCallbackUnknown, ErrMissingTransaction.Error())
+	}
+
+	var bump BUMP
+	if bump, err = c.verifyBroadcastCallbackBUMP(ctx, callback); err != nil {
+		if recordErr := c.recordBroadcastCallback(ctx, callback, BroadcastCallbackInvalid, err.Error()); recordErr != nil {
+			return recordErr
+		}
+		return fmt.Errorf("%w: %s", ErrBroadcastCallbackNotVerified, err.Error())
+	}
+
+	// Already synced on-chain: same block (duplicate) or another block (late)
+	if len(transaction.BUMP.Path) > 0 {
+		merkleRoot, _ := bump.calculateMerkleRoot()
+		if currentMerkleRoot, _ := transaction.BUMP.calculateMerkleRoot(); currentMerkleRoot == merkleRoot {
+			return nil
+		}
+		return c.recordBroadcastCallback(
+			ctx, callback, BroadcastCallbackLate, "transaction already synced in block "+transaction.BlockHash,
+		)
+	}
+
+	return c.UpdateTransaction(ctx, callback)
+}
+
+// GetBroadcastCallbacks will get the recorded broadcast callbacks (unknown, late or invalid)
+func (c *Client) GetBroadcastCallbacks(ctx context.Context, conditions *map[string]interface{},
+	queryParams *datastore.QueryParams, opts ...ModelOps,
+) ([]*BroadcastCallback, error) {
+	// Check for existing NewRelic transaction
+	ctx = c.GetOrStartTxn(ctx, "get_broadcast_callbacks")
+
+	return getBroadcastCallbacks(ctx, conditions, queryParams, c.DefaultModelOptions(opts...)...)
+}
+
+// verifyBroadcastCallbackBUMP will return the BUMP of the callback if its merkle root is confirmed by the header service
+func (c *Client) verifyBroadcastCallbackBUMP(ctx context.Context, callback *broadcast.SubmittedTx) (BUMP, error) {
+	bcBump, err := bc.NewBUMPFromStr(callback.MerklePath)
+	if err != nil {
+		return BUMP{}, err
+	}
+
+	bump := bcBumpToBUMP(bcBump)
+	if bump.BlockHeight != uint64(callback.BlockHeight) {
+		return BUMP{}, fmt.Errorf("merkle path block height %d does not match %d", bump.BlockHeight, callback.BlockHeight)
+	} else if _, ok := bump.txLeafOffset(callback.TxID); !ok {
+		return BUMP{}, errors.New("transaction not found in the merkle path")
+	}
+
+	if err = _verifyBUMP(ctx, c, bump); err != nil {
+		return BUMP{}, err
+	}
+	return bump, nil
+}
+
+// recordBroadcastCallback will record the broadcast callback for inspection (once)
+func (c *Client) recordBroadcastCallback(ctx context.Context, callback *broadcast.SubmittedTx,
+	reason, message string,
+) error {
+	c.Logger().Warn().
+		Str("txID", callback.TxID).
+		Str("reason", reason).
+		Msgf("broadcast callback not applied: %s", message)
+
+	record := newBroadcastCallback(callback, reason, message, c.DefaultModelOptions(New())...)
+	existing, err := getBroadcastCallback(ctx, record.ID, c.DefaultModelOptions()...)
+	if err != nil || existing != nil {
+		return err
+	}
+	return record.Save(ctx)
+}
+
+// validateBroadcastCallback will validate the shape of the callback payload
+func validateBroadcastCallback(callback *broadcast.SubmittedTx) error {
+	if callback == nil {
+		return ErrInvalidBroadcastCallback
+	} else if !isValidHash(callback.TxID) {
+		return fmt.Errorf("%w: invalid txid", ErrInvalidBroadcastCallback)
+	} else if callback.TxStatus == "" {
+		return fmt.Errorf("%w: missing txStatus", ErrInvalidBroadcastCallback)
+	}
+
+	if callback.TxStatus == broadcast.Mined {
+		if len(callback.MerklePath) == 0 {
+			return fmt.Errorf("%w: missing merklePath", ErrInvalidBroadcastCallback)
+		} else if !isValidHash(callback.BlockHash) {
+			return fmt.Errorf("%w: invalid blockHash", ErrInvalidBroadcastCallback)
+		} else if callback.BlockHeight <= 0 {
+			return fmt.Errorf("%w: invalid blockHeight", ErrInvalidBroadcastCallback)
+		}
+	}
+	return nil
+}
+
+// isValidHash will return true if the value is a 32 bytes hash (hex)
+func isValidHash(value string) bool {
+	if len(value) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(value)
+	return err == nil
+}
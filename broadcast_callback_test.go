@@ -0,0 +1,153 @@
+package bux
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/BuxOrg/bux/utils"
+	"github.com/bitcoin-sv/go-broadcast-client/broadcast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCallbackToken = "test-callback-token"
+
+// newTestBroadcastCallback will create the mined callback of the transaction with its BUMP
+func newTestBroadcastCallback(t *testing.T, txID string) (*broadcast.SubmittedTx, BUMP) {
+	bump, _ := createTestBlockBUMPs(t, txID)
+	blockHash, err := utils.RandomHex(32)
+	require.NoError(t, err)
+
+	callback := &broadcast.SubmittedTx{}
+	callback.TxID = txID
+	callback.TxStatus = broadcast.Mined
+	callback.BlockHash = blockHash
+	callback.BlockHeight = int64(bump.BlockHeight)
+	callback.MerklePath = bump.Hex()
+	return callback, bump
+}
+
+// serveTestBroadcastCallback will send the callback to the handler and return the response status code
+func serveTestBroadcastCallback(t *testing.T, handler http.Handler, token string, callback interface{}) int {
+	body, err := json.Marshal(callback)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/transaction/broadcast/callback", bytes.NewReader(body))
+	if len(token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w.Code
+}
+
+// TestClient_BroadcastCallbackHandler will test the method BroadcastCallbackHandler()
+func TestClient_BroadcastCallbackHandler(t *testing.T) {
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+		WithCustomChainstate(&chainStateEverythingOnChain{}),
+		WithCallback("https://bux.test/transaction/broadcast/callback", testCallbackToken))
+	defer deferMe()
+	handler := client.BroadcastCallbackHandler()
+
+	_, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+	opts := append(client.DefaultModelOptions(), New())
+	tx, err := txFromHex(txs[0].String(), opts...)
+	require.NoError(t, err)
+	require.NoError(t, tx.Save(ctx))
+	syncTx := newSyncTransaction(tx.ID, &SyncConfig{SyncOnChain: true, Broadcast: true}, opts...)
+	require.NoError(t, syncTx.Save(ctx))
+
+	callback, bump := newTestBroadcastCallback(t, tx.ID)
+
+	t.Run("invalid requests", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/transaction/broadcast/callback", nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+		assert.Equal(t, http.StatusUnauthorized, serveTestBroadcastCallback(t, handler, "", callback))
+		assert.Equal(t, http.StatusUnauthorized, serveTestBroadcastCallback(t, handler, "wrong-token", callback))
+		assert.Equal(t, http.StatusBadRequest, serveTestBroadcastCallback(t, handler, testCallbackToken, "not a callback"))
+
+		invalid := *callback
+		invalid.TxID = "invalid"
+		assert.Equal(t, http.StatusBadRequest, serveTestBroadcastCallback(t, handler, testCallbackToken, &invalid))
+
+		invalid = *callback
+		invalid.MerklePath = ""
+		assert.Equal(t, http.StatusBadRequest, serveTestBroadcastCallback(t, handler, testCallbackToken, &invalid))
+	})
+
+	t.Run("no callback token", func(t *testing.T) {
+		_, noTokenClient, deferNoToken := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+			WithCustomChainstate(&chainStateEverythingOnChain{}))
+		defer deferNoToken()
+
+		assert.Equal(t, http.StatusUnauthorized,
+			serveTestBroadcastCallback(t, noTokenClient.BroadcastCallbackHandler(), "", callback))
+	})
+
+	t.Run("not mined status is ignored", func(t *testing.T) {
+		seen := &broadcast.SubmittedTx{}
+		seen.TxID = tx.ID
+		seen.TxStatus = broadcast.SeenOnNetwork
+		assert.Equal(t, http.StatusOK, serveTestBroadcastCallback(t, handler, testCallbackToken, seen))
+	})
+
+	t.Run("invalid merkle path is recorded", func(t *testing.T) {
+		invalid := *callback
+		invalid.BlockHeight++
+		assert.Equal(t, http.StatusUnprocessableEntity, serveTestBroadcastCallback(t, handler, testCallbackToken, &invalid))
+
+		callbacks, err := client.GetBroadcastCallbacks(ctx, &map[string]interface{}{"reason": BroadcastCallbackInvalid}, nil)
+		require.NoError(t, err)
+		require.Len(t, callbacks, 1)
+		assert.Equal(t, tx.ID, callbacks[0].TxID)
+	})
+
+	t.Run("transaction is updated", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serveTestBroadcastCallback(t, handler, testCallbackToken, callback))
+
+		updated, err := client.GetTransaction(ctx, "", tx.ID)
+		require.NoError(t, err)
+		assert.Equal(t, callback.BlockHash, updated.BlockHash)
+		assert.Equal(t, bump, updated.BUMP)
+
+		updatedSyncTx, err := GetSyncTransactionByTxID(ctx, tx.ID, client.DefaultModelOptions()...)
+		require.NoError(t, err)
+		assert.Equal(t, SyncStatusComplete, updatedSyncTx.SyncStatus)
+	})
+
+	t.Run("duplicate callback is idempotent", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serveTestBroadcastCallback(t, handler, testCallbackToken, callback))
+
+		callbacks, err := client.GetBroadcastCallbacks(ctx, nil, nil)
+		require.NoError(t, err)
+		assert.Len(t, callbacks, 1)
+	})
+
+	t.Run("late callback is recorded", func(t *testing.T) {
+		late, _ := newTestBroadcastCallback(t, tx.ID)
+		assert.Equal(t, http.StatusOK, serveTestBroadcastCallback(t, handler, testCallbackToken, late))
+		assert.Equal(t, http.StatusOK, serveTestBroadcastCallback(t, handler, testCallbackToken, late))
+
+		callbacks, err := client.GetBroadcastCallbacks(ctx, &map[string]interface{}{"reason": BroadcastCallbackLate}, nil)
+		require.NoError(t, err)
+		require.Len(t, callbacks, 1)
+		assert.Equal(t, late.BlockHash, callbacks[0].BlockHash)
+	})
+
+	t.Run("unknown transaction is recorded", func(t *testing.T) {
+		unknownTxID, _ := utils.RandomHex(32)
+		unknown, _ := newTestBroadcastCallback(t, unknownTxID)
+		assert.Equal(t, http.StatusOK, serveTestBroadcastCallback(t, handler, testCallbackToken, unknown))
+
+		callbacks, err := client.GetBroadcastCallbacks(ctx, &map[string]interface{}{"reason": BroadcastCallbackUnknown}, nil)
+		require.NoError(t, err)
+		require.Len(t, callbacks, 1)
+		assert.Equal(t, unknownTxID, callbacks[0].TxID)
+	})
+}
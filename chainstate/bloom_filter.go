@@ -0,0 +1,78 @@
+package chainstate
+
+import (
+	"hash/fnv"
+	"math"
+	"sync"
+)
+
+// BloomFilter is a (thread-safe) bloom filter of strings, IE: the locking scripts of the destinations
+//
+// Test can return a false positive (at the configured rate) but never a false negative
+type BloomFilter struct {
+	bits   []uint64
+	hashes uint64
+	mu     sync.RWMutex
+	size   uint64
+}
+
+// NewBloomFilter will create a bloom filter sized for the number of items and the false positive rate
+func NewBloomFilter(maxItems uint64, falsePositiveRate float64) *BloomFilter {
+	if maxItems == 0 {
+		maxItems = defaultMaxNumberOfDestinations
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = defaultFalsePositiveRate
+	}
+
+	size := uint64(math.Ceil(-float64(maxItems) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	hashes := uint64(math.Max(1, math.Round(float64(size)/float64(maxItems)*math.Ln2)))
+	return &BloomFilter{
+		bits:   make([]uint64, (size+63)/64),
+		hashes: hashes,
+		size:   size,
+	}
+}
+
+// DefaultBloomFilter will create a bloom filter for the default number of destinations (100,000 at 1%)
+func DefaultBloomFilter() *BloomFilter {
+	return NewBloomFilter(defaultMaxNumberOfDestinations, defaultFalsePositiveRate)
+}
+
+// Add will add the item to the filter
+func (b *BloomFilter) Add(item string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	h1, h2 := bloomHashes(item)
+	for i := uint64(0); i < b.hashes; i++ {
+		position := (h1 + i*h2) % b.size
+		b.bits[position/64] |= 1 << (position % 64)
+	}
+}
+
+// Test will return true if the item may be in the filter (false if it's certainly not)
+func (b *BloomFilter) Test(item string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	h1, h2 := bloomHashes(item)
+	for i := uint64(0); i < b.hashes; i++ {
+		position := (h1 + i*h2) % b.size
+		if b.bits[position/64]&(1<<(position%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// bloomHashes will return the two hashes of the item used for the double hashing of the positions
+func bloomHashes(item string) (uint64, uint64) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(item))
+	h1 := h.Sum64()
+
+	h = fnv.New64()
+	_, _ = h.Write([]byte(item))
+	return h1, h.Sum64() | 1 // odd, to cycle through all the positions
+}
@@ -0,0 +1,42 @@
+package chainstate
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBloomFilter will test the methods Add() and Test()
+func TestBloomFilter(t *testing.T) {
+	t.Run("added items are found", func(t *testing.T) {
+		b := NewBloomFilter(1000, 0.01)
+		for i := 0; i < 1000; i++ {
+			b.Add("item-" + strconv.Itoa(i))
+		}
+		for i := 0; i < 1000; i++ {
+			assert.True(t, b.Test("item-"+strconv.Itoa(i)))
+		}
+	})
+
+	t.Run("false positive rate", func(t *testing.T) {
+		b := NewBloomFilter(1000, 0.01)
+		for i := 0; i < 1000; i++ {
+			b.Add("item-" + strconv.Itoa(i))
+		}
+		falsePositives := 0
+		for i := 0; i < 10000; i++ {
+			if b.Test("other-" + strconv.Itoa(i)) {
+				falsePositives++
+			}
+		}
+		assert.Less(t, falsePositives, 300) // 1% expected
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		b := NewBloomFilter(0, 0)
+		assert.Equal(t, DefaultBloomFilter().size, b.size)
+		assert.Equal(t, DefaultBloomFilter().hashes, b.hashes)
+		assert.False(t, b.Test("76a9147ff514e6ae3deb46e6644caac5cdd0bf2388906588ac"))
+	})
+}
@@ -2,10 +2,13 @@ package chainstate
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
+
+	"github.com/BuxOrg/bux/metrics"
+	"github.com/bitcoin-sv/go-broadcast-client/broadcast"
 )
 
 var (
@@ -18,6 +21,39 @@ var (
 		"txn_already_in_mempool", // TXN_ALREADY_IN_MEMPOOL
 	}
 
+	// broadcastNetworkErrors are a list of errors that are failures of the provider, not of the transaction
+	broadcastNetworkErrors = []string{
+		"connection refused",
+		"connection reset",
+		"no such host",
+		"bad gateway",
+		"service unavailable",
+		"internal server error",
+		"eof",
+	}
+
+	// broadcastTimeoutErrors are a list of errors that are timeouts of the provider
+	broadcastTimeoutErrors = []string{
+		"deadline exceeded",
+		"i/o timeout",
+	}
+
+	// broadcastRejectedErrors are a list of errors of the nodes & miners rejecting the transaction
+	// (see the list of the rejection codes below), the transaction is at fault, not the provider
+	broadcastRejectedErrors = []string{
+		"txn_mempool_conflict", "txn-mempool-conflict",
+		"non_final_pool_full", "non-final-pool-full",
+		"too_long_non_final_chain", "too-long-non-final-chain",
+		"bad_txns_", "bad-txns-", // BAD_TXNS_INPUTS_TOO_LARGE, BAD_TXNS_INPUTS_SPENT, BAD_TXNS_NONSTANDARD_INPUTS
+		"non_bip68_final", "non-bip68-final",
+		"too_long_validation_time", "too-long-validation-time",
+		"absurdly_high_fee", "absurdly-high-fee",
+		"dust",
+		"tx_fee_too_low", "mempool min fee not met", "insufficient priority",
+		"mandatory-script-verify-flag-failed", "non-mandatory-script-verify-flag", // invalid scripts
+		"double_spend_attempted", // ARC DOUBLE_SPEND_ATTEMPTED
+	}
+
 	// broadcastQuestionableErrors are a list of errors that are not good broadcast responses,
 	// but need to be checked differently
 	broadcastQuestionableErrors = []string{
@@ -41,48 +77,67 @@ var (
 	*/
 )
 
-// broadcast will broadcast using a standard strategy
+// broadcast will broadcast using the providers in the order of their health (see broadcastRouter)
 //
-// NOTE: if successful (in-mempool), no error will be returned
-// NOTE: function register the fastest successful broadcast into 'completeChannel' so client doesn't need to wait for other providers
-func (c *Client) broadcast(ctx context.Context, id, hex string, timeout time.Duration, completeChannel, errorChannel chan string) {
+// NOTE: if successful (in-mempool), the name of the provider is returned
+// NOTE: the next provider is only tried if the previous one failed, each attempt gets a share of the timeout
+// (see attemptTimeout), the best provider being tried first it gets most of it
+// NOTE: a broadcast can be pinned to a provider (see WithBroadcastProvider)
+func (c *Client) broadcast(ctx context.Context, id, hex string, timeout time.Duration) (string, error) {
 	// Create a context (to cancel or timeout)
 	ctxWithCancel, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	var wg sync.WaitGroup
-
-	resultsChannel := make(chan broadcastResult)
-	status := newBroadcastStatus(completeChannel)
-
-	for _, broadcastProvider := range createActiveProviders(c, id, hex) {
-		wg.Add(1)
-		go func(provider txBroadcastProvider) {
-			defer wg.Done()
-			broadcastToProvider(ctxWithCancel, ctx, provider, id, c, timeout,
-				resultsChannel, status)
-		}(broadcastProvider)
-	}
-
-	go func() {
-		wg.Wait()
-		close(resultsChannel)
-		status.dispose()
-	}()
-
-	var errorMessages []string
-	for result := range resultsChannel {
-		if result.isError {
-			debugLog(c, id, fmt.Sprintf("broadcast error: %s from provider %s", result.err, result.provider))
-			errorMessages = append(errorMessages, result.provider+": "+result.err.Error())
-		} else {
-			debugLog(c, id, fmt.Sprintf("successful broadcast to %s", result.provider))
+	// A pinned broadcast only uses the pinned provider (unless failover is enabled)
+	pin := getBroadcastPin(ctx)
+	providers := pin.filter(createActiveProviders(c, id, hex))
+	if pin != nil && len(providers) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrBroadcastProviderNotFound, pin.provider)
+	}
+
+	router := c.options.config.broadcastRouter
+	providers = pin.first(c.preferFeeSatisfied(router.route(providers), hex))
+	defer router.release(providers)
+	if c.options.metrics != nil {
+		defer router.exportMetrics(c.options.metrics)
+	}
+
+	deadline, _ := ctxWithCancel.Deadline()
+	errorMessages := make([]string, 0, len(providers))
+	for i, provider := range providers {
+		attemptTimeout := c.attemptTimeout(time.Until(deadline), len(providers)-i)
+		if attemptTimeout <= 0 {
+			errorMessages = append(errorMessages, provider.getName()+": "+context.DeadlineExceeded.Error())
+			break
+		}
+
+		if err := broadcastToProvider(ctxWithCancel, ctx, provider, id, c, attemptTimeout); err != nil {
+			debugLog(c, id, fmt.Sprintf("broadcast error: %s from provider %s", err, provider.getName()))
+			errorMessages = append(errorMessages, provider.getName()+": "+err.Error())
+			continue
 		}
+
+		debugLog(c, id, fmt.Sprintf("successful broadcast to %s", provider.getName()))
+		return provider.getName(), nil
 	}
 
-	if !status.success && len(errorMessages) > 0 {
-		errorChannel <- strings.Join(errorMessages, ", ")
+	if len(errorMessages) == 0 {
+		return "", ErrMissingBroadcastProviders
 	}
+	return "", errors.New(strings.Join(errorMessages, ", "))
+}
+
+// attemptTimeout will return the timeout of the next broadcast attempt out of the remaining timeout
+//
+// The last provider gets all the remaining timeout, the others get the configured attempt timeout
+// (see WithBroadcastAttemptTimeout) or by default a share of the remaining timeout (see broadcastAttemptShare)
+func (c *Client) attemptTimeout(remaining time.Duration, providersLeft int) time.Duration {
+	if providersLeft <= 1 {
+		return remaining
+	} else if timeout := c.options.config.attemptTimeout; timeout > 0 {
+		return min(timeout, remaining)
+	}
+	return time.Duration(float64(remaining) * broadcastAttemptShare)
 }
 
 func createActiveProviders(c *Client, txID, txHex string) []txBroadcastProvider {
@@ -101,6 +156,9 @@ func createActiveProviders(c *Client, txID, txHex string) []txBroadcastProvider
 	case ProviderBroadcastClient:
 		pvdr := broadcastClientProvider{txID: txID, txHex: txHex}
 		providers = append(providers, &pvdr)
+	case ProviderNode:
+		pvdr := nodeBroadcastProvider{txID: txID, txHex: txHex}
+		providers = append(providers, &pvdr)
 	default:
 		c.options.logger.Warn().Msg("no active provider for broadcast")
 	}
@@ -108,29 +166,53 @@ func createActiveProviders(c *Client, txID, txHex string) []txBroadcastProvider
 	return providers
 }
 
+// broadcastToProvider will broadcast to a single provider and record the result in the broadcast router
 func broadcastToProvider(ctx, fallbackCtx context.Context, provider txBroadcastProvider, txID string,
-	c *Client, fallbackTimeout time.Duration,
-	resultsChannel chan broadcastResult, status *broadcastStatus,
-) {
-	bErr := provider.broadcast(ctx, c)
-
-	if bErr != nil {
-		// check in Mempool as fallback - if transaction is there -> GREAT SUCCESS
-		// Check error response for "questionable errors"/(TX FAILURE)
-		if doesErrorContain(bErr.Error(), broadcastQuestionableErrors) {
-			bErr = checkInMempool(fallbackCtx, c, txID, bErr.Error(), fallbackTimeout)
-		}
+	c *Client, timeout time.Duration,
+) error {
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Wait for the rate limit of the provider (not a failure of the provider)
+	if err := c.options.config.rateLimits.wait(attemptCtx, provider.getName()); err != nil {
+		return fmt.Errorf("rate limit of %s (%s): %w", provider.getName(), err.Error(), context.DeadlineExceeded)
+	}
 
-		if bErr != nil {
-			resultsChannel <- newErrorResult(bErr, provider.getName())
+	var end metrics.EndWithClassification
+	if c.options.metrics != nil {
+		end = c.options.metrics.TrackBroadcast(provider.getName())
+	}
+
+	start := time.Now()
+	response, bErr := provider.broadcast(attemptCtx, c)
+	latency := time.Since(start)
+
+	// check in Mempool as fallback - if transaction is there -> GREAT SUCCESS
+	// Check error response for "questionable errors"/(TX FAILURE)
+	if bErr != nil && doesErrorContain(bErr.Error(), broadcastQuestionableErrors) {
+		if bErr = checkInMempool(fallbackCtx, c, txID, bErr.Error(), timeout); bErr == nil && response != nil {
+			response.txStatus = broadcast.SeenOnNetwork
 		}
 	}
 
-	// successful broadcast or found in mempool
-	if bErr == nil {
-		status.tryCompleteWithSuccess(provider.getName())
-		resultsChannel <- newSuccessResult(provider.getName())
+	if end != nil {
+		end(bErr == nil)
 	}
+
+	attempt := newBroadcastAttempt(txID, provider.getName(), start, latency, response, bErr)
+	c.recordBroadcastAttempt(fallbackCtx, attempt)
+
+	if bErr != nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		// Cut short by its share of the timeout (not by the broadcast timeout): not a failure of the provider
+		c.options.config.broadcastRouter.recordCutShort(provider.getName(), bErr)
+		return bErr
+	} else if bErr != nil {
+		c.options.config.broadcastRouter.recordFailure(provider.getName(), latency, bErr, attempt.ErrorClass)
+		return bErr
+	}
+
+	c.options.config.broadcastRouter.recordSuccess(provider.getName(), latency)
+	return nil
 }
 
 // checkInMempool is a quick check to see if the tx is in mempool (or on-chain)
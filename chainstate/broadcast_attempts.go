@@ -0,0 +1,58 @@
+package chainstate
+
+import (
+	"context"
+	"time"
+
+	"github.com/bitcoin-sv/go-broadcast-client/broadcast"
+)
+
+// BroadcastAttempt is the outcome of the broadcast of a transaction to a provider
+type BroadcastAttempt struct {
+	Error           string             `json:"error,omitempty"`       // Error of the attempt (empty if successful)
+	ErrorClass      string             `json:"error_class,omitempty"` // Class of the error (network, provider, rejected, timeout)
+	Latency         time.Duration      `json:"latency"`               // Duration of the attempt
+	Provider        string             `json:"provider"`              // Name of the provider (IE: miner name)
+	ResponseCode    string             `json:"response_code"`         // Raw response code (IE: mAPI return result, ARC status)
+	ResponseMessage string             `json:"response_message"`      // Raw response message of the provider
+	StartedAt       time.Time          `json:"started_at"`            // Start of the attempt
+	Success         bool               `json:"success"`               // If the provider accepted the transaction
+	TxID            string             `json:"tx_id"`                 // Transaction ID
+	TxStatus        broadcast.TxStatus `json:"tx_status,omitempty"`   // Resulting status (empty if the provider did not answer)
+}
+
+// newBroadcastAttempt will return the attempt from the response of the provider
+func newBroadcastAttempt(txID, provider string, startedAt time.Time, latency time.Duration,
+	response *broadcastResponse, err error,
+) *BroadcastAttempt {
+	attempt := &BroadcastAttempt{
+		Latency:   latency,
+		Provider:  provider,
+		StartedAt: startedAt.UTC(),
+		Success:   err == nil,
+		TxID:      txID,
+	}
+	if response != nil {
+		attempt.ResponseCode = response.code
+		attempt.ResponseMessage = response.message
+		attempt.TxStatus = response.txStatus
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+		attempt.ErrorClass = ClassifyBroadcastError(err)
+	}
+	return attempt
+}
+
+// recordBroadcastAttempt will record the attempt (if a recorder is set), a failure is only logged
+func (c *Client) recordBroadcastAttempt(ctx context.Context, attempt *BroadcastAttempt) {
+	recorder := c.options.config.broadcastAttempts
+	if recorder == nil {
+		return
+	}
+
+	if err := recorder.RecordBroadcastAttempt(ctx, attempt); err != nil {
+		c.options.logger.Warn().Err(err).Str("txID", attempt.TxID).Str("provider", attempt.Provider).
+			Msg("failed recording the broadcast attempt")
+	}
+}
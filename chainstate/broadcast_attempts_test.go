@@ -0,0 +1,96 @@
+package chainstate
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/bitcoin-sv/go-broadcast-client/broadcast"
+	broadcast_client_mock "github.com/bitcoin-sv/go-broadcast-client/broadcast/broadcast-client-mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAttemptRecorder is a recorder keeping the broadcast attempts in memory
+type testAttemptRecorder struct {
+	attempts []*BroadcastAttempt
+	mu       sync.Mutex
+}
+
+func (r *testAttemptRecorder) RecordBroadcastAttempt(_ context.Context, attempt *BroadcastAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts = append(r.attempts, attempt)
+	return nil
+}
+
+// TestClient_Broadcast_Attempts will test the broadcast attempts recorded by Broadcast()
+func TestClient_Broadcast_Attempts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("accepted (mAPI)", func(t *testing.T) {
+		recorder := &testAttemptRecorder{}
+		c := NewTestClient(
+			context.Background(), t,
+			WithMinercraft(&minerCraftBroadcastSuccess{}),
+			WithBroadcastAttemptRecorder(recorder),
+		)
+
+		provider, err := c.Broadcast(
+			context.Background(), broadcastExample1TxID, broadcastExample1TxHex, defaultBroadcastTimeOut,
+		)
+		require.NoError(t, err)
+		require.NotEmpty(t, recorder.attempts)
+
+		attempt := recorder.attempts[len(recorder.attempts)-1]
+		assert.Equal(t, provider, attempt.Provider)
+		assert.Equal(t, broadcastExample1TxID, attempt.TxID)
+		assert.True(t, attempt.Success)
+		assert.Equal(t, mAPISuccess, attempt.ResponseCode)
+		assert.Equal(t, broadcast.SeenOnNetwork, attempt.TxStatus)
+		assert.Empty(t, attempt.Error)
+		assert.False(t, attempt.StartedAt.IsZero())
+	})
+
+	t.Run("accepted (broadcast-client)", func(t *testing.T) {
+		recorder := &testAttemptRecorder{}
+		c := NewTestClient(
+			context.Background(), t,
+			WithMinercraft(&MinerCraftBase{}),
+			WithBroadcastClient(broadcast_client_mock.Builder().WithMockArc(broadcast_client_mock.MockSuccess).Build()),
+			WithBroadcastAttemptRecorder(recorder),
+		)
+
+		_, err := c.Broadcast(
+			context.Background(), broadcastExample1TxID, broadcastExample1TxHex, defaultBroadcastTimeOut,
+		)
+		require.NoError(t, err)
+		require.Len(t, recorder.attempts, 1)
+		assert.Equal(t, ProviderBroadcastClient, recorder.attempts[0].Provider)
+		assert.True(t, recorder.attempts[0].Success)
+		assert.NotEmpty(t, recorder.attempts[0].TxStatus)
+	})
+
+	t.Run("failed (broadcast-client)", func(t *testing.T) {
+		recorder := &testAttemptRecorder{}
+		c := NewTestClient(
+			context.Background(), t,
+			WithMinercraft(&MinerCraftBase{}),
+			WithBroadcastClient(broadcast_client_mock.Builder().WithMockArc(broadcast_client_mock.MockFailure).Build()),
+			WithFeeQuotes(false),
+			WithFeeUnit(MockDefaultFee),
+			WithBroadcastAttemptRecorder(recorder),
+		)
+
+		_, err := c.Broadcast(
+			context.Background(), broadcastExample1TxID, broadcastExample1TxHex, defaultBroadcastTimeOut,
+		)
+		require.Error(t, err)
+		require.Len(t, recorder.attempts, 1)
+		assert.False(t, recorder.attempts[0].Success)
+		assert.Equal(t, broadcast.ErrAllBroadcastersFailed.Error(), recorder.attempts[0].Error)
+		assert.Equal(t, ErrorClassProvider, recorder.attempts[0].ErrorClass)
+		assert.Empty(t, recorder.attempts[0].ResponseCode)
+		assert.Empty(t, recorder.attempts[0].TxStatus)
+	})
+}
@@ -38,7 +38,7 @@ func (c *Client) broadcastClientInit(ctx context.Context) error {
 				Bytes:    int(fee.MiningFee.Bytes),
 			}
 		}
-		c.options.config.feeUnit = utils.LowestFee(fees, c.options.config.feeUnit)
+		c.options.config.feeUnit = c.selectFeeUnit(fees, c.options.config.feeUnit)
 	}
 
 	return nil
@@ -0,0 +1,72 @@
+package chainstate
+
+import (
+	"context"
+	"strings"
+)
+
+// broadcastPin is the provider a broadcast is pinned to (see WithBroadcastProvider)
+type broadcastPin struct {
+	failover bool   // If the other providers are tried after the pinned provider
+	provider string // Name of the provider (IE: miner name, ProviderBroadcastClient)
+}
+
+// broadcastPinKey is the context key of the broadcast pin
+type broadcastPinKey struct{}
+
+// WithBroadcastProvider will pin the broadcasts using the context to the named provider (IE: a miner name)
+//
+// The pinned provider is tried first, with failover the other providers are tried after it,
+// otherwise the broadcast fails if the pinned provider is not active or fails
+func WithBroadcastProvider(ctx context.Context, provider string, failover bool) context.Context {
+	if len(provider) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, broadcastPinKey{}, &broadcastPin{failover: failover, provider: provider})
+}
+
+// getBroadcastPin will return the broadcast pin of the context (nil if not pinned)
+func getBroadcastPin(ctx context.Context) *broadcastPin {
+	pin, _ := ctx.Value(broadcastPinKey{}).(*broadcastPin)
+	return pin
+}
+
+// isPinned will return true if the provider is the pinned provider (case-insensitive)
+func (p *broadcastPin) isPinned(provider txBroadcastProvider) bool {
+	return strings.EqualFold(provider.getName(), p.provider)
+}
+
+// filter will keep only the pinned provider (unless failover is enabled)
+func (p *broadcastPin) filter(providers []txBroadcastProvider) []txBroadcastProvider {
+	if p == nil || p.failover {
+		return providers
+	}
+
+	pinned := make([]txBroadcastProvider, 0, 1)
+	for _, provider := range providers {
+		if p.isPinned(provider) {
+			pinned = append(pinned, provider)
+		}
+	}
+	return pinned
+}
+
+// first will move the pinned provider first, the order of the other providers is kept
+func (p *broadcastPin) first(providers []txBroadcastProvider) []txBroadcastProvider {
+	if p == nil {
+		return providers
+	}
+
+	ordered := make([]txBroadcastProvider, 0, len(providers))
+	for _, provider := range providers {
+		if p.isPinned(provider) {
+			ordered = append(ordered, provider)
+		}
+	}
+	for _, provider := range providers {
+		if !p.isPinned(provider) {
+			ordered = append(ordered, provider)
+		}
+	}
+	return ordered
+}
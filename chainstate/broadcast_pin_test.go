@@ -0,0 +1,75 @@
+package chainstate
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tonicpow/go-minercraft/v2"
+)
+
+// Test_broadcastPin will test the filtering and the order of the providers of a pinned broadcast
+func Test_broadcastPin(t *testing.T) {
+	providers := []txBroadcastProvider{
+		testBroadcastProvider{name: "first"},
+		testBroadcastProvider{name: "second"},
+		testBroadcastProvider{name: "third"},
+	}
+
+	t.Run("not pinned", func(t *testing.T) {
+		pin := getBroadcastPin(context.Background())
+		require.Nil(t, pin)
+		assert.Equal(t, []string{"first", "second", "third"}, routedNames(pin.first(pin.filter(providers))))
+	})
+
+	t.Run("empty provider is not pinned", func(t *testing.T) {
+		assert.Nil(t, getBroadcastPin(WithBroadcastProvider(context.Background(), "", false)))
+	})
+
+	t.Run("pinned without failover", func(t *testing.T) {
+		pin := getBroadcastPin(WithBroadcastProvider(context.Background(), "Second", false))
+		require.NotNil(t, pin)
+		assert.Equal(t, []string{"second"}, routedNames(pin.filter(providers)))
+	})
+
+	t.Run("pinned with failover", func(t *testing.T) {
+		pin := getBroadcastPin(WithBroadcastProvider(context.Background(), "third", true))
+		require.NotNil(t, pin)
+		assert.Equal(t, []string{"third", "first", "second"}, routedNames(pin.first(pin.filter(providers))))
+	})
+}
+
+// TestClient_Broadcast_Pinned will test the method Broadcast() pinned to a provider
+func TestClient_Broadcast_Pinned(t *testing.T) {
+	t.Parallel()
+
+	c := NewTestClient(context.Background(), t, WithMinercraft(&minerCraftBroadcastSuccess{}))
+
+	t.Run("pinned miner", func(t *testing.T) {
+		provider, err := c.Broadcast(
+			WithBroadcastProvider(context.Background(), strings.ToLower(minercraft.MinerGorillaPool), false),
+			broadcastExample1TxID, broadcastExample1TxHex, defaultBroadcastTimeOut,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, minercraft.MinerGorillaPool, provider)
+	})
+
+	t.Run("unknown miner", func(t *testing.T) {
+		_, err := c.Broadcast(
+			WithBroadcastProvider(context.Background(), "unknown", false),
+			broadcastExample1TxID, broadcastExample1TxHex, defaultBroadcastTimeOut,
+		)
+		require.ErrorIs(t, err, ErrBroadcastProviderNotFound)
+	})
+
+	t.Run("unknown miner with failover", func(t *testing.T) {
+		provider, err := c.Broadcast(
+			WithBroadcastProvider(context.Background(), "unknown", true),
+			broadcastExample1TxID, broadcastExample1TxHex, defaultBroadcastTimeOut,
+		)
+		require.NoError(t, err)
+		assert.NotEmpty(t, provider)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/bitcoin-sv/go-broadcast-client/broadcast"
@@ -13,7 +14,14 @@ import (
 // generic broadcast provider
 type txBroadcastProvider interface {
 	getName() string
-	broadcast(ctx context.Context, c *Client) error
+	broadcast(ctx context.Context, c *Client) (*broadcastResponse, error)
+}
+
+// broadcastResponse is the raw response of a provider to a broadcast (nil if the provider did not answer)
+type broadcastResponse struct {
+	code     string             // Raw response code (IE: mAPI return result, ARC status)
+	message  string             // Raw response message (IE: mAPI result description, ARC title or detail)
+	txStatus broadcast.TxStatus // Resulting status of the transaction
 }
 
 // mAPI provider
@@ -26,15 +34,25 @@ func (provider mapiBroadcastProvider) getName() string {
 	return provider.miner.Name
 }
 
-func (provider mapiBroadcastProvider) broadcast(ctx context.Context, c *Client) error {
+func (provider mapiBroadcastProvider) broadcast(ctx context.Context, c *Client) (*broadcastResponse, error) {
 	return broadcastMAPI(ctx, c, provider.miner, provider.txID, provider.txHex)
 }
 
 // broadcastMAPI will broadcast a transaction to a miner using mAPI
-func broadcastMAPI(ctx context.Context, client ClientInterface, miner *minercraft.Miner, id, hex string) error {
+//
+// mAPI only accepts raw transactions, a transaction in Extended Format is converted to raw
+func broadcastMAPI(ctx context.Context, client ClientInterface, miner *minercraft.Miner,
+	id, hex string,
+) (*broadcastResponse, error) {
 	debugLog(client, id, "executing broadcast request in mapi using miner: "+miner.Name)
 
-	resp, err := client.Minercraft().SubmitTransaction(ctx, miner, &minercraft.Transaction{
+	hex, err := toRawHex(hex)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *minercraft.SubmitTransactionResponse
+	resp, err = client.Minercraft().SubmitTransaction(ctx, miner, &minercraft.Transaction{
 		CallBackEncryption: "", // todo: allow customizing the payload
 		CallBackToken:      "",
 		CallBackURL:        "",
@@ -45,29 +63,31 @@ func broadcastMAPI(ctx context.Context, client ClientInterface, miner *minercraf
 	})
 	if err != nil {
 		debugLog(client, id, "error executing request in mapi using miner: "+miner.Name+" failed: "+err.Error())
-		return err
+		return nil, err
 	}
 
 	// Something went wrong - got back an id that does not match
-	if resp == nil {
-		return emptyBroadcastResponseErr(id)
+	if resp == nil || resp.Results == nil {
+		return nil, emptyBroadcastResponseErr(id)
 	}
-	if !strings.EqualFold(resp.Results.TxID, id) {
-		return incorrectTxIDReturnedErr(resp.Results.TxID, id)
+	response := &broadcastResponse{
+		code:    resp.Results.ReturnResult,
+		message: resp.Results.ResultDescription,
 	}
-
-	// mAPI success of broadcast
-	if resp.Results.ReturnResult == mAPISuccess {
-		return nil
+	if !strings.EqualFold(resp.Results.TxID, id) {
+		return response, incorrectTxIDReturnedErr(resp.Results.TxID, id)
 	}
 
-	// Check error message (for success error message)
-	if doesErrorContain(resp.Results.ResultDescription, broadcastSuccessErrors) {
-		return nil
+	// mAPI success of broadcast, or a success error message (IE: already in the mempool)
+	if resp.Results.ReturnResult == mAPISuccess ||
+		doesErrorContain(resp.Results.ResultDescription, broadcastSuccessErrors) {
+		response.txStatus = broadcast.SeenOnNetwork
+		return response, nil
 	}
 
 	// We got a potential real error message?
-	return errors.New(resp.Results.ResultDescription)
+	response.txStatus = broadcast.Rejected
+	return response, errors.New(resp.Results.ResultDescription)
 }
 
 func incorrectTxIDReturnedErr(actualTxID, expectedTxID string) error {
@@ -90,29 +110,51 @@ func (provider broadcastClientProvider) getName() string {
 }
 
 // Broadcast using BroadcastClient
-func (provider broadcastClientProvider) broadcast(ctx context.Context, c *Client) error {
+func (provider broadcastClientProvider) broadcast(ctx context.Context, c *Client) (*broadcastResponse, error) {
 	return broadcastWithBroadcastClient(ctx, c, provider.txID, provider.txHex)
 }
 
-func broadcastWithBroadcastClient(ctx context.Context, client *Client, txID, hex string) error {
+// broadcastWithBroadcastClient will broadcast the transaction using the BroadcastClient (ARC)
+//
+// A transaction in Extended Format is submitted as EF, so ARC can validate it without looking up the inputs
+func broadcastWithBroadcastClient(ctx context.Context, client *Client, txID, hex string) (*broadcastResponse, error) {
 	debugLog(client, txID, "executing broadcast request for "+ProviderBroadcastClient)
 
 	tx := broadcast.Transaction{
 		Hex: hex,
 	}
 
+	format := broadcast.WithRawFormat()
+	if isEfHex(hex) {
+		format = broadcast.WithEfFormat()
+	}
+
 	result, err := client.BroadcastClient().SubmitTransaction(
 		ctx,
 		&tx,
-		broadcast.WithRawFormat(),
+		format,
 		broadcast.WithCallback(client.options.config.callbackURL, client.options.config.callbackToken),
 	)
 	if err != nil {
 		debugLog(client, txID, "error broadcast request for "+ProviderBroadcastClient+" failed: "+err.Error())
-		return err
+
+		// ARC answered with an error (IE: 422 the transaction is malformed, 465 the fee is too low)
+		var arcErr broadcast.ArcError
+		if errors.As(err, &arcErr) {
+			return &broadcastResponse{
+				code:     strconv.Itoa(arcErr.Status),
+				message:  arcErr.Title + ": " + arcErr.Detail,
+				txStatus: broadcast.Rejected,
+			}, err
+		}
+		return nil, err
 	}
 
 	debugLog(client, txID, "result broadcast request for "+ProviderBroadcastClient+" blockhash: "+result.BlockHash+" status: "+result.TxStatus.String())
 
-	return nil
+	return &broadcastResponse{
+		code:     strconv.Itoa(result.Status),
+		message:  result.Title,
+		txStatus: result.TxStatus,
+	}, nil
 }
@@ -0,0 +1,295 @@
+package chainstate
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/BuxOrg/bux/metrics"
+	"github.com/bitcoin-sv/go-broadcast-client/broadcast"
+)
+
+// Circuit breaker states of a broadcast provider
+const (
+	CircuitClosed   = "closed"    // Provider is healthy, broadcasts are routed to it
+	CircuitHalfOpen = "half-open" // Provider cooled down, a trial broadcast is allowed
+	CircuitOpen     = "open"      // Provider failed too many times in a row, broadcasts are skipped
+)
+
+// Error classes of a failed broadcast
+const (
+	ErrorClassNetwork  = "network"  // Provider could not be reached
+	ErrorClassProvider = "provider" // Provider failed (any other error)
+	ErrorClassRejected = "rejected" // Provider answered but rejected the transaction (known rejection codes)
+	ErrorClassTimeout  = "timeout"  // Provider did not answer in time
+)
+
+// ARC statuses of a rejected transaction (IE: 465 the fee is too low, 466 conflicting transaction)
+const (
+	arcRejectedStatusMin = 460
+	arcRejectedStatusMax = 469
+)
+
+// Broadcast router defaults
+const (
+	broadcastAttemptShare          = 0.75 // Share of the remaining timeout of a broadcast attempt (except the last one)
+	defaultCircuitBreakerCooldown  = 1 * time.Minute
+	defaultCircuitBreakerThreshold = 3
+	latencySmoothingFactor         = 0.2 // Weight of the last latency in the average (EWMA)
+	recentErrorClassesLength       = 10  // Number of the recent error classes kept per provider
+)
+
+// ProviderStatus is the health of a broadcast provider
+type ProviderStatus struct {
+	AverageLatency      time.Duration     `json:"average_latency"`
+	CircuitState        string            `json:"circuit_state"`
+	ConsecutiveFailures uint32            `json:"consecutive_failures"`
+	Failures            uint64            `json:"failures"`
+	LastError           string            `json:"last_error"`
+	LastFailureAt       time.Time         `json:"last_failure_at"`
+	LastSuccessAt       time.Time         `json:"last_success_at"`
+	Name                string            `json:"name"`
+	RecentErrorClasses  map[string]uint32 `json:"recent_error_classes"`
+	Score               float64           `json:"score"`
+	Successes           uint64            `json:"successes"`
+}
+
+// providerHealth is the health of a single provider (guarded by the router)
+type providerHealth struct {
+	averageLatency      time.Duration
+	consecutiveFailures uint32
+	failures            uint64
+	lastError           string
+	lastFailureAt       time.Time
+	lastSuccessAt       time.Time
+	openedAt            time.Time
+	recentErrorClasses  []string
+	successes           uint64
+	trialInProgress     bool
+}
+
+// broadcastRouter keeps the health of the broadcast providers and picks the order they are tried in
+type broadcastRouter struct {
+	cooldown         time.Duration
+	failureThreshold uint32
+	mu               sync.Mutex
+	providers        map[string]*providerHealth
+}
+
+// newBroadcastRouter will create a router with the given circuit breaker settings
+func newBroadcastRouter(failureThreshold uint32, cooldown time.Duration) *broadcastRouter {
+	return &broadcastRouter{
+		cooldown:         cooldown,
+		failureThreshold: failureThreshold,
+		providers:        make(map[string]*providerHealth),
+	}
+}
+
+// route will return the providers in the order they should be tried (best score first)
+//
+// Providers with an open circuit are skipped, unless no other provider is available
+func (r *broadcastRouter) route(providers []txBroadcastProvider) []txBroadcastProvider {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	available := make([]txBroadcastProvider, 0, len(providers))
+	skipped := make([]txBroadcastProvider, 0)
+	for _, provider := range providers {
+		health := r.get(provider.getName())
+		switch r.state(health, now) {
+		case CircuitOpen:
+			skipped = append(skipped, provider)
+		case CircuitHalfOpen:
+			if health.trialInProgress {
+				skipped = append(skipped, provider)
+				continue
+			}
+			health.trialInProgress = true
+			available = append(available, provider)
+		default:
+			available = append(available, provider)
+		}
+	}
+
+	// Better to try an unhealthy provider than to not broadcast at all
+	if len(available) == 0 {
+		available = skipped
+	}
+
+	sort.SliceStable(available, func(i, j int) bool {
+		return r.get(available[i].getName()).score() > r.get(available[j].getName()).score()
+	})
+	return available
+}
+
+// release will allow a new trial broadcast of the half-open providers which were routed but not tried
+func (r *broadcastRouter) release(providers []txBroadcastProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, provider := range providers {
+		r.get(provider.getName()).trialInProgress = false
+	}
+}
+
+// recordSuccess will record a successful broadcast and close the circuit of the provider
+func (r *broadcastRouter) recordSuccess(name string, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	health := r.get(name)
+	health.successes++
+	health.consecutiveFailures = 0
+	health.lastSuccessAt = time.Now()
+	health.openedAt = time.Time{}
+	health.trialInProgress = false
+	health.observeLatency(latency)
+}
+
+// recordFailure will record a failed broadcast, the circuit of the provider is opened after too many failures
+//
+// A rejected transaction is not a failure of the provider, it does not lower its score
+// nor count towards opening the circuit
+func (r *broadcastRouter) recordFailure(name string, latency time.Duration, err error, errorClass string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	health := r.get(name)
+	health.lastError = err.Error()
+	health.trialInProgress = false
+	health.observeLatency(latency)
+	health.recentErrorClasses = append(health.recentErrorClasses, errorClass)
+	if len(health.recentErrorClasses) > recentErrorClassesLength {
+		health.recentErrorClasses = health.recentErrorClasses[1:]
+	}
+
+	if errorClass == ErrorClassRejected {
+		return
+	}
+
+	health.failures++
+	health.lastFailureAt = now
+	health.consecutiveFailures++
+	if health.consecutiveFailures >= r.failureThreshold {
+		health.openedAt = now
+	}
+}
+
+// recordCutShort will record a broadcast cut short by its share of the broadcast timeout (see attemptTimeout)
+//
+// The provider could have answered within the whole timeout, it does not count towards opening the circuit
+func (r *broadcastRouter) recordCutShort(name string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	health := r.get(name)
+	health.lastError = err.Error()
+	health.trialInProgress = false
+}
+
+// status will return the health of all the known providers (sorted by name)
+func (r *broadcastRouter) status() []*ProviderStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	statuses := make([]*ProviderStatus, 0, len(r.providers))
+	for name, health := range r.providers {
+		classes := make(map[string]uint32)
+		for _, class := range health.recentErrorClasses {
+			classes[class]++
+		}
+		statuses = append(statuses, &ProviderStatus{
+			AverageLatency:      health.averageLatency,
+			CircuitState:        r.state(health, now),
+			ConsecutiveFailures: health.consecutiveFailures,
+			Failures:            health.failures,
+			LastError:           health.lastError,
+			LastFailureAt:       health.lastFailureAt,
+			LastSuccessAt:       health.lastSuccessAt,
+			Name:                name,
+			RecentErrorClasses:  classes,
+			Score:               health.score(),
+			Successes:           health.successes,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// exportMetrics will export the health of all the known providers
+func (r *broadcastRouter) exportMetrics(m *metrics.Metrics) {
+	for _, status := range r.status() {
+		m.SetBroadcastProviderHealth(status.Name, status.Score, status.CircuitState != CircuitClosed)
+	}
+}
+
+// get will return the health of the provider (created if missing)
+func (r *broadcastRouter) get(name string) *providerHealth {
+	health, ok := r.providers[name]
+	if !ok {
+		health = &providerHealth{}
+		r.providers[name] = health
+	}
+	return health
+}
+
+// state will return the circuit state of the provider
+func (r *broadcastRouter) state(health *providerHealth, now time.Time) string {
+	if health.openedAt.IsZero() {
+		return CircuitClosed
+	} else if now.Sub(health.openedAt) < r.cooldown {
+		return CircuitOpen
+	}
+	return CircuitHalfOpen
+}
+
+// observeLatency will add the latency to the average latency (EWMA)
+func (h *providerHealth) observeLatency(latency time.Duration) {
+	if h.averageLatency == 0 {
+		h.averageLatency = latency
+		return
+	}
+	h.averageLatency = time.Duration(
+		latencySmoothingFactor*float64(latency) + (1-latencySmoothingFactor)*float64(h.averageLatency),
+	)
+}
+
+// score will return the score of the provider (0 to 1, higher is better)
+//
+// The success rate (an unknown provider starts at 0.5) is lowered by the average latency in seconds
+func (h *providerHealth) score() float64 {
+	successRate := float64(h.successes+1) / float64(h.successes+h.failures+2)
+	return successRate / (1 + h.averageLatency.Seconds())
+}
+
+// ClassifyBroadcastError will return the error class of a failed broadcast (network, provider, rejected or timeout)
+//
+// The class is also found from the message of the error (IE: the joined errors of all the providers).
+// Only the known rejection codes of the nodes and ARC are rejected, any other error is a failure of the provider
+func ClassifyBroadcastError(err error) string {
+	var netErr interface{ Timeout() bool }
+	var arcErr broadcast.ArcError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout(),
+		doesErrorContain(err.Error(), broadcastTimeoutErrors):
+		return ErrorClassTimeout
+	case errors.As(err, &arcErr):
+		if arcErr.Status >= arcRejectedStatusMin && arcErr.Status <= arcRejectedStatusMax {
+			return ErrorClassRejected
+		}
+		return ErrorClassProvider
+	case doesErrorContain(err.Error(), broadcastNetworkErrors):
+		return ErrorClassNetwork
+	case doesErrorContain(err.Error(), broadcastRejectedErrors):
+		return ErrorClassRejected
+	}
+	return ErrorClassProvider
+}
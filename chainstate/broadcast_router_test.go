@@ -0,0 +1,258 @@
+package chainstate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/go-broadcast-client/broadcast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tonicpow/go-minercraft/v2"
+)
+
+// testBroadcastProvider is a provider used to test the routing
+type testBroadcastProvider struct {
+	name string
+}
+
+func (provider testBroadcastProvider) getName() string {
+	return provider.name
+}
+
+func (provider testBroadcastProvider) broadcast(context.Context, *Client) (*broadcastResponse, error) {
+	return nil, nil
+}
+
+// slowBroadcastProvider is a provider which only answers when the broadcast is cancelled
+type slowBroadcastProvider struct {
+	name string
+}
+
+func (provider slowBroadcastProvider) getName() string {
+	return provider.name
+}
+
+func (provider slowBroadcastProvider) broadcast(ctx context.Context, _ *Client) (*broadcastResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func routedNames(providers []txBroadcastProvider) []string {
+	names := make([]string, 0, len(providers))
+	for _, provider := range providers {
+		names = append(names, provider.getName())
+	}
+	return names
+}
+
+// Test_broadcastRouter will test the routing and the circuit breakers of the broadcast providers
+func Test_broadcastRouter(t *testing.T) {
+	providers := []txBroadcastProvider{
+		testBroadcastProvider{name: "first"},
+		testBroadcastProvider{name: "second"},
+		testBroadcastProvider{name: "third"},
+	}
+	networkErr := errors.New("dial tcp: connection refused")
+
+	t.Run("unknown providers keep their order", func(t *testing.T) {
+		router := newBroadcastRouter(defaultCircuitBreakerThreshold, defaultCircuitBreakerCooldown)
+		assert.Equal(t, []string{"first", "second", "third"}, routedNames(router.route(providers)))
+	})
+
+	t.Run("best provider first", func(t *testing.T) {
+		router := newBroadcastRouter(defaultCircuitBreakerThreshold, defaultCircuitBreakerCooldown)
+		router.recordFailure("first", 10*time.Millisecond, networkErr, ErrorClassNetwork)
+		router.recordSuccess("second", 500*time.Millisecond)
+		router.recordSuccess("third", 10*time.Millisecond)
+		assert.Equal(t, []string{"third", "second", "first"}, routedNames(router.route(providers)))
+	})
+
+	t.Run("circuit opened after consecutive failures", func(t *testing.T) {
+		router := newBroadcastRouter(2, time.Hour)
+		router.recordFailure("first", time.Millisecond, networkErr, ErrorClassNetwork)
+		assert.Equal(t, []string{"second", "third", "first"}, routedNames(router.route(providers)))
+
+		router.recordFailure("first", time.Millisecond, networkErr, ErrorClassNetwork)
+		assert.Equal(t, []string{"second", "third"}, routedNames(router.route(providers)))
+
+		// all the circuits are open: still try them
+		router.recordFailure("second", time.Millisecond, networkErr, ErrorClassNetwork)
+		router.recordFailure("second", time.Millisecond, networkErr, ErrorClassNetwork)
+		router.recordFailure("third", time.Millisecond, networkErr, ErrorClassNetwork)
+		router.recordFailure("third", time.Millisecond, networkErr, ErrorClassNetwork)
+		assert.Len(t, router.route(providers), 3)
+	})
+
+	t.Run("rejected transactions do not open the circuit", func(t *testing.T) {
+		router := newBroadcastRouter(1, time.Hour)
+		router.recordFailure("first", time.Millisecond, errors.New("dust"), ErrorClassRejected)
+		assert.Contains(t, routedNames(router.route(providers)), "first")
+	})
+
+	t.Run("rejected transactions do not lower the score", func(t *testing.T) {
+		router := newBroadcastRouter(defaultCircuitBreakerThreshold, defaultCircuitBreakerCooldown)
+		router.recordSuccess("first", time.Millisecond)
+		router.recordSuccess("second", time.Millisecond)
+		router.recordFailure("first", time.Millisecond, errors.New("dust"), ErrorClassRejected)
+
+		status := router.status()
+		require.Len(t, status, 2)
+		assert.Equal(t, uint64(0), status[0].Failures)
+		assert.Equal(t, status[1].Score, status[0].Score)
+		assert.Equal(t, map[string]uint32{ErrorClassRejected: 1}, status[0].RecentErrorClasses)
+	})
+
+	t.Run("single trial when half-open", func(t *testing.T) {
+		router := newBroadcastRouter(1, time.Millisecond)
+		router.recordFailure("first", time.Millisecond, networkErr, ErrorClassNetwork)
+		time.Sleep(5 * time.Millisecond)
+
+		routed := router.route(providers)
+		assert.Contains(t, routedNames(routed), "first")
+		assert.NotContains(t, routedNames(router.route(providers)), "first")
+
+		// routed but not tried
+		router.release(routed)
+		assert.Contains(t, routedNames(router.route(providers)), "first")
+
+		router.recordSuccess("first", time.Millisecond)
+		status := router.status()
+		require.Len(t, status, 3)
+		assert.Equal(t, "first", status[0].Name)
+		assert.Equal(t, CircuitClosed, status[0].CircuitState)
+	})
+
+	t.Run("status", func(t *testing.T) {
+		router := newBroadcastRouter(1, time.Hour)
+		router.recordSuccess("second", 100*time.Millisecond)
+		router.recordFailure("first", 200*time.Millisecond, networkErr, ErrorClassNetwork)
+		router.recordFailure("first", 200*time.Millisecond, context.DeadlineExceeded, ErrorClassTimeout)
+
+		status := router.status()
+		require.Len(t, status, 2)
+		assert.Equal(t, "first", status[0].Name)
+		assert.Equal(t, CircuitOpen, status[0].CircuitState)
+		assert.Equal(t, uint64(2), status[0].Failures)
+		assert.Equal(t, uint32(2), status[0].ConsecutiveFailures)
+		assert.Equal(t, context.DeadlineExceeded.Error(), status[0].LastError)
+		assert.Equal(t, map[string]uint32{ErrorClassNetwork: 1, ErrorClassTimeout: 1}, status[0].RecentErrorClasses)
+		assert.Equal(t, 200*time.Millisecond, status[0].AverageLatency)
+
+		assert.Equal(t, "second", status[1].Name)
+		assert.Equal(t, CircuitClosed, status[1].CircuitState)
+		assert.Equal(t, uint64(1), status[1].Successes)
+		assert.Greater(t, status[1].Score, status[0].Score)
+	})
+}
+
+// TestClassifyBroadcastError will test the method ClassifyBroadcastError()
+func TestClassifyBroadcastError(t *testing.T) {
+	assert.Equal(t, ErrorClassTimeout, ClassifyBroadcastError(fmt.Errorf("request: %w", context.DeadlineExceeded)))
+	assert.Equal(t, ErrorClassTimeout, ClassifyBroadcastError(errors.New("Taal: context deadline exceeded")))
+	assert.Equal(t, ErrorClassNetwork, ClassifyBroadcastError(errors.New("dial tcp: Connection Refused")))
+	assert.Equal(t, ErrorClassNetwork, ClassifyBroadcastError(errors.New("503 Service Unavailable")))
+	assert.Equal(t, ErrorClassRejected, ClassifyBroadcastError(errors.New("258: txn-mempool-conflict")))
+	assert.Equal(t, ErrorClassRejected, ClassifyBroadcastError(errors.New("-26: 64: dust")))
+	assert.Equal(t, ErrorClassRejected, ClassifyBroadcastError(errors.New("BAD_TXNS_INPUTS_SPENT")))
+	assert.Equal(t, ErrorClassRejected, ClassifyBroadcastError(
+		fmt.Errorf("submit: %w", broadcast.ArcError{Title: "Fee too low", Status: 465}),
+	))
+	assert.Equal(t, ErrorClassProvider, ClassifyBroadcastError(broadcast.ArcError{Title: "Unauthorized", Status: 401}))
+	assert.Equal(t, ErrorClassProvider, ClassifyBroadcastError(broadcast.ErrAllBroadcastersFailed))
+	assert.Equal(t, ErrorClassProvider, ClassifyBroadcastError(errors.New("unexpected response")))
+}
+
+// Test_attemptTimeout will test the timeout of the broadcast attempts
+func Test_attemptTimeout(t *testing.T) {
+	t.Run("most of the timeout to the first attempt", func(t *testing.T) {
+		c := NewTestClient(context.Background(), t, WithMinercraft(&minerCraftBroadcastSuccess{}))
+		first := c.(*Client).attemptTimeout(10*time.Second, 3)
+		assert.Greater(t, first, 5*time.Second)
+		assert.Less(t, first, 10*time.Second)
+		assert.Equal(t, 10*time.Second, c.(*Client).attemptTimeout(10*time.Second, 1))
+	})
+
+	t.Run("configured attempt timeout", func(t *testing.T) {
+		c := NewTestClient(context.Background(), t, WithMinercraft(&minerCraftBroadcastSuccess{}),
+			WithBroadcastAttemptTimeout(2*time.Second))
+		assert.Equal(t, 2*time.Second, c.(*Client).attemptTimeout(10*time.Second, 3))
+		assert.Equal(t, time.Second, c.(*Client).attemptTimeout(time.Second, 3))
+		assert.Equal(t, 10*time.Second, c.(*Client).attemptTimeout(10*time.Second, 1))
+	})
+}
+
+// Test_broadcastToProvider_timeout will test that only the timeouts of the broadcast open the circuit
+func Test_broadcastToProvider_timeout(t *testing.T) {
+	provider := slowBroadcastProvider{name: "slow"}
+
+	t.Run("attempt cut short by its share of the timeout", func(t *testing.T) {
+		c := NewTestClient(context.Background(), t, WithMinercraft(&minerCraftBroadcastSuccess{}),
+			WithCircuitBreaker(1, time.Minute))
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		err := broadcastToProvider(ctx, ctx, provider, broadcastExample1TxID, c.(*Client), 10*time.Millisecond)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		status := c.ProviderStatus()
+		require.Len(t, status, 1)
+		assert.Equal(t, uint64(0), status[0].Failures)
+		assert.Equal(t, CircuitClosed, status[0].CircuitState)
+	})
+
+	t.Run("broadcast timeout", func(t *testing.T) {
+		c := NewTestClient(context.Background(), t, WithMinercraft(&minerCraftBroadcastSuccess{}),
+			WithCircuitBreaker(1, time.Minute))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := broadcastToProvider(ctx, ctx, provider, broadcastExample1TxID, c.(*Client), time.Minute)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		status := c.ProviderStatus()
+		require.Len(t, status, 1)
+		assert.Equal(t, uint64(1), status[0].Failures)
+		assert.Equal(t, CircuitOpen, status[0].CircuitState)
+	})
+}
+
+// TestClient_ProviderStatus will test the method ProviderStatus()
+func TestClient_ProviderStatus(t *testing.T) {
+	c := NewTestClient(context.Background(), t, WithMinercraft(&minerCraftBroadcastSuccess{}))
+	assert.Empty(t, c.ProviderStatus())
+
+	// only Taal accepts the transaction, the other miners are tried first
+	provider, err := c.Broadcast(
+		context.Background(), broadcastExample1TxID, broadcastExample1TxHex, defaultBroadcastTimeOut,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, minercraft.MinerTaal, provider)
+
+	var failures uint64
+	var taal *ProviderStatus
+	for _, status := range c.ProviderStatus() {
+		failures += status.Failures
+		if status.Name == minercraft.MinerTaal {
+			taal = status
+		}
+	}
+	require.NotNil(t, taal)
+	assert.Equal(t, uint64(1), taal.Successes)
+
+	// Taal is now the best provider
+	provider, err = c.Broadcast(
+		context.Background(), broadcastExample1TxID, broadcastExample1TxHex, defaultBroadcastTimeOut,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, minercraft.MinerTaal, provider)
+
+	// the other miners were not tried again
+	var newFailures uint64
+	for _, status := range c.ProviderStatus() {
+		newFailures += status.Failures
+	}
+	assert.Equal(t, failures, newFailures)
+}
@@ -28,6 +28,33 @@ func Test_doesErrorContain(t *testing.T) {
 	})
 }
 
+// Test_isEfHex will test the methods isEfHex() and toRawHex()
+func Test_isEfHex(t *testing.T) {
+	rawHex := "0100000001" + strings.Repeat("00", 32) + "ffffffff00ffffffff0100000000000000000000000000"
+	efHex := "01000000" + "0000000000ef" + "01" + strings.Repeat("00", 32) + "ffffffff00ffffffff" +
+		"e803000000000000" + "0151" + "0100000000000000000000000000"
+
+	t.Run("raw transaction", func(t *testing.T) {
+		assert.False(t, isEfHex(rawHex))
+		hex, err := toRawHex(rawHex)
+		require.NoError(t, err)
+		assert.Equal(t, rawHex, hex)
+	})
+
+	t.Run("extended format transaction", func(t *testing.T) {
+		assert.True(t, isEfHex(efHex))
+		assert.True(t, isEfHex(strings.ToUpper(efHex)))
+		hex, err := toRawHex(efHex)
+		require.NoError(t, err)
+		assert.Equal(t, rawHex, hex)
+	})
+
+	t.Run("invalid extended format transaction", func(t *testing.T) {
+		_, err := toRawHex("010000000000000000ef01")
+		require.Error(t, err)
+	})
+}
+
 // TestClient_Broadcast will test the method Broadcast()
 func TestClient_Broadcast(t *testing.T) {
 	t.Parallel()
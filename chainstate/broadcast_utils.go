@@ -3,62 +3,9 @@ package chainstate
 import (
 	"fmt"
 	"strings"
-	"sync"
-)
-
-// struct handles communication with the client - returns first successful broadcast
-type broadcastStatus struct {
-	mu          *sync.Mutex
-	complete    bool
-	success     bool
-	syncChannel chan string
-}
-
-func newBroadcastStatus(synchChannel chan string) *broadcastStatus {
-	return &broadcastStatus{complete: false, syncChannel: synchChannel, mu: &sync.Mutex{}}
-}
-
-func (g *broadcastStatus) tryCompleteWithSuccess(fastestProvider string) {
-	g.mu.Lock()
-	defer g.mu.Unlock()
-
-	if !g.complete {
-		g.complete = true
-		g.success = true
-
-		g.syncChannel <- fastestProvider
-		close(g.syncChannel)
-	}
-
-	// g.mu.Unlock() is done by defer
-}
-
-func (g *broadcastStatus) dispose() {
-	g.mu.Lock()
-	defer g.mu.Unlock()
-
-	if !g.complete {
-		g.complete = true
-		close(g.syncChannel) // have to close the channel here to not block client
-	}
 
-	// g.mu.Unlock() is done by defer
-}
-
-// result of single broadcast to provider
-type broadcastResult struct {
-	isError  bool
-	err      error
-	provider string
-}
-
-func newErrorResult(err error, provider string) broadcastResult {
-	return broadcastResult{isError: true, err: err, provider: provider}
-}
-
-func newSuccessResult(provider string) broadcastResult {
-	return broadcastResult{isError: false, provider: provider}
-}
+	"github.com/libsv/go-bt/v2"
+)
 
 // doesErrorContain will look at a string for a list of strings
 func doesErrorContain(err string, messages []string) bool {
@@ -74,3 +21,21 @@ func doesErrorContain(err string, messages []string) bool {
 func debugLog(c ClientInterface, txID, msg string) {
 	c.DebugLog(fmt.Sprintf("[txID: %s]: %s", txID, msg))
 }
+
+// isEfHex will return true if the transaction hex is in Extended Format (BRC-30)
+func isEfHex(txHex string) bool {
+	// version (4 bytes) followed by the EF marker (0000000000EF)
+	return len(txHex) > 20 && strings.EqualFold(txHex[8:20], "0000000000ef")
+}
+
+// toRawHex will convert the transaction hex (raw or Extended Format) to the raw transaction hex
+func toRawHex(txHex string) (string, error) {
+	if !isEfHex(txHex) {
+		return txHex, nil
+	}
+	tx, err := bt.NewTxFromString(txHex)
+	if err != nil {
+		return "", err
+	}
+	return tx.String(), nil
+}
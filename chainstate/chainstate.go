@@ -10,6 +10,8 @@ import (
 )
 
 // Broadcast will attempt to broadcast a transaction using the given providers
+//
+// txHex can be the raw transaction or the transaction in Extended Format (BRC-30)
 func (c *Client) Broadcast(ctx context.Context, id, txHex string, timeout time.Duration) (string, error) {
 	// Basic validation
 	if len(id) < 50 {
@@ -23,20 +25,16 @@ func (c *Client) Broadcast(ctx context.Context, id, txHex string, timeout time.D
 	c.DebugLog("tx_hex: " + txHex)
 
 	// Broadcast or die
-	successCompleteCh := make(chan string)
-	errorCh := make(chan string)
-
-	go c.broadcast(ctx, id, txHex, timeout, successCompleteCh, errorCh)
-
-	// wait for first success
-	success := <-successCompleteCh
-	if success != "" {
-		return success, nil
+	provider, err := c.broadcast(ctx, id, txHex, timeout)
+	if err != nil {
+		return ProviderAll, fmt.Errorf("broadcast failed, errors: %w", err)
 	}
+	return provider, nil
+}
 
-	// successCompleteCh closed without any values
-	errorMessage := <-errorCh
-	return ProviderAll, fmt.Errorf("broadcast failed, errors: %s", errorMessage)
+// ProviderStatus will return the health of the broadcast providers (success rate, latency, circuit state)
+func (c *Client) ProviderStatus() []*ProviderStatus {
+	return c.options.config.broadcastRouter.status()
 }
 
 // QueryTransaction will get the transaction info from all providers returning the "first" valid result
@@ -58,6 +56,8 @@ func (c *Client) QueryTransaction(
 		return nil, ErrInvalidTransactionID
 	} else if !c.validRequirement(requiredIn) {
 		return nil, ErrInvalidRequirements
+	} else if c.options.config.spvOnly {
+		return nil, ErrTransactionLookupDisabled
 	}
 
 	// Try all providers and return the "first" valid response
@@ -79,6 +79,8 @@ func (c *Client) QueryTransactionFastest(
 		return nil, ErrInvalidTransactionID
 	} else if !c.validRequirement(requiredIn) {
 		return nil, ErrInvalidRequirements
+	} else if c.options.config.spvOnly {
+		return nil, ErrTransactionLookupDisabled
 	}
 
 	// Try all providers and return the "fastest" valid response
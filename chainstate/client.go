@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/BuxOrg/bux/logging"
@@ -40,12 +41,23 @@ type (
 		httpClient        HTTPInterface              // Custom HTTP client (Minercraft, WOC)
 		minercraftConfig  *minercraftConfig          // minercraftConfig configuration
 		minercraft        minercraft.ClientInterface // Minercraft client
-		network           Network                    // Current network (mainnet, testnet, stn)
+		network           Network                    // Current network (mainnet, testnet, stn, regtest)
+		node              *nodeConfig                // Bitcoind-compatible node (IE: a local regtest node)
 		queryTimeout      time.Duration              // Timeout for transaction query
+		rateLimits        providerRateLimits         // Rate limits of the providers (token buckets)
 		broadcastClient   broadcast.Client           // Broadcast client
+		attemptTimeout    time.Duration              // Timeout of a broadcast attempt when other providers are left (if set)
+		broadcastRouter   *broadcastRouter           // Health of the broadcast providers (routing & circuit breakers)
+		broadcastAttempts BroadcastAttemptRecorder   // Recorder of the broadcast attempts (if set)
 		pulseClient       *pulseClientProvider       // Pulse client
+		headerService     HeaderService              // Header service used instead of Pulse (IE: local header store)
 		feeUnit           *utils.FeeUnit             // The lowest fees among all miners
+		feeUnitLock       sync.RWMutex               // Guards the feeUnit (refreshed in the background)
+		feeUnitPolicy     FeeUnitPolicy              // Policy to select the feeUnit among the fee quotes
 		feeQuotes         bool                       // If set, feeUnit will be updated with fee quotes from miner's
+		feePriorities     FeePriorities              // Multipliers of the fee priorities over the feeUnit
+		providerFeeUnits  map[string]utils.FeeUnit   // Latest fee quote of each broadcast provider (guarded by feeUnitLock)
+		spvOnly           bool                       // If set, transactions are never queried from the providers
 	}
 
 	// minercraftConfig is specific for minercraft configuration
@@ -151,12 +163,18 @@ func (c *Client) QueryTimeout() time.Duration {
 
 // FeeUnit will return feeUnit
 func (c *Client) FeeUnit() *utils.FeeUnit {
+	c.options.config.feeUnitLock.RLock()
+	defer c.options.config.feeUnitLock.RUnlock()
+
 	return c.options.config.feeUnit
 }
 
 // ActiveProvider returns a name of a provider based on config.
 func (c *Client) ActiveProvider() string {
 	excluded := c.options.config.excludedProviders
+	if !utils.StringInSlice(ProviderNode, excluded) && c.options.config.node != nil {
+		return ProviderNode
+	}
 	if !utils.StringInSlice(ProviderBroadcastClient, excluded) && c.BroadcastClient() != nil {
 		return ProviderBroadcastClient
 	}
@@ -176,6 +194,8 @@ func (c *Client) initActiveProvider(ctx context.Context) error {
 		return c.minercraftInit(ctx)
 	case ProviderBroadcastClient:
 		return c.broadcastClientInit(ctx)
+	case ProviderNode:
+		return c.nodeInit(ctx)
 	default:
 		return errors.New("no active provider found")
 	}
@@ -280,3 +280,31 @@ func TestWithExcludedProviders(t *testing.T) {
 		assert.Equal(t, ProviderBroadcastClient, options.config.excludedProviders[0])
 	})
 }
+
+// TestWithBroadcastAttemptTimeout will test the method WithBroadcastAttemptTimeout()
+func TestWithBroadcastAttemptTimeout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("check type", func(t *testing.T) {
+		opt := WithBroadcastAttemptTimeout(0)
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying empty value", func(t *testing.T) {
+		options := &clientOptions{
+			config: &syncConfig{},
+		}
+		opt := WithBroadcastAttemptTimeout(0)
+		opt(options)
+		assert.Equal(t, time.Duration(0), options.config.attemptTimeout)
+	})
+
+	t.Run("test applying option", func(t *testing.T) {
+		options := &clientOptions{
+			config: &syncConfig{},
+		}
+		opt := WithBroadcastAttemptTimeout(5 * time.Second)
+		opt(options)
+		assert.Equal(t, 5*time.Second, options.config.attemptTimeout)
+	})
+}
@@ -26,6 +26,7 @@ const (
 const (
 	mainNet    = "mainnet" // Main Public Bitcoin network
 	mainNetAlt = "main"    // Main Public Bitcoin network
+	regTest    = "regtest" // Regression test network (local node)
 	stn        = "stn"     // BitcoinSV Public Stress Test Network (https://bitcoinscaling.io/)
 	testNet    = "testnet" // Public test network
 	testNetAlt = "test"    // Public test network
@@ -44,6 +45,7 @@ const (
 	ProviderAll             = "all"             // All providers (used for errors etc)
 	ProviderMinercraft      = "minercraft"      // Query & broadcast provider for mAPI (using given miners)
 	ProviderBroadcastClient = "broadcastclient" // Query & broadcast provider for configured miners
+	ProviderNode            = "node"            // Query & broadcast provider for a bitcoind-compatible node (JSON-RPC)
 	ProviderNone            = "none"            // No providers (used to indicate no providers)
 )
 
@@ -14,11 +14,32 @@ var ErrTransactionIDMismatch = errors.New("result tx id did not match provided t
 // ErrTransactionNotFound is when a transaction was not found in any on-chain provider
 var ErrTransactionNotFound = errors.New("transaction not found using all chain providers")
 
+// ErrTransactionLookupDisabled is when a transaction is queried in SPV only mode
+var ErrTransactionLookupDisabled = errors.New("transaction lookup is disabled in SPV only mode")
+
 // ErrInvalidRequirements is when an invalid requirement was given
 var ErrInvalidRequirements = errors.New("requirements are invalid or missing")
 
+// ErrFeeQuotesDisabled is when the fee unit is refreshed but the fee quotes are disabled
+var ErrFeeQuotesDisabled = errors.New("fee quotes are disabled")
+
+// ErrInvalidFeePriority is when the fee priority is unknown
+var ErrInvalidFeePriority = errors.New("invalid fee priority")
+
+// ErrMissingFeeUnit is when the fee unit is missing or invalid
+var ErrMissingFeeUnit = errors.New("missing: fee unit")
+
+// ErrMissingFeeQuotes is when no fee quote was returned by the providers
+var ErrMissingFeeQuotes = errors.New("missing: fee quotes")
+
 // ErrMissingBroadcastMiners is when broadcasting miners are missing
 var ErrMissingBroadcastMiners = errors.New("missing: broadcasting miners")
 
 // ErrMissingQueryMiners is when query miners are missing
 var ErrMissingQueryMiners = errors.New("missing: query miners")
+
+// ErrMissingBroadcastProviders is when there is no provider to broadcast with
+var ErrMissingBroadcastProviders = errors.New("missing: broadcast providers")
+
+// ErrBroadcastProviderNotFound is when the pinned broadcast provider is not an active provider
+var ErrBroadcastProviderNotFound = errors.New("pinned broadcast provider not found")
@@ -0,0 +1,114 @@
+package chainstate
+
+import (
+	"math"
+	"sort"
+
+	"github.com/BuxOrg/bux/utils"
+	"github.com/libsv/go-bt/v2"
+)
+
+// FeePriority is a named fee level, a multiplier over the fee unit (current miner quote)
+type FeePriority string
+
+// Fee priorities
+const (
+	FeePriorityEconomy  FeePriority = "economy"  // The fee unit (lowest miner quote)
+	FeePriorityNormal   FeePriority = "normal"   // 1.5x the fee unit
+	FeePriorityPriority FeePriority = "priority" // 2x the fee unit
+)
+
+// feePriorityBytes are the bytes of the fee units computed with a multiplier (precision of the satoshis)
+const feePriorityBytes = 1000
+
+// FeePriorities are the multipliers of the fee priorities over the fee unit
+type FeePriorities map[FeePriority]float64
+
+// DefaultFeePriorities will return the default multipliers of the fee priorities
+func DefaultFeePriorities() FeePriorities {
+	return FeePriorities{
+		FeePriorityEconomy:  1,
+		FeePriorityNormal:   1.5,
+		FeePriorityPriority: 2,
+	}
+}
+
+// FeeUnit will return the fee unit of the priority (rounded up to the satoshi per 1000 bytes)
+func (p FeePriorities) FeeUnit(feeUnit *utils.FeeUnit, priority FeePriority) (*utils.FeeUnit, error) {
+	multiplier, ok := p[priority]
+	if !ok || multiplier <= 0 {
+		return nil, ErrInvalidFeePriority
+	} else if feeUnit == nil || !feeUnit.IsValid() {
+		return nil, ErrMissingFeeUnit
+	}
+
+	if multiplier == 1 {
+		return &utils.FeeUnit{Satoshis: feeUnit.Satoshis, Bytes: feeUnit.Bytes}, nil
+	}
+	return &utils.FeeUnit{
+		Satoshis: int(math.Ceil(float64(feeUnit.Satoshis) * multiplier * feePriorityBytes / float64(feeUnit.Bytes))),
+		Bytes:    feePriorityBytes,
+	}, nil
+}
+
+// FeeUnitForPriority will return the fee unit of the priority, a multiplier over the current fee unit
+func (c *Client) FeeUnitForPriority(priority FeePriority) (*utils.FeeUnit, error) {
+	return c.options.config.feePriorities.FeeUnit(c.FeeUnit(), priority)
+}
+
+// setProviderFeeUnits will set the latest fee quote of each broadcast provider
+func (c *Client) setProviderFeeUnits(feeUnits map[string]utils.FeeUnit) {
+	c.options.config.feeUnitLock.Lock()
+	defer c.options.config.feeUnitLock.Unlock()
+
+	c.options.config.providerFeeUnits = feeUnits
+}
+
+// preferFeeSatisfied will move first the providers with a fee quote satisfied by the fee of the transaction
+//
+// The order of the providers (health) is kept otherwise, nothing changes if the fee of the transaction is unknown
+// (IE: inputs without satoshis, not in Extended Format)
+func (c *Client) preferFeeSatisfied(providers []txBroadcastProvider, txHex string) []txBroadcastProvider {
+	c.options.config.feeUnitLock.RLock()
+	feeUnits := c.options.config.providerFeeUnits
+	c.options.config.feeUnitLock.RUnlock()
+	if len(feeUnits) == 0 || len(providers) < 2 {
+		return providers
+	}
+
+	txFee := transactionFeeUnit(txHex)
+	if txFee == nil {
+		return providers
+	}
+
+	satisfied := func(provider txBroadcastProvider) bool {
+		quote, ok := feeUnits[provider.getName()]
+		return ok && !txFee.IsLowerThan(&quote)
+	}
+	sort.SliceStable(providers, func(i, j int) bool {
+		return satisfied(providers[i]) && !satisfied(providers[j])
+	})
+	return providers
+}
+
+// transactionFeeUnit will return the fee unit paid by the transaction (nil if the input satoshis are unknown)
+func transactionFeeUnit(txHex string) *utils.FeeUnit {
+	tx, err := bt.NewTxFromString(txHex)
+	if err != nil || len(tx.Inputs) == 0 {
+		return nil
+	}
+
+	var inputs uint64
+	for _, input := range tx.Inputs {
+		if input.PreviousTxSatoshis == 0 {
+			return nil
+		}
+		inputs += input.PreviousTxSatoshis
+	}
+	outputs := tx.TotalOutputSatoshis()
+	if inputs < outputs {
+		return nil
+	}
+
+	return &utils.FeeUnit{Satoshis: int(inputs - outputs), Bytes: tx.Size()}
+}
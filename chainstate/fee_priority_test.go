@@ -0,0 +1,102 @@
+package chainstate
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/BuxOrg/bux/utils"
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestFeeTx will return a transaction paying the fee of (inputs - outputs) satoshis
+func newTestFeeTx(t *testing.T, inputs, outputs uint64) *bt.Tx {
+	lockingScript := "76a9147ff514e6ae3deb46e6644caac5cdd0bf2388906588ac"
+	script, err := bscript.NewFromHexString(lockingScript)
+	require.NoError(t, err)
+
+	tx := bt.NewTx()
+	require.NoError(t, tx.From(
+		"b2a2f4c4b9d6d2a1e4bcd6b0dfd8ec0ff2b6d64e8e4b2c7f1bd0a4d2f6c1e3a9", 0, lockingScript, inputs,
+	))
+	tx.AddOutput(&bt.Output{LockingScript: script, Satoshis: outputs})
+	return tx
+}
+
+// TestFeePriorities_FeeUnit will test the method FeeUnit()
+func TestFeePriorities_FeeUnit(t *testing.T) {
+	priorities := DefaultFeePriorities()
+	feeUnit := &utils.FeeUnit{Satoshis: 1, Bytes: 20}
+
+	economy, err := priorities.FeeUnit(feeUnit, FeePriorityEconomy)
+	require.NoError(t, err)
+	assert.Equal(t, feeUnit, economy)
+
+	var normal *utils.FeeUnit
+	normal, err = priorities.FeeUnit(feeUnit, FeePriorityNormal)
+	require.NoError(t, err)
+	assert.Equal(t, &utils.FeeUnit{Satoshis: 75, Bytes: 1000}, normal)
+
+	var priority *utils.FeeUnit
+	priority, err = priorities.FeeUnit(feeUnit, FeePriorityPriority)
+	require.NoError(t, err)
+	assert.Equal(t, &utils.FeeUnit{Satoshis: 100, Bytes: 1000}, priority)
+
+	_, err = priorities.FeeUnit(feeUnit, "unknown")
+	require.ErrorIs(t, err, ErrInvalidFeePriority)
+
+	_, err = priorities.FeeUnit(nil, FeePriorityNormal)
+	require.ErrorIs(t, err, ErrMissingFeeUnit)
+}
+
+// TestClient_FeeUnitForPriority will test the method FeeUnitForPriority()
+func TestClient_FeeUnitForPriority(t *testing.T) {
+	c := &Client{options: defaultClientOptions()}
+	c.options.config.feeUnit = &utils.FeeUnit{Satoshis: 1, Bytes: 1000}
+	WithFeePriorities(FeePriorities{FeePriorityPriority: 5, FeePriorityNormal: -1})(c.options)
+
+	feeUnit, err := c.FeeUnitForPriority(FeePriorityPriority)
+	require.NoError(t, err)
+	assert.Equal(t, &utils.FeeUnit{Satoshis: 5, Bytes: 1000}, feeUnit)
+
+	// invalid multipliers are ignored
+	feeUnit, err = c.FeeUnitForPriority(FeePriorityNormal)
+	require.NoError(t, err)
+	assert.Equal(t, &utils.FeeUnit{Satoshis: 2, Bytes: 1000}, feeUnit)
+}
+
+// Test_preferFeeSatisfied will test the providers preferred for the fee of the transaction
+func Test_preferFeeSatisfied(t *testing.T) {
+	c := &Client{options: defaultClientOptions()}
+	c.setProviderFeeUnits(map[string]utils.FeeUnit{
+		"expensive": {Satoshis: 500, Bytes: 1000},
+		"cheap":     {Satoshis: 1, Bytes: 1000},
+	})
+	newProviders := func() []txBroadcastProvider {
+		return []txBroadcastProvider{
+			testBroadcastProvider{name: "expensive"},
+			testBroadcastProvider{name: "unknown"},
+			testBroadcastProvider{name: "cheap"},
+		}
+	}
+
+	t.Run("low fee", func(t *testing.T) {
+		tx := newTestFeeTx(t, 1000, 999)
+		providers := c.preferFeeSatisfied(newProviders(), hex.EncodeToString(tx.ExtendedBytes()))
+		assert.Equal(t, []string{"cheap", "expensive", "unknown"}, routedNames(providers))
+	})
+
+	t.Run("high fee", func(t *testing.T) {
+		tx := newTestFeeTx(t, 1000, 500)
+		providers := c.preferFeeSatisfied(newProviders(), hex.EncodeToString(tx.ExtendedBytes()))
+		assert.Equal(t, []string{"expensive", "cheap", "unknown"}, routedNames(providers))
+	})
+
+	t.Run("unknown fee (not in Extended Format)", func(t *testing.T) {
+		tx := newTestFeeTx(t, 1000, 999)
+		providers := c.preferFeeSatisfied(newProviders(), tx.String())
+		assert.Equal(t, []string{"expensive", "unknown", "cheap"}, routedNames(providers))
+	})
+}
@@ -0,0 +1,133 @@
+package chainstate
+
+import (
+	"context"
+	"errors"
+
+	"github.com/BuxOrg/bux/utils"
+	"github.com/newrelic/go-agent/v3/newrelic"
+)
+
+// FeeUnitPolicy is the policy to select the fee unit among the fee quotes of the providers
+//
+// The lowest quote is selected (see utils.LowestFee), quotes above MaxFeeUnit are ignored
+// and the selected fee unit is never lower than MinFeeUnit
+type FeeUnitPolicy struct {
+	MaxFeeUnit *utils.FeeUnit `json:"max_fee_unit"` // Ignore the quotes above (nil: no maximum)
+	MinFeeUnit *utils.FeeUnit `json:"min_fee_unit"` // Never select a fee unit below (nil: no minimum)
+}
+
+// FeeUnitRefresh is the result of a fee unit refresh
+type FeeUnitRefresh struct {
+	Changed  bool            `json:"changed"`  // If the fee unit changed
+	FeeUnit  *utils.FeeUnit  `json:"fee_unit"` // The selected fee unit
+	Previous *utils.FeeUnit  `json:"previous"` // The fee unit before the refresh
+	Provider string          `json:"provider"` // The provider of the fee quotes
+	Quotes   []utils.FeeUnit `json:"quotes"`   // The fee quotes of the provider (miners)
+}
+
+// RefreshFeeUnit will request the fee quotes of the active provider and select the fee unit (see FeeUnitPolicy)
+//
+// The current fee unit is kept if the provider returned no quote (ErrMissingFeeQuotes)
+func (c *Client) RefreshFeeUnit(ctx context.Context) (*FeeUnitRefresh, error) {
+	if txn := newrelic.FromContext(ctx); txn != nil {
+		defer txn.StartSegment("refresh_fee_unit").End()
+	}
+
+	if !c.isFeeQuotesEnabled() {
+		return nil, ErrFeeQuotesDisabled
+	}
+
+	quotes, err := c.feeQuotes(ctx)
+	if err != nil {
+		return nil, err
+	} else if len(quotes) == 0 {
+		return nil, ErrMissingFeeQuotes
+	}
+
+	previous := c.FeeUnit()
+	feeUnit := c.selectFeeUnit(quotes, previous)
+	c.SetFeeUnit(feeUnit)
+
+	return &FeeUnitRefresh{
+		Changed:  *feeUnit != *previous,
+		FeeUnit:  feeUnit,
+		Previous: previous,
+		Provider: c.ActiveProvider(),
+		Quotes:   quotes,
+	}, nil
+}
+
+// SetFeeUnit will set the fee unit (IE: selected by another node of the cluster)
+func (c *Client) SetFeeUnit(feeUnit *utils.FeeUnit) {
+	if feeUnit == nil || !feeUnit.IsValid() {
+		return
+	}
+
+	c.options.config.feeUnitLock.Lock()
+	defer c.options.config.feeUnitLock.Unlock()
+
+	c.options.config.feeUnit = feeUnit
+}
+
+// feeQuotes will request the fee quotes of the active provider
+func (c *Client) feeQuotes(ctx context.Context) ([]utils.FeeUnit, error) {
+	switch c.ActiveProvider() {
+	case ProviderMinercraft:
+		mi := &minercraftInitializer{client: c, ctx: ctx, minersWithFee: make(minerToFeeMap)}
+		quotes := make([]utils.FeeUnit, 0, len(c.options.config.minercraftConfig.broadcastMiners))
+		for _, miner := range c.options.config.minercraftConfig.broadcastMiners {
+			feeUnit, err := mi.getFeeQuote(ctx, miner)
+			if err != nil {
+				c.options.logger.Warn().Msgf("No FeeQuote response from miner %s. Reason: %s", miner.Name, err)
+				continue
+			}
+			mi.addToMinersWithFee(miner, feeUnit)
+			quotes = append(quotes, *feeUnit)
+		}
+		if len(quotes) > 0 {
+			c.setProviderFeeUnits(mi.feeUnitsByName())
+		}
+		return quotes, nil
+	case ProviderBroadcastClient:
+		feeQuotes, err := c.BroadcastClient().GetFeeQuote(ctx)
+		if err != nil {
+			return nil, err
+		}
+		quotes := make([]utils.FeeUnit, len(feeQuotes))
+		for index, fee := range feeQuotes {
+			quotes[index] = utils.FeeUnit{
+				Satoshis: int(fee.MiningFee.Satoshis),
+				Bytes:    int(fee.MiningFee.Bytes),
+			}
+		}
+		return quotes, nil
+	case ProviderNode:
+		feeUnit, err := nodeFeeQuote(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		return []utils.FeeUnit{*feeUnit}, nil
+	default:
+		return nil, errors.New("no active provider for fee quotes")
+	}
+}
+
+// selectFeeUnit will select the fee unit among the quotes using the fee unit policy
+func (c *Client) selectFeeUnit(quotes []utils.FeeUnit, defaultValue *utils.FeeUnit) *utils.FeeUnit {
+	policy := c.options.config.feeUnitPolicy
+
+	accepted := make([]utils.FeeUnit, 0, len(quotes))
+	for _, quote := range utils.ValidFees(quotes) {
+		if policy.MaxFeeUnit != nil && policy.MaxFeeUnit.IsLowerThan(&quote) {
+			continue
+		}
+		accepted = append(accepted, quote)
+	}
+
+	feeUnit := utils.LowestFee(accepted, defaultValue)
+	if policy.MinFeeUnit != nil && feeUnit != nil && feeUnit.IsLowerThan(policy.MinFeeUnit) {
+		return policy.MinFeeUnit
+	}
+	return feeUnit
+}
@@ -0,0 +1,91 @@
+package chainstate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/BuxOrg/bux/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_selectFeeUnit will test the method selectFeeUnit()
+func TestClient_selectFeeUnit(t *testing.T) {
+	quotes := []utils.FeeUnit{
+		{Satoshis: 1, Bytes: 1000},
+		{Satoshis: 5, Bytes: 1000},
+		{Satoshis: 0, Bytes: 0}, // invalid
+	}
+	defaultFee := &utils.FeeUnit{Satoshis: 50, Bytes: 1000}
+
+	t.Run("lowest quote", func(t *testing.T) {
+		c := &Client{options: defaultClientOptions()}
+		assert.Equal(t, &quotes[0], c.selectFeeUnit(quotes, defaultFee))
+		assert.Equal(t, defaultFee, c.selectFeeUnit(nil, defaultFee))
+	})
+
+	t.Run("quotes above the maximum are ignored", func(t *testing.T) {
+		c := &Client{options: defaultClientOptions()}
+		c.options.config.feeUnitPolicy.MaxFeeUnit = &utils.FeeUnit{Satoshis: 1, Bytes: 2000}
+		assert.Equal(t, defaultFee, c.selectFeeUnit(quotes, defaultFee))
+
+		c.options.config.feeUnitPolicy.MaxFeeUnit = &utils.FeeUnit{Satoshis: 3, Bytes: 1000}
+		assert.Equal(t, &quotes[0], c.selectFeeUnit(quotes, defaultFee))
+	})
+
+	t.Run("never below the minimum", func(t *testing.T) {
+		c := &Client{options: defaultClientOptions()}
+		minFee := &utils.FeeUnit{Satoshis: 3, Bytes: 1000}
+		c.options.config.feeUnitPolicy.MinFeeUnit = minFee
+		assert.Equal(t, minFee, c.selectFeeUnit(quotes, defaultFee))
+	})
+}
+
+// TestClient_RefreshFeeUnit will test the method RefreshFeeUnit()
+func TestClient_RefreshFeeUnit(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("fee quotes disabled", func(t *testing.T) {
+		c, err := NewClient(ctx, WithMinercraft(&MinerCraftBase{}), WithFeeQuotes(false), WithFeeUnit(MockDefaultFee))
+		require.NoError(t, err)
+
+		_, err = c.RefreshFeeUnit(ctx)
+		require.ErrorIs(t, err, ErrFeeQuotesDisabled)
+	})
+
+	t.Run("unchanged fee unit", func(t *testing.T) {
+		c, err := NewClient(ctx, WithMinercraft(&MinerCraftBase{}), WithFeeQuotes(true))
+		require.NoError(t, err)
+
+		var refresh *FeeUnitRefresh
+		refresh, err = c.RefreshFeeUnit(ctx)
+		require.NoError(t, err)
+		assert.False(t, refresh.Changed)
+		assert.Equal(t, *MockDefaultFee, *refresh.FeeUnit)
+		assert.Equal(t, ProviderMinercraft, refresh.Provider)
+		assert.NotEmpty(t, refresh.Quotes)
+	})
+
+	t.Run("changed by the policy", func(t *testing.T) {
+		minFee := &utils.FeeUnit{Satoshis: 1, Bytes: 10}
+		c, err := NewClient(
+			ctx, WithMinercraft(&MinerCraftBase{}), WithFeeQuotes(true),
+			WithFeeUnitPolicy(FeeUnitPolicy{MinFeeUnit: minFee}),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, minFee, c.FeeUnit()) // policy applied on start
+
+		previous := &utils.FeeUnit{Satoshis: 5, Bytes: 10}
+		c.SetFeeUnit(previous)
+		c.SetFeeUnit(&utils.FeeUnit{}) // invalid, ignored
+		assert.Equal(t, previous, c.FeeUnit())
+
+		var refresh *FeeUnitRefresh
+		refresh, err = c.RefreshFeeUnit(ctx)
+		require.NoError(t, err)
+		assert.True(t, refresh.Changed)
+		assert.Equal(t, previous, refresh.Previous)
+		assert.Equal(t, minFee, refresh.FeeUnit)
+		assert.Equal(t, minFee, c.FeeUnit())
+	})
+}
@@ -18,6 +18,7 @@ type HTTPInterface interface {
 // ChainService is the chain related methods
 type ChainService interface {
 	Broadcast(ctx context.Context, id, txHex string, timeout time.Duration) (string, error)
+	ProviderStatus() []*ProviderStatus
 	QueryTransaction(
 		ctx context.Context, id string, requiredIn RequiredIn, timeout time.Duration,
 	) (*TransactionInfo, error)
@@ -37,6 +38,11 @@ type HeaderService interface {
 	VerifyMerkleRoots(ctx context.Context, merkleRoots []MerkleRootConfirmationRequestItem) error
 }
 
+// BroadcastAttemptRecorder is the recorder of the broadcast attempts (IE: the broadcast history of bux)
+type BroadcastAttemptRecorder interface {
+	RecordBroadcastAttempt(ctx context.Context, attempt *BroadcastAttempt) error
+}
+
 // ClientInterface is the chainstate client interface
 type ClientInterface interface {
 	ChainService
@@ -51,4 +57,7 @@ type ClientInterface interface {
 	Network() Network
 	QueryTimeout() time.Duration
 	FeeUnit() *utils.FeeUnit
+	FeeUnitForPriority(priority FeePriority) (*utils.FeeUnit, error)
+	RefreshFeeUnit(ctx context.Context) (*FeeUnitRefresh, error)
+	SetFeeUnit(feeUnit *utils.FeeUnit)
 }
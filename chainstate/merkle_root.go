@@ -7,7 +7,12 @@ import (
 
 // VerifyMerkleRoots will try to verify merkle roots with all available providers
 // When no error is returned, it means that the pulse client responded with state: Confirmed or UnableToVerify
+// If a header service is configured (WithHeaderService), it is used instead of the pulse client
 func (c *Client) VerifyMerkleRoots(ctx context.Context, merkleRoots []MerkleRootConfirmationRequestItem) error {
+	if hs := c.options.config.headerService; hs != nil {
+		return hs.VerifyMerkleRoots(ctx, merkleRoots)
+	}
+
 	pc := c.options.config.pulseClient
 	if pc == nil {
 		c.options.logger.Warn().Msg("VerifyMerkleRoots is called even though no pulse client is configured; this likely indicates that the paymail capabilities have been cached.")
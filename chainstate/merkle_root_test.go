@@ -3,6 +3,7 @@ package chainstate
 import (
 	"bytes"
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/jarcoal/httpmock"
@@ -108,6 +109,34 @@ func TestVerifyMerkleRoots(t *testing.T) {
 		assert.False(t, bLogger.contains("ERR"))
 		assert.False(t, bLogger.contains("WARN"))
 	})
+
+	t.Run("header service is used instead of pulse", func(t *testing.T) {
+		httpmock.Reset()
+		hs := &mockHeaderService{err: errors.New("not confirmed")}
+		c, _ := initMockClient(WithConnectionToPulse(mockURL, ""), WithHeaderService(hs))
+
+		err := c.VerifyMerkleRoots(context.Background(), []MerkleRootConfirmationRequestItem{
+			{
+				MerkleRoot:  "some-merkle-root",
+				BlockHeight: 1,
+			},
+		})
+
+		assert.ErrorIs(t, err, hs.err)
+		assert.Len(t, hs.merkleRoots, 1)
+		assert.Equal(t, 0, httpmock.GetTotalCallCount())
+	})
+}
+
+// mockHeaderService is a header service returning the given error
+type mockHeaderService struct {
+	err         error
+	merkleRoots []MerkleRootConfirmationRequestItem
+}
+
+func (m *mockHeaderService) VerifyMerkleRoots(_ context.Context, merkleRoots []MerkleRootConfirmationRequestItem) error {
+	m.merkleRoots = merkleRoots
+	return m.err
 }
 
 // buffLogger allows to check if a certain string was logged
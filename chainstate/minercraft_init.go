@@ -29,6 +29,7 @@ func (c *Client) minercraftInit(ctx context.Context) error {
 	if c.isFeeQuotesEnabled() {
 		c.options.config.feeUnit = mi.lowestFee()
 	}
+	c.setProviderFeeUnits(mi.feeUnitsByName())
 
 	return nil
 }
@@ -170,12 +171,22 @@ func (i *minercraftInitializer) deleteUnreachableMiners() {
 	c.options.config.minercraftConfig.broadcastMiners = validMiners
 }
 
-// lowestFees takes the lowest fees among all miners and sets them as the feeUnit for future transactions
+// feeUnitsByName will return the fee units of the reachable miners by miner name
+func (i *minercraftInitializer) feeUnitsByName() map[string]utils.FeeUnit {
+	feeUnits := make(map[string]utils.FeeUnit, len(i.minersWithFee))
+	for _, miner := range i.client.options.config.minercraftConfig.broadcastMiners {
+		if fee, ok := i.minersWithFee[minerID(miner.MinerID)]; ok {
+			feeUnits[miner.Name] = fee
+		}
+	}
+	return feeUnits
+}
+
+// lowestFee takes the lowest fees among all miners (see FeeUnitPolicy) and sets them as the feeUnit for future transactions
 func (i *minercraftInitializer) lowestFee() *utils.FeeUnit {
 	fees := make([]utils.FeeUnit, 0)
 	for _, fee := range i.minersWithFee {
 		fees = append(fees, fee)
 	}
-	lowest := utils.LowestFee(fees, i.client.options.config.feeUnit)
-	return lowest
+	return i.client.selectFeeUnit(fees, i.client.options.config.feeUnit)
 }
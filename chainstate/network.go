@@ -6,6 +6,7 @@ type Network string
 // Supported networks
 const (
 	MainNet       Network = mainNet // Main public network
+	RegTestNet    Network = regTest // Regression test network (IE: a local node)
 	StressTestNet Network = stn     // Stress Test Network (https://bitcoinscaling.io/)
 	TestNet       Network = testNet // Test public network
 )
@@ -24,6 +25,8 @@ func (n Network) Alternate() string {
 		return testNetAlt
 	case StressTestNet:
 		return stn
+	case RegTestNet:
+		return regTest
 	default:
 		return ""
 	}
@@ -12,6 +12,7 @@ func TestNetwork_String(t *testing.T) {
 
 	t.Run("test all networks", func(t *testing.T) {
 		assert.Equal(t, mainNet, MainNet.String())
+		assert.Equal(t, regTest, RegTestNet.String())
 		assert.Equal(t, stn, StressTestNet.String())
 		assert.Equal(t, testNet, TestNet.String())
 	})
@@ -28,6 +29,7 @@ func TestNetwork_Alternate(t *testing.T) {
 
 	t.Run("test all networks", func(t *testing.T) {
 		assert.Equal(t, mainNetAlt, MainNet.Alternate())
+		assert.Equal(t, regTest, RegTestNet.Alternate())
 		assert.Equal(t, stn, StressTestNet.Alternate())
 		assert.Equal(t, testNetAlt, TestNet.Alternate())
 	})
@@ -0,0 +1,284 @@
+package chainstate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/BuxOrg/bux/utils"
+	"github.com/bitcoin-sv/go-broadcast-client/broadcast"
+	"github.com/libsv/go-bc"
+	"github.com/libsv/go-p2p/chaincfg/chainhash"
+	"github.com/newrelic/go-agent/v3/newrelic"
+)
+
+// Node JSON-RPC methods
+const (
+	nodeMethodGetBlock           = "getblock"
+	nodeMethodGetNetworkInfo     = "getnetworkinfo"
+	nodeMethodGetRawTransaction  = "getrawtransaction"
+	nodeMethodSendRawTransaction = "sendrawtransaction"
+)
+
+// nodeConfig is the configuration of a bitcoind-compatible node (see WithNode)
+type nodeConfig struct {
+	broadcastURL string // JSON-RPC endpoint for broadcasting
+	queryURL     string // JSON-RPC endpoint for querying transactions & the fee quote
+}
+
+// nodeRequest is a JSON-RPC request to the node
+type nodeRequest struct {
+	ID      string        `json:"id"`
+	JSONRPC string        `json:"jsonrpc"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+// nodeResponse is a JSON-RPC response of the node
+type nodeResponse struct {
+	Error  *nodeError      `json:"error"`
+	Result json.RawMessage `json:"result"`
+}
+
+// nodeError is a JSON-RPC error of the node (IE: -26: dust)
+type nodeError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error will return the error message of the node
+func (e *nodeError) Error() string {
+	return strconv.Itoa(e.Code) + ": " + e.Message
+}
+
+// nodeTransaction is the (verbose) result of getrawtransaction
+type nodeTransaction struct {
+	BlockHash     string `json:"blockhash"`
+	BlockHeight   int64  `json:"blockheight"`
+	Confirmations int64  `json:"confirmations"`
+	TxID          string `json:"txid"`
+}
+
+// nodeBlock is the result of getblock (verbosity 1)
+type nodeBlock struct {
+	Hash   string   `json:"hash"`
+	Height uint64   `json:"height"`
+	Tx     []string `json:"tx"`
+}
+
+// nodeNetworkInfo is the result of getnetworkinfo
+type nodeNetworkInfo struct {
+	RelayFee float64 `json:"relayfee"` // BSV per kB
+}
+
+// nodeInit will load the fee unit from the node (if fee quotes are enabled)
+func (c *Client) nodeInit(ctx context.Context) error {
+	if txn := newrelic.FromContext(ctx); txn != nil {
+		defer txn.StartSegment("start_node").End()
+	}
+
+	if c.isFeeQuotesEnabled() {
+		feeUnit, err := nodeFeeQuote(ctx, c)
+		if err != nil {
+			return err
+		}
+		c.options.logger.Info().Msgf("got fee quote from node: %s", feeUnit)
+		c.options.config.feeUnit = c.selectFeeUnit([]utils.FeeUnit{*feeUnit}, c.options.config.feeUnit)
+	}
+
+	return nil
+}
+
+// node provider
+type nodeBroadcastProvider struct {
+	txID, txHex string
+}
+
+func (provider nodeBroadcastProvider) getName() string {
+	return ProviderNode
+}
+
+func (provider nodeBroadcastProvider) broadcast(ctx context.Context, c *Client) (*broadcastResponse, error) {
+	return broadcastNode(ctx, c, provider.txID, provider.txHex)
+}
+
+// broadcastNode will broadcast a transaction to the node using sendrawtransaction
+//
+// The node only accepts raw transactions, a transaction in Extended Format is converted to raw
+func broadcastNode(ctx context.Context, client *Client, id, hex string) (*broadcastResponse, error) {
+	debugLog(client, id, "executing broadcast request using "+ProviderNode)
+
+	hex, err := toRawHex(hex)
+	if err != nil {
+		return nil, err
+	}
+
+	var txID string
+	if err = callNode(
+		ctx, client, client.options.config.node.broadcastURL, nodeMethodSendRawTransaction, []interface{}{hex}, &txID,
+	); err != nil {
+		debugLog(client, id, "error broadcast request using "+ProviderNode+" failed: "+err.Error())
+
+		// The node answered with an error (IE: -26: dust, -27: transaction already in the mempool)
+		var rpcErr *nodeError
+		if !errors.As(err, &rpcErr) {
+			return nil, err
+		}
+		response := &broadcastResponse{
+			code:    strconv.Itoa(rpcErr.Code),
+			message: rpcErr.Message,
+		}
+		if doesErrorContain(rpcErr.Message, broadcastSuccessErrors) {
+			response.txStatus = broadcast.SeenOnNetwork
+			return response, nil
+		}
+		response.txStatus = broadcast.Rejected
+		return response, err
+	}
+
+	response := &broadcastResponse{txStatus: broadcast.SeenOnNetwork}
+	if !strings.EqualFold(txID, id) {
+		return response, incorrectTxIDReturnedErr(txID, id)
+	}
+	return response, nil
+}
+
+// queryNode will query the transaction from the node using getrawtransaction (verbose)
+//
+// The BUMP of a confirmed transaction is built from the transaction ids of its block (getblock)
+//
+// NOTE: the node needs the transaction index (-txindex) to return the confirmed transactions
+func queryNode(ctx context.Context, client *Client, id string) (*TransactionInfo, error) {
+	client.DebugLog("executing request using " + ProviderNode)
+
+	var tx nodeTransaction
+	if err := callNode(
+		ctx, client, client.options.config.node.queryURL, nodeMethodGetRawTransaction, []interface{}{id, 1}, &tx,
+	); err != nil {
+		client.DebugLog("error executing request using " + ProviderNode + " failed: " + err.Error())
+		return nil, err
+	} else if !strings.EqualFold(tx.TxID, id) {
+		return nil, ErrTransactionIDMismatch
+	}
+
+	info := &TransactionInfo{
+		BlockHash:     tx.BlockHash,
+		BlockHeight:   tx.BlockHeight,
+		Confirmations: tx.Confirmations,
+		ID:            tx.TxID,
+		Provider:      ProviderNode,
+	}
+	if len(tx.BlockHash) > 0 {
+		var err error
+		if info.BUMP, err = nodeBUMP(ctx, client, tx.TxID, tx.BlockHash); err != nil {
+			client.DebugLog("error executing request using " + ProviderNode + " failed: " + err.Error())
+			return nil, err
+		}
+	}
+
+	return info, nil
+}
+
+// nodeBUMP will build the BUMP of the transaction from the transaction ids of the block
+func nodeBUMP(ctx context.Context, client *Client, id, blockHash string) (*bc.BUMP, error) {
+	var block nodeBlock
+	if err := callNode(
+		ctx, client, client.options.config.node.queryURL, nodeMethodGetBlock, []interface{}{blockHash, 1}, &block,
+	); err != nil {
+		return nil, err
+	}
+
+	index := -1
+	txIDs := make([]*chainhash.Hash, 0, len(block.Tx))
+	for i, txID := range block.Tx {
+		hash, err := chainhash.NewHashFromStr(txID)
+		if err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(txID, id) {
+			index = i
+		}
+		txIDs = append(txIDs, hash)
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("transaction %s not found in block %s", id, blockHash)
+	}
+
+	return bc.NewBUMPFromMerkleTreeAndIndex(block.Height, bc.BuildMerkleTreeStoreChainHash(txIDs), uint64(index))
+}
+
+// nodeFeeQuote will return the relay fee of the node as a fee unit
+func nodeFeeQuote(ctx context.Context, client *Client) (*utils.FeeUnit, error) {
+	var info nodeNetworkInfo
+	if err := callNode(
+		ctx, client, client.options.config.node.queryURL, nodeMethodGetNetworkInfo, []interface{}{}, &info,
+	); err != nil {
+		return nil, err
+	}
+
+	return &utils.FeeUnit{
+		Satoshis: int(math.Round(info.RelayFee * 1e8)),
+		Bytes:    1000,
+	}, nil
+}
+
+// callNode will call the JSON-RPC method of the node and decode the result
+//
+// A JSON-RPC error is returned as a *nodeError (the node answers those with a HTTP error status)
+func callNode(ctx context.Context, client *Client, url, method string, params []interface{},
+	result interface{},
+) error {
+	body, err := json.Marshal(&nodeRequest{
+		ID:      "bux",
+		JSONRPC: "1.0",
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body)); err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if req.URL.User != nil {
+		password, _ := req.URL.User.Password()
+		req.SetBasicAuth(req.URL.User.Username(), password)
+		req.URL.User = nil
+	}
+	if len(client.options.userAgent) > 0 {
+		req.Header.Set("User-Agent", client.options.userAgent)
+	}
+
+	httpClient := client.HTTPClient()
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	var res *http.Response
+	if res, err = httpClient.Do(req); err != nil {
+		return err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var response nodeResponse
+	if err = json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return fmt.Errorf("node returned status code %d: %w", res.StatusCode, err)
+	} else if response.Error != nil {
+		return response.Error
+	} else if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("node returned status code %d", res.StatusCode)
+	}
+
+	return json.Unmarshal(response.Result, result)
+}
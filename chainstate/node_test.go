@@ -0,0 +1,244 @@
+package chainstate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/BuxOrg/bux/utils"
+	"github.com/libsv/go-bc"
+	"github.com/libsv/go-p2p/chaincfg/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testNodePassword = "password"
+	testNodeUser     = "bux"
+)
+
+// testNode is a bitcoind-compatible JSON-RPC node (regtest)
+type testNode struct {
+	mu           sync.Mutex
+	blocks       map[string]*nodeBlock       // Blocks known by the node
+	sendError    *nodeError                  // Error returned by sendrawtransaction (if set)
+	transactions map[string]*nodeTransaction // Transactions known by the node
+}
+
+func (n *testNode) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if user, password, ok := req.BasicAuth(); !ok || user != testNodeUser || password != testNodePassword {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var request nodeRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var response nodeResponse
+	switch request.Method {
+	case nodeMethodGetBlock:
+		block, ok := n.blocks[request.Params[0].(string)]
+		if !ok {
+			response.Error = &nodeError{Code: -5, Message: "Block not found"}
+			break
+		}
+		response.Result, _ = json.Marshal(block)
+	case nodeMethodGetNetworkInfo:
+		response.Result, _ = json.Marshal(&nodeNetworkInfo{RelayFee: 0.0000005})
+	case nodeMethodSendRawTransaction:
+		if n.sendError != nil {
+			response.Error = n.sendError
+			break
+		}
+		response.Result, _ = json.Marshal(broadcastExample1TxID)
+	case nodeMethodGetRawTransaction:
+		tx, ok := n.transactions[request.Params[0].(string)]
+		if !ok {
+			response.Error = &nodeError{Code: -5, Message: "No such mempool or blockchain transaction"}
+			break
+		}
+		response.Result, _ = json.Marshal(tx)
+	default:
+		response.Error = &nodeError{Code: -32601, Message: "Method not found"}
+	}
+
+	if response.Error != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	_ = json.NewEncoder(w).Encode(&response)
+}
+
+// newTestNode will start a test node, the URL contains the credentials
+func newTestNode(t *testing.T, node *testNode) string {
+	server := httptest.NewServer(node)
+	t.Cleanup(server.Close)
+	return strings.Replace(server.URL, "http://", "http://"+testNodeUser+":"+testNodePassword+"@", 1)
+}
+
+// TestClient_Node will test the node provider (regtest)
+func TestClient_Node(t *testing.T) {
+	t.Parallel()
+
+	blockTxIDs := []string{broadcastExample1TxID, onChainExample1TxID, notFoundExample1TxID}
+	node := &testNode{blocks: map[string]*nodeBlock{
+		onChainExample1BlockHash: {
+			Hash:   onChainExample1BlockHash,
+			Height: uint64(onChainExample1BlockHeight),
+			Tx:     blockTxIDs,
+		},
+	}, transactions: map[string]*nodeTransaction{
+		broadcastExample1TxID: {TxID: broadcastExample1TxID},
+		onChainExample1TxID: {
+			BlockHash:     onChainExample1BlockHash,
+			BlockHeight:   onChainExample1BlockHeight,
+			Confirmations: onChainExample1Confirmations,
+			TxID:          onChainExample1TxID,
+		},
+	}}
+	c := NewTestClient(
+		context.Background(), t,
+		WithNetwork(RegTestNet),
+		WithNode(newTestNode(t, node), ""),
+	)
+
+	t.Run("active provider and fee quote", func(t *testing.T) {
+		assert.Equal(t, RegTestNet, c.Network())
+		assert.Equal(t, ProviderNode, c.(*Client).ActiveProvider())
+		assert.Equal(t, &utils.FeeUnit{Satoshis: 50, Bytes: 1000}, c.FeeUnit())
+	})
+
+	t.Run("broadcast", func(t *testing.T) {
+		provider, err := c.Broadcast(
+			context.Background(), broadcastExample1TxID, broadcastExample1TxHex, defaultBroadcastTimeOut,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, ProviderNode, provider)
+	})
+
+	t.Run("broadcast - already in mempool", func(t *testing.T) {
+		node.mu.Lock()
+		node.sendError = &nodeError{Code: -27, Message: "Transaction already in the mempool"}
+		node.mu.Unlock()
+
+		provider, err := c.Broadcast(
+			context.Background(), broadcastExample1TxID, broadcastExample1TxHex, defaultBroadcastTimeOut,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, ProviderNode, provider)
+	})
+
+	t.Run("broadcast - rejected", func(t *testing.T) {
+		node.mu.Lock()
+		node.sendError = &nodeError{Code: -26, Message: "dust"}
+		node.mu.Unlock()
+
+		_, err := c.Broadcast(
+			context.Background(), broadcastExample1TxID, broadcastExample1TxHex, defaultBroadcastTimeOut,
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "-26: dust")
+	})
+
+	t.Run("query - in mempool", func(t *testing.T) {
+		info, err := c.QueryTransaction(
+			context.Background(), broadcastExample1TxID, RequiredInMempool, defaultQueryTimeOut,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, broadcastExample1TxID, info.ID)
+		assert.Equal(t, ProviderNode, info.Provider)
+
+		_, err = c.QueryTransaction(
+			context.Background(), broadcastExample1TxID, RequiredOnChain, defaultQueryTimeOut,
+		)
+		require.ErrorIs(t, err, ErrTransactionNotFound)
+	})
+
+	t.Run("query - on-chain", func(t *testing.T) {
+		info, err := c.QueryTransactionFastest(
+			context.Background(), onChainExample1TxID, RequiredOnChain, defaultQueryTimeOut,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, onChainExample1BlockHash, info.BlockHash)
+		assert.Equal(t, onChainExample1BlockHeight, info.BlockHeight)
+		assert.Equal(t, onChainExample1Confirmations, info.Confirmations)
+		assert.True(t, info.Valid())
+
+		// the BUMP is built from the transactions of the block
+		hashes := make([]*chainhash.Hash, 0, len(blockTxIDs))
+		for _, txID := range blockTxIDs {
+			hash, err := chainhash.NewHashFromStr(txID)
+			require.NoError(t, err)
+			hashes = append(hashes, hash)
+		}
+		merkles := bc.BuildMerkleTreeStoreChainHash(hashes)
+		require.NotNil(t, info.BUMP)
+		assert.Equal(t, uint64(onChainExample1BlockHeight), info.BUMP.BlockHeight)
+		root, err := info.BUMP.CalculateRootGivenTxid(onChainExample1TxID)
+		require.NoError(t, err)
+		assert.Equal(t, merkles[len(merkles)-1].String(), root)
+	})
+
+	t.Run("query - block not found", func(t *testing.T) {
+		node.mu.Lock()
+		node.transactions[notFoundExample1TxID] = &nodeTransaction{
+			BlockHash: "0000000000000000000000000000000000000000000000000000000000000001",
+			TxID:      notFoundExample1TxID,
+		}
+		node.mu.Unlock()
+		defer func() {
+			node.mu.Lock()
+			delete(node.transactions, notFoundExample1TxID)
+			node.mu.Unlock()
+		}()
+
+		_, err := c.QueryTransaction(
+			context.Background(), notFoundExample1TxID, RequiredOnChain, defaultQueryTimeOut,
+		)
+		require.ErrorIs(t, err, ErrTransactionNotFound)
+	})
+
+	t.Run("query - unknown transaction", func(t *testing.T) {
+		_, err := c.QueryTransaction(
+			context.Background(), notFoundExample1TxID, RequiredInMempool, defaultQueryTimeOut,
+		)
+		require.ErrorIs(t, err, ErrTransactionNotFound)
+	})
+}
+
+// TestWithNode will test the method WithNode()
+func TestWithNode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing broadcast endpoint", func(t *testing.T) {
+		options := defaultClientOptions()
+		WithNode("", "http://localhost:18332")(options)
+		assert.Nil(t, options.config.node)
+	})
+
+	t.Run("query endpoint defaults to the broadcast endpoint", func(t *testing.T) {
+		options := defaultClientOptions()
+		WithNode("http://localhost:18332", "")(options)
+		require.NotNil(t, options.config.node)
+		assert.Equal(t, "http://localhost:18332", options.config.node.queryURL)
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		url := newTestNode(t, &testNode{})
+		_, err := NewClient(
+			context.Background(),
+			WithNetwork(RegTestNet),
+			WithNode(strings.Replace(url, testNodePassword, "wrong", 1), ""),
+		)
+		require.Error(t, err)
+	})
+}
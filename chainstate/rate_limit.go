@@ -0,0 +1,21 @@
+package chainstate
+
+import (
+	"context"
+	"strings"
+
+	"golang.org/x/time/rate"
+)
+
+// providerRateLimits are the token buckets of the providers, keyed by the lowercase provider name
+//
+// The providers without a rate limit are not limited
+type providerRateLimits map[string]*rate.Limiter
+
+// wait will wait for a token of the provider (returns an error if the context is done first)
+func (l providerRateLimits) wait(ctx context.Context, provider string) error {
+	if limiter := l[strings.ToLower(provider)]; limiter != nil {
+		return limiter.Wait(ctx)
+	}
+	return nil
+}
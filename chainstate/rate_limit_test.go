@@ -0,0 +1,64 @@
+package chainstate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithProviderRateLimit will test the method WithProviderRateLimit()
+func TestWithProviderRateLimit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid rate limits are ignored", func(t *testing.T) {
+		options := defaultClientOptions()
+		WithProviderRateLimit("", 1, 1)(options)
+		WithProviderRateLimit(ProviderNode, 0, 1)(options)
+		assert.Empty(t, options.config.rateLimits)
+	})
+
+	t.Run("token bucket per provider", func(t *testing.T) {
+		options := defaultClientOptions()
+		WithProviderRateLimit("Node", 0.001, 0)(options)
+		limits := options.config.rateLimits
+
+		// the first token of the bucket (burst of 1)
+		require.NoError(t, limits.wait(context.Background(), ProviderNode))
+
+		// the bucket is empty
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		require.Error(t, limits.wait(ctx, ProviderNode))
+
+		// other providers are not limited
+		require.NoError(t, limits.wait(ctx, ProviderBroadcastClient))
+	})
+}
+
+// TestClient_Broadcast_RateLimit will test the method Broadcast() with a rate limited provider
+func TestClient_Broadcast_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	node := &testNode{transactions: map[string]*nodeTransaction{}}
+	c := NewTestClient(
+		context.Background(), t,
+		WithNetwork(RegTestNet),
+		WithNode(newTestNode(t, node), ""),
+		WithProviderRateLimit(ProviderNode, 0.001, 1),
+	)
+
+	provider, err := c.Broadcast(
+		context.Background(), broadcastExample1TxID, broadcastExample1TxHex, defaultBroadcastTimeOut,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, ProviderNode, provider)
+
+	_, err = c.Broadcast(
+		context.Background(), broadcastExample1TxID, broadcastExample1TxHex, 100*time.Millisecond,
+	)
+	require.Error(t, err)
+	assert.Equal(t, ErrorClassTimeout, ClassifyBroadcastError(err))
+}
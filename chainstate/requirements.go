@@ -36,3 +36,8 @@ func checkRequirementMapi(requirement RequiredIn, id string, txInfo *Transaction
 	isConfirmedOnChain := len(txInfo.BlockHash) > 0 && txInfo.Confirmations > 0
 	return checkRequirement(requirement, id, txInfo, isConfirmedOnChain)
 }
+
+func checkRequirementNode(requirement RequiredIn, id string, txInfo *TransactionInfo) bool {
+	isConfirmedOnChain := len(txInfo.BlockHash) > 0 && txInfo.Confirmations > 0
+	return checkRequirement(requirement, id, txInfo, isConfirmedOnChain)
+}
@@ -21,21 +21,35 @@ func (c *Client) query(ctx context.Context, id string, requiredIn RequiredIn,
 	switch c.ActiveProvider() {
 	case ProviderMinercraft:
 		for index := range c.options.config.minercraftConfig.queryMiners {
-			if c.options.config.minercraftConfig.queryMiners[index] != nil {
+			if miner := c.options.config.minercraftConfig.queryMiners[index]; miner != nil &&
+				c.options.config.rateLimits.wait(ctxWithCancel, miner.Name) == nil {
 				if res, err := queryMinercraft(
-					ctxWithCancel, c, c.options.config.minercraftConfig.queryMiners[index], id,
+					ctxWithCancel, c, miner, id,
 				); err == nil && checkRequirementMapi(requiredIn, id, res) {
 					return res
 				}
 			}
 		}
 	case ProviderBroadcastClient:
+		if c.options.config.rateLimits.wait(ctxWithCancel, ProviderBroadcastClient) != nil {
+			break
+		}
 		resp, err := queryBroadcastClient(
 			ctxWithCancel, c, id,
 		)
 		if err == nil && checkRequirementArc(requiredIn, id, resp) {
 			return resp
 		}
+	case ProviderNode:
+		if c.options.config.rateLimits.wait(ctxWithCancel, ProviderNode) != nil {
+			break
+		}
+		resp, err := queryNode(
+			ctxWithCancel, c, id,
+		)
+		if err == nil && checkRequirementNode(requiredIn, id, resp) {
+			return resp
+		}
 	default:
 		c.options.logger.Warn().Msg("no active provider for query")
 	}
@@ -69,6 +83,9 @@ func (c *Client) fastestQuery(ctx context.Context, id string, requiredIn Require
 				id string, requiredIn RequiredIn,
 			) {
 				defer wg.Done()
+				if client.options.config.rateLimits.wait(ctx, miner.Name) != nil {
+					return
+				}
 				if res, err := queryMinercraft(
 					ctx, client, miner, id,
 				); err == nil && checkRequirementMapi(requiredIn, id, res) {
@@ -80,12 +97,28 @@ func (c *Client) fastestQuery(ctx context.Context, id string, requiredIn Require
 		wg.Add(1)
 		go func(ctx context.Context, client *Client, id string, requiredIn RequiredIn) {
 			defer wg.Done()
+			if client.options.config.rateLimits.wait(ctx, ProviderBroadcastClient) != nil {
+				return
+			}
 			if resp, err := queryBroadcastClient(
 				ctx, client, id,
 			); err == nil && checkRequirementArc(requiredIn, id, resp) {
 				resultsChannel <- resp
 			}
 		}(ctxWithCancel, c, id, requiredIn)
+	case ProviderNode:
+		wg.Add(1)
+		go func(ctx context.Context, client *Client, id string, requiredIn RequiredIn) {
+			defer wg.Done()
+			if client.options.config.rateLimits.wait(ctx, ProviderNode) != nil {
+				return
+			}
+			if resp, err := queryNode(
+				ctx, client, id,
+			); err == nil && checkRequirementNode(requiredIn, id, resp) {
+				resultsChannel <- resp
+			}
+		}(ctxWithCancel, c, id, requiredIn)
 	default:
 		c.options.logger.Warn().Msg("no active provider for fastestQuery")
 	}
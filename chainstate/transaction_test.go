@@ -44,6 +44,32 @@ func TestClient_Transaction(t *testing.T) {
 		require.Nil(t, info)
 		assert.ErrorIs(t, err, ErrInvalidRequirements)
 	})
+
+	t.Run("error - SPV only", func(t *testing.T) {
+		// given
+		c := NewTestClient(context.Background(), t, WithMinercraft(&minerCraftTxOnChain{}), WithSPVOnly())
+
+		// when
+		info, err := c.QueryTransaction(
+			context.Background(), onChainExample1TxID,
+			RequiredOnChain, defaultQueryTimeOut,
+		)
+
+		// then
+		require.Error(t, err)
+		require.Nil(t, info)
+		assert.ErrorIs(t, err, ErrTransactionLookupDisabled)
+
+		// when
+		info, err = c.QueryTransactionFastest(
+			context.Background(), onChainExample1TxID,
+			RequiredOnChain, defaultQueryTimeOut,
+		)
+
+		// then
+		require.Nil(t, info)
+		assert.ErrorIs(t, err, ErrTransactionLookupDisabled)
+	})
 }
 
 func TestClient_Transaction_MAPI(t *testing.T) {
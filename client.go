@@ -27,22 +27,25 @@ type (
 
 	// clientOptions holds all the configuration for the client
 	clientOptions struct {
-		cacheStore    *cacheStoreOptions    // Configuration options for Cachestore (ristretto, redis, etc.)
-		cluster       *clusterOptions       // Configuration options for the cluster coordinator
-		chainstate    *chainstateOptions    // Configuration options for Chainstate (broadcast, sync, etc.)
-		dataStore     *dataStoreOptions     // Configuration options for the DataStore (MySQL, etc.)
-		debug         bool                  // If the client is in debug mode
-		encryptionKey string                // Encryption key for encrypting sensitive information (IE: paymail xPub) (hex encoded key)
-		httpClient    HTTPInterface         // HTTP interface to use
-		iuc           bool                  // (Input UTXO Check) True will check input utxos when saving transactions
-		logger        *zerolog.Logger       // Internal logging
-		metrics       *metrics.Metrics      // Metrics with a collector interface
-		models        *modelOptions         // Configuration options for the loaded models
-		newRelic      *newRelicOptions      // Configuration options for NewRelic
-		notifications *notificationsOptions // Configuration options for Notifications
-		paymail       *paymailOptions       // Paymail options & client
-		taskManager   *taskManagerOptions   // Configuration options for the TaskManager (TaskQ, etc.)
-		userAgent     string                // User agent for all outgoing requests
+		cacheStore              *cacheStoreOptions    // Configuration options for Cachestore (ristretto, redis, etc.)
+		cluster                 *clusterOptions       // Configuration options for the cluster coordinator
+		chainstate              *chainstateOptions    // Configuration options for Chainstate (broadcast, sync, etc.)
+		dataStore               *dataStoreOptions     // Configuration options for the DataStore (MySQL, etc.)
+		debug                   bool                  // If the client is in debug mode
+		encryptionKey           string                // Encryption key for encrypting sensitive information (IE: paymail xPub) (hex encoded key)
+		httpClient              HTTPInterface         // HTTP interface to use
+		iuc                     bool                  // (Input UTXO Check) True will check input utxos when saving transactions
+		logger                  *zerolog.Logger       // Internal logging
+		maxUnconfirmedAncestors uint32                // Max depth of unconfirmed ancestors for a utxo to be selected as an input (0 = no limit)
+		merkleProofsMigration   bool                  // If the legacy merkle proofs are migrated to BUMP (cron job)
+		metrics                 *metrics.Metrics      // Metrics with a collector interface
+		models                  *modelOptions         // Configuration options for the loaded models
+		monitor                 *monitorOptions       // Configuration options for the transaction monitor
+		newRelic                *newRelicOptions      // Configuration options for NewRelic
+		notifications           *notificationsOptions // Configuration options for Notifications
+		paymail                 *paymailOptions       // Paymail options & client
+		taskManager             *taskManagerOptions   // Configuration options for the TaskManager (TaskQ, etc.)
+		userAgent               string                // User agent for all outgoing requests
 	}
 
 	// chainstateOptions holds the chainstate configuration and client
@@ -53,6 +56,16 @@ type (
 		broadcastInstant           bool                   // Default value for all transactions
 		paymailP2P                 bool                   // Default value for all transactions
 		syncOnChain                bool                   // Default value for all transactions
+		localHeaders               bool                   // If the local header store verifies the merkle roots
+		headerSource               BlockHeaderSource      // Source for extending the local header store
+		spvOnly                    bool                   // If set, transactions are never queried from the providers
+		callbackToken              string                 // Token of the broadcast callbacks
+		feeQuotesRefresh           time.Duration          // Interval of the fee quotes refresh (0: disabled)
+		feePriority                chainstate.FeePriority // Default fee priority of the transactions (empty: the fee unit)
+		addressHistory             AddressHistoryProvider // Provider of the past transactions of the addresses (xPub rescan)
+		broadcastAttempts          bool                   // If each broadcast attempt to a provider is recorded
+		retryPolicy                *SyncRetryPolicy       // Retry policy of the failed sync transactions
+		syncWorkers                int                    // Number of sync transactions processed at the same time
 	}
 
 	// cacheStoreOptions holds the cache configuration and client
@@ -166,6 +179,11 @@ func NewClient(ctx context.Context, opts ...ClientOps) (ClientInterface, error)
 		return nil, err
 	}
 
+	// Apply the fee unit updates of the other nodes (if the fee quotes refresh is enabled)
+	if err = client.subscribeFeeUnitUpdates(); err != nil {
+		return nil, err
+	}
+
 	// Load the Paymail client (if client does not exist)
 	if err = client.loadPaymailClient(); err != nil {
 		return nil, err
@@ -186,6 +204,11 @@ func NewClient(ctx context.Context, opts ...ClientOps) (ClientInterface, error)
 		return nil, err
 	}
 
+	// Start the transaction monitor (if a source is set)
+	if err = client.loadMonitor(ctx); err != nil {
+		return nil, err
+	}
+
 	// Default paymail server config (generic capabilities and domain check disabled)
 	if client.options.paymail.serverConfig.Configuration == nil {
 		if err = client.loadDefaultPaymailConfig(); err != nil {
@@ -258,6 +281,9 @@ func (c *Client) Close(ctx context.Context) error {
 		defer txn.StartSegment("close_all").End()
 	}
 
+	// Stop the monitor (before its dependencies are closed)
+	c.stopMonitor()
+
 	// Close Chainstate
 	ch := c.Chainstate()
 	if ch != nil {
@@ -378,11 +404,31 @@ func (c *Client) IsEncryptionKeySet() bool {
 	return len(c.options.encryptionKey) > 0
 }
 
+// SyncRetryPolicy will return the retry policy of the failed sync transactions
+func (c *Client) SyncRetryPolicy() *SyncRetryPolicy {
+	return c.options.chainstate.retryPolicy
+}
+
+// SyncWorkers will return the number of sync transactions processed at the same time
+func (c *Client) SyncWorkers() int {
+	return c.options.chainstate.syncWorkers
+}
+
+// IsSPVOnlyEnabled will return the flag (bool) if the transaction lookups are disabled
+func (c *Client) IsSPVOnlyEnabled() bool {
+	return c.options.chainstate.spvOnly
+}
+
 // IsMigrationEnabled will return the flag (bool)
 func (c *Client) IsMigrationEnabled() bool {
 	return !c.options.dataStore.migrationDisabled
 }
 
+// MaxUnconfirmedAncestors will return the max depth of unconfirmed ancestors for selecting utxos (0 = no limit)
+func (c *Client) MaxUnconfirmedAncestors() uint32 {
+	return c.options.maxUnconfirmedAncestors
+}
+
 // Logger will return the Logger if it exists
 func (c *Client) Logger() *zerolog.Logger {
 	return c.options.logger
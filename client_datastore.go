@@ -8,7 +8,11 @@ import (
 )
 
 const (
-	conditionAnd = "$and"
+	conditionAnd                = "$and"
+	conditionGreaterThan        = "$gt"
+	conditionGreaterThanOrEqual = "$gte"
+	conditionLessThanOrEqual    = "$lte"
+	conditionOr                 = "$or"
 )
 
 // processCustomFields will process all custom fields
@@ -24,6 +28,12 @@ func processCustomFields(conditions *map[string]interface{}) {
 	if ok {
 		processXpubMetadataConditions(conditions)
 	}
+
+	// Process the metadata_path
+	_, ok = (*conditions)[metadataPathField]
+	if ok {
+		processMetadataPathConditions(conditions)
+	}
 }
 
 // processXpubOutputValueConditions will process xpub_output_value
@@ -83,26 +93,102 @@ func processXpubMetadataConditions(conditions *map[string]interface{}) {
 	delete(*conditions, "xpub_metadata")
 }
 
+// processMetadataPathConditions will process metadata_path (key => subKey => value, see MetadataMatch())
+//
+// The key and the sub key are matched on the same key/value pair using $elemMatch, the first path is set
+// on the metadata field (a metadata field is not processed again after the custom fields)
+func processMetadataPathConditions(conditions *map[string]interface{}) {
+	// marshal / unmarshal into standard map[string]interface{}
+	m, _ := json.Marshal((*conditions)[metadataPathField]) //nolint:errchkjson // this check might break the current code
+	var r map[string]interface{}
+	_ = json.Unmarshal(m, &r)
+
+	for key, kr := range r {
+		subKeys, ok := kr.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for subKey, value := range subKeys {
+			elemMatch := map[string]interface{}{
+				"$elemMatch": map[string]interface{}{"k": key, "v." + subKey: value},
+			}
+			if _, ok = (*conditions)[metadataField]; !ok {
+				(*conditions)[metadataField] = elemMatch
+				continue
+			}
+			appendAndConditions(*conditions, map[string]interface{}{
+				metadataPathField: map[string]interface{}{key: map[string]interface{}{subKey: value}},
+			})
+		}
+	}
+	delete(*conditions, metadataPathField)
+}
+
 // getMongoIndexes will get indexes from mongo
 func getMongoIndexes() map[string][]mongo.IndexModel {
 
 	return map[string][]mongo.IndexModel{
+		"block_bumps": {
+			mongo.IndexModel{Keys: bsonx.Doc{{
+				Key:   "block_height",
+				Value: bsonx.Int32(1),
+			}}},
+		},
 		"block_headers": {
 			mongo.IndexModel{Keys: bsonx.Doc{{
 				Key:   "height",
 				Value: bsonx.Int32(1),
 			}}},
 			mongo.IndexModel{Keys: bsonx.Doc{{
-				Key:   "synced",
+				Key:   "merkle_root",
+				Value: bsonx.Int32(1),
+			}}},
+			mongo.IndexModel{Keys: bsonx.Doc{{
+				Key:   "longest_chain",
+				Value: bsonx.Int32(1),
+			}}},
+		},
+		"broadcast_attempts": {
+			mongo.IndexModel{Keys: bsonx.Doc{{
+				Key:   "tx_id",
+				Value: bsonx.Int32(1),
+			}, {
+				Key:   "started_at",
+				Value: bsonx.Int32(1),
+			}}},
+		},
+		"broadcast_callbacks": {
+			mongo.IndexModel{Keys: bsonx.Doc{{
+				Key:   "tx_id",
+				Value: bsonx.Int32(1),
+			}}},
+			mongo.IndexModel{Keys: bsonx.Doc{{
+				Key:   "reason",
 				Value: bsonx.Int32(1),
 			}}},
 		},
 		"destinations": {
+			mongo.IndexModel{Keys: bsonx.Doc{{
+				Key:   "created_at",
+				Value: bsonx.Int32(1),
+			}, {
+				Key:   "_id",
+				Value: bsonx.Int32(1),
+			}}},
 			mongo.IndexModel{Keys: bsonx.Doc{{
 				Key:   "address",
 				Value: bsonx.Int32(1),
 			}}},
 		},
+		"access_keys": {
+			mongo.IndexModel{Keys: bsonx.Doc{{
+				Key:   "created_at",
+				Value: bsonx.Int32(1),
+			}, {
+				Key:   "_id",
+				Value: bsonx.Int32(1),
+			}}},
+		},
 		"draft_transactions": {
 			mongo.IndexModel{Keys: bsonx.Doc{{
 				Key:   "status",
@@ -116,7 +202,29 @@ func getMongoIndexes() map[string][]mongo.IndexModel {
 				Value: bsonx.Int32(1),
 			}}},
 		},
+		"fee_history": {
+			mongo.IndexModel{Keys: bsonx.Doc{{
+				Key:   "created_at",
+				Value: bsonx.Int32(1),
+			}}},
+		},
+		"paymail_addresses": {
+			mongo.IndexModel{Keys: bsonx.Doc{{
+				Key:   "created_at",
+				Value: bsonx.Int32(1),
+			}, {
+				Key:   "_id",
+				Value: bsonx.Int32(1),
+			}}},
+		},
 		"transactions": {
+			mongo.IndexModel{Keys: bsonx.Doc{{
+				Key:   "created_at",
+				Value: bsonx.Int32(1),
+			}, {
+				Key:   "_id",
+				Value: bsonx.Int32(1),
+			}}},
 			mongo.IndexModel{Keys: bsonx.Doc{{
 				Key:   "xpub_metadata.x",
 				Value: bsonx.Int32(1),
@@ -135,8 +243,19 @@ func getMongoIndexes() map[string][]mongo.IndexModel {
 				Key:   "xpub_out_ids",
 				Value: bsonx.Int32(1),
 			}}},
+			mongo.IndexModel{Keys: bsonx.Doc{{
+				Key:   "block_bump_id",
+				Value: bsonx.Int32(1),
+			}}},
 		},
 		"utxos": {
+			mongo.IndexModel{Keys: bsonx.Doc{{
+				Key:   "created_at",
+				Value: bsonx.Int32(1),
+			}, {
+				Key:   "_id",
+				Value: bsonx.Int32(1),
+			}}},
 			mongo.IndexModel{Keys: bsonx.Doc{{
 				Key:   "transaction_id",
 				Value: bsonx.Int32(1),
@@ -158,5 +277,14 @@ func getMongoIndexes() map[string][]mongo.IndexModel {
 				Value: bsonx.Int32(1),
 			}}},
 		},
+		"xpubs": {
+			mongo.IndexModel{Keys: bsonx.Doc{{
+				Key:   "created_at",
+				Value: bsonx.Int32(1),
+			}, {
+				Key:   "_id",
+				Value: bsonx.Int32(1),
+			}}},
+		},
 	}
 }
@@ -39,6 +39,12 @@ func (c *Client) loadChainstate(ctx context.Context) (err error) {
 		c.options.chainstate.options = append(c.options.chainstate.options, chainstate.WithUserAgent(c.UserAgent()))
 		c.options.chainstate.options = append(c.options.chainstate.options, chainstate.WithHTTPClient(c.HTTPClient()))
 		c.options.chainstate.options = append(c.options.chainstate.options, chainstate.WithMetrics(c.options.metrics))
+		if c.options.chainstate.localHeaders {
+			c.options.chainstate.options = append(c.options.chainstate.options, chainstate.WithHeaderService(newBlockHeaderStore(c)))
+		}
+		if c.options.chainstate.broadcastAttempts {
+			c.options.chainstate.options = append(c.options.chainstate.options, chainstate.WithBroadcastAttemptRecorder(newBroadcastAttemptRecorder(c)))
+		}
 		c.options.chainstate.ClientInterface, err = chainstate.NewClient(ctx, c.options.chainstate.options...)
 	}
 
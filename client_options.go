@@ -53,6 +53,8 @@ func defaultClientOptions() *clientOptions {
 			broadcastInstant: true, // Enabled by default for new users
 			paymailP2P:       true, // Enabled by default for new users
 			syncOnChain:      true, // Enabled by default for new users
+			retryPolicy:      DefaultSyncRetryPolicy(),
+			syncWorkers:      1,
 		},
 
 		cluster: &clusterOptions{
@@ -84,6 +86,11 @@ func defaultClientOptions() *clientOptions {
 			migrateModels:     nil,
 		},
 
+		// Blank monitor config (disabled until a source is set)
+		monitor: &monitorOptions{
+			filters: make(map[string]MonitorFilter),
+		},
+
 		// Blank NewRelic config
 		newRelic: &newRelicOptions{},
 
@@ -244,6 +251,24 @@ func WithIUCDisabled() ClientOps {
 	}
 }
 
+// WithMaxUnconfirmedAncestors will set the maximum depth of unconfirmed ancestors
+// a utxo can have to be selected as an input for a new transaction (0 = no limit, the default)
+//
+// IE: 1000 is the default limit of the miners (ancestors in the mempool)
+func WithMaxUnconfirmedAncestors(limit uint32) ClientOps {
+	return func(c *clientOptions) {
+		c.maxUnconfirmedAncestors = limit
+	}
+}
+
+// WithLegacyMerkleProofsMigration will run a cron job backfilling the BUMP of the mined transactions
+// which only have a legacy TSC merkle proof (or neither), see MigrateLegacyMerkleProofs
+func WithLegacyMerkleProofsMigration() ClientOps {
+	return func(c *clientOptions) {
+		c.merkleProofsMigration = true
+	}
+}
+
 // WithHTTPClient will set the custom http interface
 func WithHTTPClient(httpClient HTTPInterface) ClientOps {
 	return func(c *clientOptions) {
@@ -602,6 +627,60 @@ func WithChainstateOptions(broadcasting, broadcastInstant, paymailP2P, syncOnCha
 	}
 }
 
+// WithLocalHeaderStore will verify the merkle roots with the local header store instead of Pulse
+//
+// The headers are imported with ImportBlockHeaders and extended from the source (optional) with SyncBlockHeaders
+func WithLocalHeaderStore(source BlockHeaderSource) ClientOps {
+	return func(c *clientOptions) {
+		c.chainstate.localHeaders = true
+		c.chainstate.headerSource = source
+
+		// Add the block_header model in bux
+		c.addModels(migrateList, &BlockHeader{Model: *NewBaseModel(ModelBlockHeader)})
+	}
+}
+
+// WithAddressHistoryProvider will set the provider of the past transactions of the addresses (see RescanXpub)
+func WithAddressHistoryProvider(provider AddressHistoryProvider) ClientOps {
+	return func(c *clientOptions) {
+		if provider != nil {
+			c.chainstate.addressHistory = provider
+		}
+	}
+}
+
+// WithMonitor will start a monitor recording the transactions of the source which match the destinations of bux
+// (bloom filter, kept in sync across the cluster) or a registered filter (see WithMonitorFilter)
+func WithMonitor(source MonitorSource) ClientOps {
+	return func(c *clientOptions) {
+		if source != nil {
+			c.monitor.source = source
+		}
+	}
+}
+
+// WithMonitorFilter will register a filter of the monitor (IE: filters.Metanet or RegexMonitorFilter())
+//
+// The name is stored in the metadata of the recorded transactions (see MonitorFilterMetadataKey)
+func WithMonitorFilter(name string, filter MonitorFilter) ClientOps {
+	return func(c *clientOptions) {
+		if len(name) > 0 && name != chainstate.FilterBloom && filter != nil {
+			c.monitor.filters[name] = filter
+		}
+	}
+}
+
+// WithSPVOnly will disable the transaction lookups on the chainstate providers (SPV only mode)
+//
+// The on-chain statuses are only updated by the broadcast callbacks (UpdateTransaction), the BEEF ancestors
+// and the header service, transactions which cannot be synced otherwise are marked as unverifiable
+func WithSPVOnly() ClientOps {
+	return func(c *clientOptions) {
+		c.chainstate.spvOnly = true
+		c.chainstate.options = append(c.chainstate.options, chainstate.WithSPVOnly())
+	}
+}
+
 // WithExcludedProviders will set a list of excluded providers
 func WithExcludedProviders(providers []string) ClientOps {
 	return func(c *clientOptions) {
@@ -640,6 +719,45 @@ func WithFeeQuotes(enabled bool) ClientOps {
 	}
 }
 
+// WithFeeQuotesRefresh will refresh the fee unit from the fee quotes of the providers on the given interval
+//
+// The fee unit is selected using the policy, each refresh is recorded (see GetFeeHistory)
+// and the changes are published to the other nodes of the cluster
+func WithFeeQuotesRefresh(interval time.Duration, policy chainstate.FeeUnitPolicy) ClientOps {
+	return func(c *clientOptions) {
+		if interval <= 0 {
+			return
+		}
+		c.chainstate.feeQuotesRefresh = interval
+		c.chainstate.options = append(
+			c.chainstate.options,
+			chainstate.WithFeeQuotes(true),
+			chainstate.WithFeeUnitPolicy(policy),
+		)
+
+		// Add the fee_history model in bux
+		c.addModels(migrateList, &FeeHistory{Model: *NewBaseModel(ModelFeeHistory)})
+	}
+}
+
+// WithFeePriorities will set the multipliers of the fee priorities over the fee unit
+func WithFeePriorities(priorities chainstate.FeePriorities) ClientOps {
+	return func(c *clientOptions) {
+		if len(priorities) > 0 {
+			c.chainstate.options = append(c.chainstate.options, chainstate.WithFeePriorities(priorities))
+		}
+	}
+}
+
+// WithDefaultFeePriority will set the fee priority of the transactions without fee unit or fee priority
+//
+// The default can be overridden per xPub with the metadata key FeePriorityMetadataKey
+func WithDefaultFeePriority(priority chainstate.FeePriority) ClientOps {
+	return func(c *clientOptions) {
+		c.chainstate.feePriority = priority
+	}
+}
+
 // WithFeeUnit will set the fee unit to use for broadcasting
 func WithFeeUnit(feeUnit *utils.FeeUnit) ClientOps {
 	return func(c *clientOptions) {
@@ -677,9 +795,76 @@ func WithBroadcastClient(broadcastClient broadcast.Client) ClientOps {
 	}
 }
 
+// WithNetwork will set the network of the chainstate (IE: chainstate.RegTestNet)
+//
+// The addresses are derived using the version bytes of the xPubs (a tpub on testnet & regtest)
+func WithNetwork(network chainstate.Network) ClientOps {
+	return func(c *clientOptions) {
+		c.chainstate.options = append(c.chainstate.options, chainstate.WithNetwork(network))
+	}
+}
+
+// WithNode will set the JSON-RPC endpoints of a bitcoind-compatible node (IE: a local regtest node)
+//
+// The node is used for broadcasting, querying and the fee quotes (see chainstate.WithNode)
+func WithNode(broadcastURL, queryURL string) ClientOps {
+	return func(c *clientOptions) {
+		c.chainstate.options = append(c.chainstate.options, chainstate.WithNode(broadcastURL, queryURL))
+	}
+}
+
+// WithProviderRateLimit will limit the requests (broadcasts & queries) to a chainstate provider using a token bucket
+//
+// The provider is a miner name, chainstate.ProviderBroadcastClient or chainstate.ProviderNode
+func WithProviderRateLimit(provider string, requestsPerSecond float64, burst int) ClientOps {
+	return func(c *clientOptions) {
+		c.chainstate.options = append(
+			c.chainstate.options, chainstate.WithProviderRateLimit(provider, requestsPerSecond, burst),
+		)
+	}
+}
+
+// WithSyncWorkers will set the number of sync transactions processed at the same time (broadcast & sync)
+//
+// Default is 1 (one at a time), a single xPub is never processed concurrently
+func WithSyncWorkers(workers int) ClientOps {
+	return func(c *clientOptions) {
+		if workers > 0 {
+			c.chainstate.syncWorkers = workers
+		}
+	}
+}
+
+// WithBroadcastAttempts will record each broadcast attempt to a provider (see GetBroadcastAttempts)
+//
+// The attempts are recorded with the raw response of the provider and the resulting transaction status
+func WithBroadcastAttempts() ClientOps {
+	return func(c *clientOptions) {
+		c.chainstate.broadcastAttempts = true
+
+		// Add the broadcast_attempt model in bux
+		c.addModels(migrateList, &BroadcastAttempt{Model: *NewBaseModel(ModelBroadcastAttempt)})
+	}
+}
+
+// WithSyncRetryPolicy will set the retry policy of the failed sync transactions (default: DefaultSyncRetryPolicy)
+func WithSyncRetryPolicy(policy *SyncRetryPolicy) ClientOps {
+	return func(c *clientOptions) {
+		if policy != nil {
+			c.chainstate.retryPolicy = policy
+		}
+	}
+}
+
 // WithCallback set callback settings
+//
+// The callbacks are received by the BroadcastCallbackHandler, which validates the callback token
 func WithCallback(callbackURL string, callbackToken string) ClientOps {
 	return func(c *clientOptions) {
 		c.chainstate.options = append(c.chainstate.options, chainstate.WithCallback(callbackURL, callbackToken))
+		c.chainstate.callbackToken = callbackToken
+
+		// Add the broadcast_callback model in bux
+		c.addModels(migrateList, &BroadcastCallback{Model: *NewBaseModel(ModelBroadcastCallback)})
 	}
 }
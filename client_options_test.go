@@ -584,7 +584,7 @@ func TestWithModels(t *testing.T) {
 		assert.Equal(t, []string{
 			ModelXPub.String(), ModelAccessKey.String(),
 			ModelDraftTransaction.String(), ModelTransaction.String(),
-			ModelSyncTransaction.String(), ModelDestination.String(),
+			ModelBlockBUMP.String(), ModelSyncTransaction.String(), ModelDestination.String(),
 			ModelUtxo.String(),
 		}, tc.GetModelNames())
 	})
@@ -602,7 +602,7 @@ func TestWithModels(t *testing.T) {
 		assert.Equal(t, []string{
 			ModelXPub.String(), ModelAccessKey.String(),
 			ModelDraftTransaction.String(), ModelTransaction.String(),
-			ModelSyncTransaction.String(), ModelDestination.String(),
+			ModelBlockBUMP.String(), ModelSyncTransaction.String(), ModelDestination.String(),
 			ModelUtxo.String(), ModelPaymailAddress.String(),
 		}, tc.GetModelNames())
 	})
@@ -644,6 +644,77 @@ func TestWithIUCDisabled(t *testing.T) {
 	})
 }
 
+// TestWithMaxUnconfirmedAncestors will test the method WithMaxUnconfirmedAncestors()
+func TestWithMaxUnconfirmedAncestors(t *testing.T) {
+	t.Parallel()
+	testLogger := zerolog.Nop()
+
+	t.Run("check type", func(t *testing.T) {
+		opt := WithMaxUnconfirmedAncestors(0)
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("default options", func(t *testing.T) {
+		opts := DefaultClientOpts(false, true)
+		opts = append(opts, WithLogger(&testLogger))
+
+		tc, err := NewClient(tester.GetNewRelicCtx(t, defaultNewRelicApp, defaultNewRelicTx), opts...)
+		require.NoError(t, err)
+		require.NotNil(t, tc)
+		defer CloseClient(context.Background(), t, tc)
+
+		assert.Equal(t, uint32(0), tc.MaxUnconfirmedAncestors())
+	})
+
+	t.Run("custom limit", func(t *testing.T) {
+		opts := DefaultClientOpts(false, true)
+		opts = append(opts, WithMaxUnconfirmedAncestors(25))
+		opts = append(opts, WithLogger(&testLogger))
+
+		tc, err := NewClient(tester.GetNewRelicCtx(t, defaultNewRelicApp, defaultNewRelicTx), opts...)
+		require.NoError(t, err)
+		require.NotNil(t, tc)
+		defer CloseClient(context.Background(), t, tc)
+
+		assert.Equal(t, uint32(25), tc.MaxUnconfirmedAncestors())
+	})
+}
+
+// TestWithSyncWorkers will test the method WithSyncWorkers()
+func TestWithSyncWorkers(t *testing.T) {
+	t.Parallel()
+	testLogger := zerolog.Nop()
+
+	t.Run("check type", func(t *testing.T) {
+		opt := WithSyncWorkers(0)
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("default workers", func(t *testing.T) {
+		opts := DefaultClientOpts(false, true)
+		opts = append(opts, WithSyncWorkers(0), WithLogger(&testLogger))
+
+		tc, err := NewClient(tester.GetNewRelicCtx(t, defaultNewRelicApp, defaultNewRelicTx), opts...)
+		require.NoError(t, err)
+		require.NotNil(t, tc)
+		defer CloseClient(context.Background(), t, tc)
+
+		assert.Equal(t, 1, tc.SyncWorkers())
+	})
+
+	t.Run("custom workers", func(t *testing.T) {
+		opts := DefaultClientOpts(false, true)
+		opts = append(opts, WithSyncWorkers(16), WithLogger(&testLogger))
+
+		tc, err := NewClient(tester.GetNewRelicCtx(t, defaultNewRelicApp, defaultNewRelicTx), opts...)
+		require.NoError(t, err)
+		require.NotNil(t, tc)
+		defer CloseClient(context.Background(), t, tc)
+
+		assert.Equal(t, 16, tc.SyncWorkers())
+	})
+}
+
 // TestWithHTTPClient will test the method WithHTTPClient()
 func TestWithHTTPClient(t *testing.T) {
 	t.Parallel()
@@ -794,6 +865,7 @@ func TestWithAutoMigrate(t *testing.T) {
 			ModelAccessKey.String(),
 			ModelDraftTransaction.String(),
 			ModelTransaction.String(),
+			ModelBlockBUMP.String(),
 			ModelSyncTransaction.String(),
 			ModelDestination.String(),
 			ModelUtxo.String(),
@@ -815,6 +887,7 @@ func TestWithAutoMigrate(t *testing.T) {
 			ModelAccessKey.String(),
 			ModelDraftTransaction.String(),
 			ModelTransaction.String(),
+			ModelBlockBUMP.String(),
 			ModelSyncTransaction.String(),
 			ModelDestination.String(),
 			ModelUtxo.String(),
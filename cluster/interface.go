@@ -19,6 +19,9 @@ type Channel string
 var (
 	// DestinationNew is a message sent when a new destination is created
 	DestinationNew Channel = "new-destination"
+
+	// FeeUnitUpdated is a message sent when the fee unit is changed by a fee quotes refresh
+	FeeUnitUpdated Channel = "fee-unit-updated"
 )
 
 // ClientInterface interface for the internal pub/sub functionality for clusters
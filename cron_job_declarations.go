@@ -13,6 +13,9 @@ const (
 	CronJobNameSyncTransactionBroadcast = "sync_transaction_broadcast"
 	CronJobNameSyncTransactionSync      = "sync_transaction_sync"
 	CronJobNameCalculateMetrics         = "calculate_metrics"
+	CronJobNameSyncBlockHeaders         = "sync_block_headers"
+	CronJobNameMigrateMerkleProofs      = "migrate_merkle_proofs"
+	CronJobNameRefreshFeeQuotes         = "refresh_fee_quotes"
 )
 
 type cronJobHandler func(ctx context.Context, client *Client) error
@@ -65,5 +68,29 @@ func (c *Client) cronJobs() taskmanager.CronJobs {
 		)
 	}
 
+	if c.options.chainstate.localHeaders && c.options.chainstate.headerSource != nil {
+		addJob(
+			CronJobNameSyncBlockHeaders,
+			time.Minute,
+			taskSyncBlockHeaders,
+		)
+	}
+
+	if c.options.merkleProofsMigration {
+		addJob(
+			CronJobNameMigrateMerkleProofs,
+			time.Hour,
+			taskMigrateLegacyMerkleProofs,
+		)
+	}
+
+	if c.options.chainstate.feeQuotesRefresh > 0 {
+		addJob(
+			CronJobNameRefreshFeeQuotes,
+			c.options.chainstate.feeQuotesRefresh,
+			taskRefreshFeeQuotes,
+		)
+	}
+
 	return jobs
 }
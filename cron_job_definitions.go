@@ -86,6 +86,50 @@ func taskSyncTransactions(ctx context.Context, client *Client) error {
 	return err
 }
 
+// taskSyncBlockHeaders will extend the local header store with the headers of the block header source
+func taskSyncBlockHeaders(ctx context.Context, client *Client) error {
+	client.Logger().Info().Msg("running sync block headers task...")
+
+	synced, err := client.SyncBlockHeaders(ctx)
+	if synced > 0 {
+		client.Logger().Info().Uint64("headers", synced).Msg("synced block headers")
+	}
+	return err
+}
+
+// taskMigrateLegacyMerkleProofs will backfill the BUMP of the mined transactions without BUMP
+func taskMigrateLegacyMerkleProofs(ctx context.Context, client *Client) error {
+	client.Logger().Info().Msg("running migrate legacy merkle proofs task...")
+
+	result, err := client.MigrateLegacyMerkleProofs(ctx)
+	if result != nil && result.Converted+result.Moved+result.Queried+result.Failed > 0 {
+		client.Logger().Info().
+			Int("converted", result.Converted).
+			Int("moved", result.Moved).
+			Int("queried", result.Queried).
+			Int("failed", result.Failed).
+			Msg("migrated legacy merkle proofs")
+	}
+	return err
+}
+
+// taskRefreshFeeQuotes will refresh the fee unit from the fee quotes of the providers
+func taskRefreshFeeQuotes(ctx context.Context, client *Client) error {
+	client.Logger().Info().Msg("running refresh fee quotes task...")
+
+	refresh, err := client.RefreshFeeUnit(ctx)
+	if err != nil {
+		return err
+	}
+	if refresh.Changed {
+		client.Logger().Info().
+			Str("provider", refresh.Provider).
+			Str("feeUnit", refresh.FeeUnit.String()).
+			Msg("fee unit changed")
+	}
+	return nil
+}
+
 func taskCalculateMetrics(ctx context.Context, client *Client) error {
 	m, enabled := client.Metrics()
 	if !enabled {
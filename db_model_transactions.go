@@ -35,6 +35,15 @@ func (m *Transaction) Save(ctx context.Context) (err error) {
 		}
 	}
 
+	// Store the BUMP in the block BUMP, the transaction only references its leaf
+	if len(m.BUMP.Path) > 0 && m.Client() != nil && m.storeBlockBUMP(ctx) {
+		bump := m.BUMP
+		m.BUMP = BUMP{}
+		defer func() {
+			m.BUMP = bump
+		}()
+	}
+
 	return Save(ctx, m)
 }
 
@@ -115,11 +124,18 @@ func (m *Transaction) AfterCreated(ctx context.Context) error {
 }
 
 // AfterUpdated will fire after the model is updated in the Datastore
-func (m *Transaction) AfterUpdated(_ context.Context) error {
+func (m *Transaction) AfterUpdated(ctx context.Context) error {
 	m.Client().Logger().Debug().
 		Str("txID", m.ID).
 		Msgf("starting: %s AfterUpdated hook...", m.Name())
 
+	// The cached BEEFs containing the transaction without its BUMP are outdated once it's mined
+	if m.BlockBUMPID != "" || len(m.BUMP.Path) > 0 {
+		if err := invalidateTransactionBEEFs(ctx, m.Client(), m.ID); err != nil {
+			return err
+		}
+	}
+
 	// Fire notifications (this is already in a go routine)
 	notify(notifications.EventTypeUpdate, m)
 
@@ -14,8 +14,10 @@ const (
 	defaultDatabaseReadTimeout = 20 * time.Second  // For all "GET" or "SELECT" methods
 	defaultDraftTxExpiresIn    = 20 * time.Second  // Default TTL for draft transactions
 	defaultHTTPTimeout         = 20 * time.Second  // Default timeout for HTTP requests
+	defaultIterateBatchSize    = 500               // Default number of records read per batch by the Iterate* methods
 	defaultOverheadSize        = uint64(8)         // 8 bytes is the default overhead in a transaction = 4 bytes version + 4 bytes nLockTime
 	defaultQueryTxTimeout      = 10 * time.Second  // Default timeout for syncing on-chain information
+	defaultSyncClaimLease      = 2 * time.Minute   // Lease of a claimed sync transaction (picked again if the node dies)
 	defaultUserAgent           = "bux: " + version // Default user agent
 	dustLimit                  = uint64(1)         // Dust limit
 	mongoTestVersion           = "6.0.4"           // Mongo Testing Version
@@ -25,21 +27,27 @@ const (
 
 // All the base models
 const (
-	ModelAccessKey        ModelName = "access_key"
-	ModelDestination      ModelName = "destination"
-	ModelDraftTransaction ModelName = "draft_transaction"
-	ModelMetadata         ModelName = "metadata"
-	ModelNameEmpty        ModelName = "empty"
-	ModelPaymailAddress   ModelName = "paymail_address"
-	ModelSyncTransaction  ModelName = "sync_transaction"
-	ModelTransaction      ModelName = "transaction"
-	ModelUtxo             ModelName = "utxo"
-	ModelXPub             ModelName = "xpub"
+	ModelAccessKey         ModelName = "access_key"
+	ModelBlockBUMP         ModelName = "block_bump"
+	ModelBlockHeader       ModelName = "block_header"
+	ModelBroadcastAttempt  ModelName = "broadcast_attempt"
+	ModelBroadcastCallback ModelName = "broadcast_callback"
+	ModelDestination       ModelName = "destination"
+	ModelDraftTransaction  ModelName = "draft_transaction"
+	ModelFeeHistory        ModelName = "fee_history"
+	ModelMetadata          ModelName = "metadata"
+	ModelNameEmpty         ModelName = "empty"
+	ModelPaymailAddress    ModelName = "paymail_address"
+	ModelSyncTransaction   ModelName = "sync_transaction"
+	ModelTransaction       ModelName = "transaction"
+	ModelUtxo              ModelName = "utxo"
+	ModelXPub              ModelName = "xpub"
 )
 
 // AllModelNames is a list of all models
 var AllModelNames = []ModelName{
 	ModelAccessKey,
+	ModelBlockBUMP,
 	ModelDestination,
 	ModelMetadata,
 	ModelPaymailAddress,
@@ -52,22 +60,34 @@ var AllModelNames = []ModelName{
 
 // Internal table names
 const (
-	tableAccessKeys        = "access_keys"
-	tableDestinations      = "destinations"
-	tableDraftTransactions = "draft_transactions"
-	tablePaymailAddresses  = "paymail_addresses"
-	tableSyncTransactions  = "sync_transactions"
-	tableTransactions      = "transactions"
-	tableUTXOs             = "utxos"
-	tableXPubs             = "xpubs"
+	tableAccessKeys         = "access_keys"
+	tableBlockBUMPs         = "block_bumps"
+	tableBlockHeaders       = "block_headers"
+	tableBroadcastAttempts  = "broadcast_attempts"
+	tableBroadcastCallbacks = "broadcast_callbacks"
+	tableDestinations       = "destinations"
+	tableDraftTransactions  = "draft_transactions"
+	tableFeeHistory         = "fee_history"
+	tablePaymailAddresses   = "paymail_addresses"
+	tableSyncTransactions   = "sync_transactions"
+	tableTransactions       = "transactions"
+	tableUTXOs              = "utxos"
+	tableXPubs              = "xpubs"
 )
 
 const (
 	// ReferenceIDField is used for Paymail
 	ReferenceIDField = "reference_id"
 
+	// FeePriorityMetadataKey is the xPub metadata key of the default fee priority of its transactions
+	FeePriorityMetadataKey = "fee_priority"
+
+	// MonitorFilterMetadataKey is the transaction metadata key of the monitor filter which matched the transaction
+	MonitorFilterMetadataKey = "monitor_filter"
+
 	// Internal field names
 	aliasField           = "alias"
+	broadcastAtField     = "broadcast_at"
 	broadcastStatusField = "broadcast_status"
 	createdAtField       = "created_at"
 	currentBalanceField  = "current_balance"
@@ -75,6 +95,8 @@ const (
 	draftIDField         = "draft_id"
 	idField              = "id"
 	metadataField        = "metadata"
+	metadataPathField    = "metadata_path"
+	nextAttemptField     = "next_attempt"
 	nextExternalNumField = "next_external_num"
 	nextInternalNumField = "next_internal_num"
 	p2pStatusField       = "p2p_status"
@@ -89,17 +111,25 @@ const (
 	blockHashField       = "block_hash"
 	merkleProofField     = "merkle_proof"
 	bumpField            = "bump"
+	blockBUMPIDField     = "block_bump_id"
+	heightField          = "height"
+	longestChainField    = "longest_chain"
+	startedAtField       = "started_at"
+	txIDField            = "tx_id"
 
 	// Universal statuses
-	statusCanceled   = "canceled"
-	statusComplete   = "complete"
-	statusDraft      = "draft"
-	statusError      = "error"
-	statusExpired    = "expired"
-	statusPending    = "pending"
-	statusProcessing = "processing"
-	statusReady      = "ready"
-	statusSkipped    = "skipped"
+	statusCanceled     = "canceled"
+	statusComplete     = "complete"
+	statusDeadLetter   = "deadletter"
+	statusDraft        = "draft"
+	statusError        = "error"
+	statusExpired      = "expired"
+	statusPending      = "pending"
+	statusProcessing   = "processing"
+	statusReady        = "ready"
+	statusScheduled    = "scheduled"
+	statusSkipped      = "skipped"
+	statusUnverifiable = "unverifiable"
 
 	// Paymail / Handles
 	cacheKeyAddressResolution = "paymail-address-resolution-"
@@ -113,17 +143,20 @@ const (
 	p2pMetadataField          = "p2p_tx_metadata"
 
 	// Misc
-	gormTypeText = "text"
-	migrateList  = "migrate"
-	modelList    = "models"
+	cacheTTLTransactionBEEF = 24 * time.Hour
+	gormTypeText            = "text"
+	migrateList             = "migrate"
+	modelList               = "models"
 )
 
 // Cache keys for model caching
 const (
-	cacheKeyDestinationModel                = "destination-id-%s"             // model-id-<destination_id>
-	cacheKeyDestinationModelByAddress       = "destination-address-%s"        // model-address-<address>
-	cacheKeyDestinationModelByLockingScript = "destination-locking-script-%s" // model-locking-script-<script>
-	cacheKeyXpubModel                       = "xpub-id-%s"                    // model-id-<xpub_id>
+	cacheKeyDestinationModel                = "destination-id-%s"              // model-id-<destination_id>
+	cacheKeyDestinationModelByAddress       = "destination-address-%s"         // model-address-<address>
+	cacheKeyDestinationModelByLockingScript = "destination-locking-script-%s"  // model-locking-script-<script>
+	cacheKeyTransactionBEEF                 = "transaction-beef-%s"            // beef-<tx_id>
+	cacheKeyTransactionBEEFDependents       = "transaction-beef-dependents-%s" // beef-dependents-<tx_id>
+	cacheKeyXpubModel                       = "xpub-id-%s"                     // model-id-<xpub_id>
 )
 
 // BaseModels is the list of models for loading the engine and AutoMigration (defaults)
@@ -148,6 +181,11 @@ var BaseModels = []interface{}{
 		Model: *NewBaseModel(ModelTransaction),
 	},
 
+	// Merged BUMPs of the blocks (related to Transaction)
+	&BlockBUMP{
+		Model: *NewBaseModel(ModelBlockBUMP),
+	},
+
 	// Sync configuration for transactions (on-chain) (related to Transaction)
 	&SyncTransaction{
 		Model: *NewBaseModel(ModelSyncTransaction),
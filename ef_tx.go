@@ -0,0 +1,111 @@
+package bux
+
+import (
+	"context"
+	"encoding/hex"
+
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+)
+
+// ToEfHex generates Extended Format (BRC-30) hex for transaction
+//
+// The previous outputs (satoshis & locking script) of the inputs are taken from the inputs (utxos) of the draft
+// transaction, or else from the stored parent transactions.
+// Returns false if the previous output of any input cannot be found
+func ToEfHex(ctx context.Context, tx *Transaction, store TransactionGetter) (efHex string, ok bool) {
+	btTx := tx.parsedTx
+	if btTx == nil {
+		var err error
+		if btTx, err = bt.NewTxFromString(tx.Hex); err != nil {
+			return "", false
+		}
+	} else {
+		btTx = btTx.Clone()
+	}
+
+	needed := make([]*bt.Input, 0, len(btTx.Inputs))
+	for _, input := range btTx.Inputs {
+		if input.PreviousTxScript == nil {
+			needed = append(needed, input)
+		}
+	}
+
+	// previous outputs from the inputs of the draft transaction
+	if len(needed) > 0 && tx.draftTransaction == nil && tx.DraftID != "" && tx.Client() != nil {
+		_ = hydrateTransaction(ctx, tx)
+	}
+	if len(needed) > 0 && tx.draftTransaction != nil {
+		needed = fillEfInputsFromDraft(needed, tx.draftTransaction)
+	}
+
+	// previous outputs from the parent transactions
+	if len(needed) > 0 && store != nil {
+		parentIDs := make([]string, 0, len(needed))
+		for _, input := range needed {
+			parentIDs = append(parentIDs, input.PreviousTxIDStr())
+		}
+		parents, err := store.GetTransactionsByIDs(ctx, parentIDs)
+		if err != nil {
+			return "", false
+		}
+		needed = fillEfInputsFromParents(needed, parents)
+	}
+
+	if len(needed) > 0 {
+		return "", false
+	}
+
+	return hex.EncodeToString(btTx.ExtendedBytes()), true
+}
+
+// fillEfInputsFromDraft will set the previous outputs of the inputs using the draft transaction inputs,
+// returning the inputs which were not found
+func fillEfInputsFromDraft(inputs []*bt.Input, draft *DraftTransaction) []*bt.Input {
+	missing := make([]*bt.Input, 0)
+	for _, input := range inputs {
+		found := false
+		for _, draftInput := range draft.Configuration.Inputs {
+			if draftInput.TransactionID != input.PreviousTxIDStr() ||
+				draftInput.OutputIndex != input.PreviousTxOutIndex {
+				continue
+			}
+			script, err := bscript.NewFromHexString(draftInput.ScriptPubKey)
+			if err != nil {
+				break
+			}
+			input.PreviousTxSatoshis = draftInput.Satoshis
+			input.PreviousTxScript = script
+			found = true
+			break
+		}
+		if !found {
+			missing = append(missing, input)
+		}
+	}
+	return missing
+}
+
+// fillEfInputsFromParents will set the previous outputs of the inputs using the parent transactions,
+// returning the inputs which were not found
+func fillEfInputsFromParents(inputs []*bt.Input, parents []*Transaction) []*bt.Input {
+	parentTxs := make(map[string]*bt.Tx, len(parents))
+	for _, parent := range parents {
+		if parentTx, err := bt.NewTxFromString(parent.Hex); err == nil {
+			parentTxs[parent.ID] = parentTx
+		}
+	}
+
+	missing := make([]*bt.Input, 0)
+	for _, input := range inputs {
+		parentTx, ok := parentTxs[input.PreviousTxIDStr()]
+		if !ok || int(input.PreviousTxOutIndex) >= len(parentTx.Outputs) {
+			missing = append(missing, input)
+			continue
+		}
+		output := parentTx.Outputs[input.PreviousTxOutIndex]
+		input.PreviousTxSatoshis = output.Satoshis
+		input.PreviousTxScript = output.LockingScript
+	}
+	return missing
+}
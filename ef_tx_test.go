@@ -0,0 +1,60 @@
+package bux
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libsv/go-bt/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestToEfHex will test the method ToEfHex()
+func TestToEfHex(t *testing.T) {
+	_, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+	parent, child := txs[0], txs[1]
+
+	t.Run("previous outputs from the parent transactions", func(t *testing.T) {
+		store := NewMockTransactionStore()
+		store.AddToStore(&Transaction{TransactionBase: TransactionBase{ID: parent.TxID(), Hex: parent.String()}})
+
+		efHex, ok := ToEfHex(context.Background(), &Transaction{TransactionBase: TransactionBase{ID: child.TxID(), Hex: child.String()}}, store)
+		require.True(t, ok)
+
+		efTx, err := bt.NewTxFromString(efHex)
+		require.NoError(t, err)
+		assert.Equal(t, child.TxID(), efTx.TxID())
+		assert.Equal(t, parent.Outputs[0].Satoshis, efTx.Inputs[0].PreviousTxSatoshis)
+		assert.Equal(t, parent.Outputs[0].LockingScript.String(), efTx.Inputs[0].PreviousTxScript.String())
+	})
+
+	t.Run("previous outputs from the draft transaction", func(t *testing.T) {
+		draft := &DraftTransaction{Configuration: TransactionConfig{Inputs: []*TransactionInput{{
+			Utxo: Utxo{
+				UtxoPointer:  UtxoPointer{TransactionID: parent.TxID(), OutputIndex: 0},
+				Satoshis:     parent.Outputs[0].Satoshis,
+				ScriptPubKey: parent.Outputs[0].LockingScript.String(),
+			},
+		}}}}
+		tx := &Transaction{TransactionBase: TransactionBase{ID: child.TxID(), Hex: child.String()}, draftTransaction: draft}
+
+		efHex, ok := ToEfHex(context.Background(), tx, NewMockTransactionStore())
+		require.True(t, ok)
+
+		efTx, err := bt.NewTxFromString(efHex)
+		require.NoError(t, err)
+		assert.Equal(t, parent.Outputs[0].Satoshis, efTx.Inputs[0].PreviousTxSatoshis)
+		assert.Equal(t, child.String(), efTx.String())
+	})
+
+	t.Run("unknown previous outputs", func(t *testing.T) {
+		_, ok := ToEfHex(context.Background(), &Transaction{TransactionBase: TransactionBase{ID: child.TxID(), Hex: child.String()}},
+			NewMockTransactionStore())
+		assert.False(t, ok)
+	})
+
+	t.Run("invalid hex", func(t *testing.T) {
+		_, ok := ToEfHex(context.Background(), &Transaction{TransactionBase: TransactionBase{Hex: "invalid"}}, NewMockTransactionStore())
+		assert.False(t, ok)
+	})
+}
@@ -13,6 +13,12 @@ var ErrMissingDestination = errors.New("destination could not be found")
 // ErrMissingTransaction is an error when a transaction could not be found
 var ErrMissingTransaction = errors.New("transaction could not be found")
 
+// ErrMissingSyncTransaction is an error when a sync transaction could not be found
+var ErrMissingSyncTransaction = errors.New("sync transaction could not be found")
+
+// ErrSyncTransactionChanged is an error when the claimed action of a sync transaction was changed meanwhile (IE: canceled)
+var ErrSyncTransactionChanged = errors.New("sync transaction was changed while it was processed")
+
 // ErrMissingUtxo is an error when a given utxo could not be found
 var ErrMissingUtxo = errors.New("utxo could not be found")
 
@@ -91,6 +97,9 @@ var ErrTransactionFeeInvalid = errors.New("transaction fee is invalid")
 // ErrMissingUTXOsSpendable is when there are no utxos found from the "spendable utxos"
 var ErrMissingUTXOsSpendable = errors.New("no utxos found using spendable")
 
+// ErrUtxoUnconfirmedAncestorsLimit is when a draft transaction cannot be created because all the spendable utxos exceed the unconfirmed ancestors limit
+var ErrUtxoUnconfirmedAncestorsLimit = errors.New("could not select enough outputs: utxos exceed the unconfirmed ancestors limit")
+
 // ErrDuplicateUTXOs is when a transaction is created using the same utxo more than once
 var ErrDuplicateUTXOs = errors.New("duplicate utxos found")
 
@@ -189,3 +198,54 @@ var ErrMissingPaymailXPubID = errors.New("missing xpub_id in paymail")
 
 // ErrMissingClient missing client from model
 var ErrMissingClient = errors.New("client is missing from model, cannot save")
+
+// ErrInvalidCursor is when the cursor given for pagination cannot be decoded
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// ErrFilterInvalid is when a query filter cannot be compiled
+var ErrFilterInvalid = errors.New("invalid query filter")
+
+// ErrFilterUnknownField is when a query filter uses a field that does not exist on the model
+var ErrFilterUnknownField = errors.New("unknown query filter field")
+
+// ErrFilterUnknownModel is when a query filter is compiled for a model that is not loaded
+var ErrFilterUnknownModel = errors.New("unknown query filter model")
+
+// ErrInvalidBEEF is when the BEEF transaction cannot be decoded
+var ErrInvalidBEEF = errors.New("invalid BEEF transaction")
+
+// ErrBEEFVerificationFailed is when the SPV verification of the BEEF transaction failed
+var ErrBEEFVerificationFailed = errors.New("BEEF transaction SPV verification failed")
+
+// ErrBEEFFeeTooLow is when the fee of the BEEF transaction is lower than the current fee unit
+var ErrBEEFFeeTooLow = errors.New("BEEF transaction fee is too low")
+
+// ErrInvalidBlockHeader is when a block header cannot be decoded or does not satisfy its proof of work
+var ErrInvalidBlockHeader = errors.New("invalid block header")
+
+// ErrBlockHeaderNotConnected is when a block header does not connect to the stored header chain
+var ErrBlockHeaderNotConnected = errors.New("block header does not connect to the header chain")
+
+// ErrMerkleRootNotConfirmed is when a merkle root does not match the header of the longest chain at its height
+var ErrMerkleRootNotConfirmed = errors.New("not all merkle roots confirmed")
+
+// ErrBlockHeightNotSynced is when a merkle root is verified at a height which is not in the local header store (yet)
+var ErrBlockHeightNotSynced = errors.New("block height is not synced in the local header store")
+
+// ErrLocalHeaderStoreDisabled is when the local header store is used but was not enabled (WithLocalHeaderStore)
+var ErrLocalHeaderStoreDisabled = errors.New("local header store is not enabled")
+
+// ErrMissingBlockHeaderSource is when the block headers are synced without a block header source
+var ErrMissingBlockHeaderSource = errors.New("missing block header source")
+
+// ErrMissingAddressHistoryProvider is when an xPub is rescanned without an address history provider
+var ErrMissingAddressHistoryProvider = errors.New("missing address history provider")
+
+// ErrInvalidBroadcastCallback is when the broadcast callback payload is invalid
+var ErrInvalidBroadcastCallback = errors.New("invalid broadcast callback")
+
+// ErrBroadcastCallbackNotVerified is when the merkle path of the broadcast callback cannot be verified
+var ErrBroadcastCallbackNotVerified = errors.New("broadcast callback merkle path not verified")
+
+// ErrTransactionUnverifiable is when the transaction cannot be verified without a transaction lookup (SPV only mode)
+var ErrTransactionUnverifiable = errors.New("transaction cannot be verified without a transaction lookup in SPV only mode")
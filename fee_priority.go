@@ -0,0 +1,39 @@
+package bux
+
+import (
+	"context"
+
+	"github.com/BuxOrg/bux/chainstate"
+)
+
+// setDraftFeePriority will set the fee unit of the fee priority of the draft transaction
+//
+// The fee priority is the one of the transaction config, or the one of the xPub metadata (FeePriorityMetadataKey),
+// or the default fee priority (WithDefaultFeePriority). The chainstate fee unit is kept without any fee priority.
+func (c *Client) setDraftFeePriority(ctx context.Context, draft *DraftTransaction) error {
+	priority := draft.Configuration.FeePriority
+	if len(priority) == 0 {
+		xPub, err := getXpubWithCache(ctx, c, draft.rawXpubKey, "", draft.GetOptions(false)...)
+		if err != nil {
+			return err
+		} else if xPub != nil {
+			if value, ok := xPub.Metadata[FeePriorityMetadataKey].(string); ok {
+				priority = chainstate.FeePriority(value)
+			}
+		}
+	}
+	if len(priority) == 0 {
+		priority = c.options.chainstate.feePriority
+	}
+	if len(priority) == 0 {
+		return nil
+	}
+
+	feeUnit, err := c.Chainstate().FeeUnitForPriority(priority)
+	if err != nil {
+		return err
+	}
+	draft.Configuration.FeePriority = priority
+	draft.Configuration.FeeUnit = feeUnit
+	return nil
+}
@@ -0,0 +1,80 @@
+package bux
+
+import (
+	"testing"
+
+	"github.com/BuxOrg/bux/chainstate"
+	"github.com/BuxOrg/bux/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_NewTransaction_FeePriority will test the fee unit of the fee priorities
+func TestClient_NewTransaction_FeePriority(t *testing.T) {
+	newConfig := func(priority chainstate.FeePriority) *TransactionConfig {
+		return &TransactionConfig{
+			FeePriority: priority,
+			Outputs: []*TransactionOutput{{
+				To:       "1A1PjKqjWMNBzTVdcBru27EV1PHcXWc63W",
+				Satoshis: 1000,
+			}},
+		}
+	}
+
+	t.Run("chainstate fee unit without fee priority", func(t *testing.T) {
+		ctx, client, deferMe := initSimpleTestCase(t)
+		defer deferMe()
+
+		draft, err := client.NewTransaction(ctx, testXPub, newConfig(""), client.DefaultModelOptions()...)
+		require.NoError(t, err)
+		assert.Equal(t, chainstate.MockDefaultFee, draft.Configuration.FeeUnit)
+		assert.Empty(t, draft.Configuration.FeePriority)
+	})
+
+	t.Run("fee priority of the transaction config", func(t *testing.T) {
+		ctx, client, deferMe := initSimpleTestCase(t)
+		defer deferMe()
+
+		draft, err := client.NewTransaction(
+			ctx, testXPub, newConfig(chainstate.FeePriorityPriority), client.DefaultModelOptions()...,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, &utils.FeeUnit{Satoshis: 100, Bytes: 1000}, draft.Configuration.FeeUnit)
+		assert.Equal(t, chainstate.FeePriorityPriority, draft.Configuration.FeePriority)
+	})
+
+	t.Run("fee priority of the xPub metadata", func(t *testing.T) {
+		ctx, client, deferMe := initSimpleTestCase(t)
+		defer deferMe()
+
+		_, err := client.UpdateXpubMetadata(ctx, testXPubID, Metadata{
+			FeePriorityMetadataKey: string(chainstate.FeePriorityNormal),
+		})
+		require.NoError(t, err)
+
+		var draft *DraftTransaction
+		draft, err = client.NewTransaction(ctx, testXPub, newConfig(""), client.DefaultModelOptions()...)
+		require.NoError(t, err)
+		assert.Equal(t, &utils.FeeUnit{Satoshis: 75, Bytes: 1000}, draft.Configuration.FeeUnit)
+		assert.Equal(t, chainstate.FeePriorityNormal, draft.Configuration.FeePriority)
+	})
+
+	t.Run("fee unit over the fee priority", func(t *testing.T) {
+		ctx, client, deferMe := initSimpleTestCase(t)
+		defer deferMe()
+
+		config := newConfig(chainstate.FeePriorityPriority)
+		config.FeeUnit = &utils.FeeUnit{Satoshis: 1, Bytes: 10}
+		draft, err := client.NewTransaction(ctx, testXPub, config, client.DefaultModelOptions()...)
+		require.NoError(t, err)
+		assert.Equal(t, config.FeeUnit, draft.Configuration.FeeUnit)
+	})
+
+	t.Run("invalid fee priority", func(t *testing.T) {
+		ctx, client, deferMe := initSimpleTestCase(t)
+		defer deferMe()
+
+		_, err := client.NewTransaction(ctx, testXPub, newConfig("unknown"), client.DefaultModelOptions()...)
+		require.ErrorIs(t, err, chainstate.ErrInvalidFeePriority)
+	})
+}
@@ -19,6 +19,7 @@ require (
 	github.com/libsv/go-bk v0.1.6
 	github.com/libsv/go-bt v1.0.8
 	github.com/libsv/go-bt/v2 v2.2.5
+	github.com/libsv/go-p2p v0.2.1
 	github.com/mrz1836/go-cache v0.9.7
 	github.com/mrz1836/go-cachestore v0.3.9
 	github.com/mrz1836/go-datastore v0.5.21
@@ -34,6 +35,7 @@ require (
 	github.com/vmihailenco/taskq/v3 v3.2.9
 	go.elastic.co/ecszerolog v0.2.0
 	go.mongodb.org/mongo-driver v1.14.0
+	golang.org/x/time v0.5.0
 	gorm.io/gorm v1.25.9
 )
 
@@ -82,7 +84,6 @@ require (
 	github.com/klauspost/compress v1.17.7 // indirect
 	github.com/lestrrat-go/strftime v1.0.6 // indirect
 	github.com/lib/pq v1.10.9 // indirect
-	github.com/libsv/go-p2p v0.2.1 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/mattn/go-sqlite3 v1.14.22 // indirect
@@ -2,6 +2,7 @@ package bux
 
 import (
 	"context"
+	"io"
 	"net/http"
 
 	"github.com/BuxOrg/bux/chainstate"
@@ -23,6 +24,8 @@ type AccessKeyService interface {
 		queryParams *datastore.QueryParams, opts ...ModelOps) ([]*AccessKey, error)
 	GetAccessKeysCount(ctx context.Context, metadata *Metadata,
 		conditions *map[string]interface{}, opts ...ModelOps) (int64, error)
+	GetAccessKeysWithCursor(ctx context.Context, metadata *Metadata, conditions *map[string]interface{},
+		cursorParams *CursorQueryParams, opts ...ModelOps) ([]*AccessKey, string, error)
 	GetAccessKeysByXPubID(ctx context.Context, xPubID string, metadata *Metadata, conditions *map[string]interface{},
 		queryParams *datastore.QueryParams, opts ...ModelOps) ([]*AccessKey, error)
 	GetAccessKeysByXPubIDCount(ctx context.Context, xPubID string, metadata *Metadata,
@@ -38,10 +41,35 @@ type AdminService interface {
 		queryParams *datastore.QueryParams, opts ...ModelOps) ([]*PaymailAddress, error)
 	GetPaymailAddressesCount(ctx context.Context, metadataConditions *Metadata,
 		conditions *map[string]interface{}, opts ...ModelOps) (int64, error)
+	GetPaymailAddressesWithCursor(ctx context.Context, metadataConditions *Metadata, conditions *map[string]interface{},
+		cursorParams *CursorQueryParams, opts ...ModelOps) ([]*PaymailAddress, string, error)
+	GetBroadcastAttempts(ctx context.Context, txID string, opts ...ModelOps) ([]*BroadcastAttempt, error)
+	GetBroadcastCallbacks(ctx context.Context, conditions *map[string]interface{},
+		queryParams *datastore.QueryParams, opts ...ModelOps) ([]*BroadcastCallback, error)
+	GetFeeHistory(ctx context.Context, conditions *map[string]interface{},
+		queryParams *datastore.QueryParams, opts ...ModelOps) ([]*FeeHistory, error)
+	RefreshFeeUnit(ctx context.Context) (*chainstate.FeeUnitRefresh, error)
+	CancelSyncTransactions(ctx context.Context, ids []string, opts ...ModelOps) ([]*SyncTransaction, error)
+	GetSyncQueueSummary(ctx context.Context, opts ...ModelOps) (*SyncQueueSummary, error)
+	GetSyncTransactions(ctx context.Context, conditions *map[string]interface{},
+		queryParams *datastore.QueryParams, opts ...ModelOps) ([]*SyncTransaction, error)
+	GetSyncTransactionsCount(ctx context.Context, conditions *map[string]interface{},
+		opts ...ModelOps) (int64, error)
+	RetrySyncTransactions(ctx context.Context, ids []string, opts ...ModelOps) ([]*SyncTransaction, error)
+	SkipSyncTransactions(ctx context.Context, ids []string, opts ...ModelOps) ([]*SyncTransaction, error)
 	GetXPubs(ctx context.Context, metadataConditions *Metadata,
 		conditions *map[string]interface{}, queryParams *datastore.QueryParams, opts ...ModelOps) ([]*Xpub, error)
 	GetXPubsCount(ctx context.Context, metadataConditions *Metadata,
 		conditions *map[string]interface{}, opts ...ModelOps) (int64, error)
+	GetXPubsWithCursor(ctx context.Context, metadataConditions *Metadata, conditions *map[string]interface{},
+		cursorParams *CursorQueryParams, opts ...ModelOps) ([]*Xpub, string, error)
+}
+
+// BlockHeaderService is the block header (local header store) methods
+type BlockHeaderService interface {
+	GetBlockHeaderTip(ctx context.Context) (*BlockHeader, error)
+	ImportBlockHeaders(ctx context.Context, reader io.Reader, startHeight uint64) (uint64, error)
+	SyncBlockHeaders(ctx context.Context) (uint64, error)
 }
 
 // ClientService is the client related services
@@ -66,6 +94,8 @@ type DestinationService interface {
 		queryParams *datastore.QueryParams, opts ...ModelOps) ([]*Destination, error)
 	GetDestinationsCount(ctx context.Context, metadata *Metadata,
 		conditions *map[string]interface{}, opts ...ModelOps) (int64, error)
+	GetDestinationsWithCursor(ctx context.Context, metadata *Metadata, conditions *map[string]interface{},
+		cursorParams *CursorQueryParams, opts ...ModelOps) ([]*Destination, string, error)
 	GetDestinationsByXpubID(ctx context.Context, xPubID string, usingMetadata *Metadata, conditions *map[string]interface{},
 		queryParams *datastore.QueryParams) ([]*Destination, error)
 	GetDestinationsByXpubIDCount(ctx context.Context, xPubID string, usingMetadata *Metadata,
@@ -98,6 +128,7 @@ type HTTPInterface interface {
 type ModelService interface {
 	AddModels(ctx context.Context, autoMigrate bool, models ...interface{}) error
 	DefaultModelOptions(opts ...ModelOps) []ModelOps
+	FilterConditions(modelName ModelName, filter *Filter) (*map[string]interface{}, error)
 	GetModelNames() []string
 }
 
@@ -118,21 +149,30 @@ type PaymailService interface {
 
 // TransactionService is the transaction actions
 type TransactionService interface {
+	BroadcastCallbackHandler() http.Handler
 	GetTransaction(ctx context.Context, xPubID, txID string) (*Transaction, error)
+	GetTransactionBEEF(ctx context.Context, xPubID, txID string) (string, error)
 	GetTransactionsByIDs(ctx context.Context, txIDs []string) ([]*Transaction, error)
+	MigrateLegacyMerkleProofs(ctx context.Context) (*MerkleProofMigration, error)
 	GetTransactionByHex(ctx context.Context, hex string) (*Transaction, error)
 	GetTransactions(ctx context.Context, metadata *Metadata, conditions *map[string]interface{},
 		queryParams *datastore.QueryParams, opts ...ModelOps) ([]*Transaction, error)
 	GetTransactionsCount(ctx context.Context, metadata *Metadata,
 		conditions *map[string]interface{}, opts ...ModelOps) (int64, error)
+	GetTransactionsWithCursor(ctx context.Context, metadata *Metadata, conditions *map[string]interface{},
+		cursorParams *CursorQueryParams, opts ...ModelOps) ([]*Transaction, string, error)
+	IterateTransactions(ctx context.Context, conditions *map[string]interface{},
+		fn func(*Transaction) error, opts ...ModelOps) error
 	GetTransactionsByXpubID(ctx context.Context, xPubID string, metadata *Metadata, conditions *map[string]interface{},
 		queryParams *datastore.QueryParams) ([]*Transaction, error)
 	GetTransactionsByXpubIDCount(ctx context.Context, xPubID string, metadata *Metadata,
 		conditions *map[string]interface{}) (int64, error)
 	NewTransaction(ctx context.Context, rawXpubKey string, config *TransactionConfig,
 		opts ...ModelOps) (*DraftTransaction, error)
+	ProcessBroadcastCallback(ctx context.Context, callback *broadcast.SubmittedTx) error
 	RecordTransaction(ctx context.Context, xPubKey, txHex, draftID string,
 		opts ...ModelOps) (*Transaction, error)
+	RecordBEEFTransaction(ctx context.Context, beefHex string, opts ...ModelOps) (*Transaction, error)
 	RecordRawTransaction(ctx context.Context, txHex string, opts ...ModelOps) (*Transaction, error)
 	UpdateTransaction(ctx context.Context, txInfo *broadcast.SubmittedTx) error
 	UpdateTransactionMetadata(ctx context.Context, xPubID, id string, metadata Metadata) (*Transaction, error)
@@ -147,6 +187,10 @@ type UTXOService interface {
 		queryParams *datastore.QueryParams, opts ...ModelOps) ([]*Utxo, error)
 	GetUtxosCount(ctx context.Context, metadata *Metadata,
 		conditions *map[string]interface{}, opts ...ModelOps) (int64, error)
+	GetUtxosWithCursor(ctx context.Context, metadata *Metadata, conditions *map[string]interface{},
+		cursorParams *CursorQueryParams, opts ...ModelOps) ([]*Utxo, string, error)
+	IterateUtxos(ctx context.Context, conditions *map[string]interface{},
+		fn func(*Utxo) error, opts ...ModelOps) error
 	GetUtxosByXpubID(ctx context.Context, xPubID string, metadata *Metadata, conditions *map[string]interface{},
 		queryParams *datastore.QueryParams) ([]*Utxo, error)
 	UnReserveUtxos(ctx context.Context, xPubID, draftID string) error
@@ -157,6 +201,7 @@ type XPubService interface {
 	GetXpub(ctx context.Context, xPubKey string) (*Xpub, error)
 	GetXpubByID(ctx context.Context, xPubID string) (*Xpub, error)
 	NewXpub(ctx context.Context, xPubKey string, opts ...ModelOps) (*Xpub, error)
+	RescanXpub(ctx context.Context, xPubKey string, gapLimit uint32) (*XpubRescan, error)
 	UpdateXpubMetadata(ctx context.Context, xPubID string, metadata Metadata) (*Xpub, error)
 }
 
@@ -164,6 +209,7 @@ type XPubService interface {
 type ClientInterface interface {
 	AccessKeyService
 	AdminService
+	BlockHeaderService
 	ClientService
 	DestinationService
 	DraftTransactionService
@@ -184,7 +230,11 @@ type ClientInterface interface {
 	IsIUCEnabled() bool
 	IsMigrationEnabled() bool
 	IsNewRelicEnabled() bool
+	IsSPVOnlyEnabled() bool
+	MaxUnconfirmedAncestors() uint32
 	SetNotificationsClient(notifications.ClientInterface)
+	SyncRetryPolicy() *SyncRetryPolicy
+	SyncWorkers() int
 	UserAgent() string
 	Version() string
 	Metrics() (metrics *metrics.Metrics, enabled bool)
@@ -7,11 +7,16 @@ import (
 )
 
 const (
+	lockKeyBlockBUMP          = "block-bump-%s" // + Merkle root
+	lockKeyBlockHeaders       = "block-headers"
+	lockKeyBroadcastCallback  = "broadcast-callback-%s"            // + Tx ID
+	lockKeyClaimSyncTx        = "claim-sync-transaction-%s"        // + Tx ID
 	lockKeyProcessBroadcastTx = "process-broadcast-transaction-%s" // + Tx ID
 	lockKeyProcessP2PTx       = "process-p2p-transaction-%s"       // + Tx ID
 	lockKeyProcessSyncTx      = "process-sync-transaction-task"
 	lockKeyProcessXpub        = "action-xpub-id-%s"            // + Xpub ID
 	lockKeyRecordTx           = "action-record-transaction-%s" // + Tx ID
+	lockKeyTransactionBEEF    = "beef-dependents-%s"           // + Tx ID
 	lockKeyReserveUtxo        = "utxo-reserve-xpub-id-%s"      // + Xpub ID
 )
 
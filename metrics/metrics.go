@@ -21,6 +21,11 @@ type Metrics struct {
 	verifyMerkleRoots *prometheus.HistogramVec
 	recordTransaction *prometheus.HistogramVec
 	queryTransaction  *prometheus.HistogramVec
+	broadcast         *prometheus.HistogramVec
+
+	// the health of each broadcast provider (score and circuit breaker)
+	broadcastProviderScore   *prometheus.GaugeVec
+	broadcastProviderCircuit *prometheus.GaugeVec
 
 	// each cronJob is observed by the duration it takes to execute and the last time it was executed
 	cronHistogram     *prometheus.HistogramVec
@@ -35,8 +40,12 @@ func NewMetrics(collector Collector) *Metrics {
 		verifyMerkleRoots: collector.RegisterHistogramVec(verifyMerkleRootsHistogramName, "classification"),
 		recordTransaction: collector.RegisterHistogramVec(recordTransactionHistogramName, "classification", "strategy"),
 		queryTransaction:  collector.RegisterHistogramVec(queryTransactionHistogramName, "classification"),
+		broadcast:         collector.RegisterHistogramVec(broadcastHistogramName, "classification", "provider"),
 		cronHistogram:     collector.RegisterHistogramVec(cronHistogramName, "name"),
 		cronLastExecution: collector.RegisterGaugeVec(cronLastExecutionGaugeName, "name"),
+
+		broadcastProviderScore:   collector.RegisterGaugeVec(broadcastProviderScoreGaugeName, "provider"),
+		broadcastProviderCircuit: collector.RegisterGaugeVec(broadcastProviderCircuitGaugeName, "provider"),
 	}
 }
 
@@ -67,6 +76,24 @@ func (m *Metrics) TrackQueryTransaction() EndWithClassification {
 	}
 }
 
+// TrackBroadcast is used to track the time it takes to broadcast a transaction to a provider
+func (m *Metrics) TrackBroadcast(provider string) EndWithClassification {
+	start := time.Now()
+	return func(success bool) {
+		m.broadcast.WithLabelValues(classify(success), provider).Observe(time.Since(start).Seconds())
+	}
+}
+
+// SetBroadcastProviderHealth is used to export the health score of a broadcast provider and if its circuit is open
+func (m *Metrics) SetBroadcastProviderHealth(provider string, score float64, circuitOpen bool) {
+	m.broadcastProviderScore.WithLabelValues(provider).Set(score)
+	if circuitOpen {
+		m.broadcastProviderCircuit.WithLabelValues(provider).Set(1)
+	} else {
+		m.broadcastProviderCircuit.WithLabelValues(provider).Set(0)
+	}
+}
+
 // TrackCron is used to track the time it takes to execute a cron job
 func (m *Metrics) TrackCron(name string) EndWithClassification {
 	start := time.Now()
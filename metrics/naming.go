@@ -6,6 +6,12 @@ const (
 	verifyMerkleRootsHistogramName = domainPrefix + "verify_merkle_roots_histogram"
 	recordTransactionHistogramName = domainPrefix + "record_transaction_histogram"
 	queryTransactionHistogramName  = domainPrefix + "query_transaction_histogram"
+	broadcastHistogramName         = domainPrefix + "broadcast_histogram"
+)
+
+const (
+	broadcastProviderScoreGaugeName   = domainPrefix + "broadcast_provider_score_gauge"
+	broadcastProviderCircuitGaugeName = domainPrefix + "broadcast_provider_circuit_open_gauge"
 )
 
 const (
@@ -18,6 +18,10 @@ func (c *chainStateBase) Broadcast(context.Context, string, string, time.Duratio
 	return "", nil
 }
 
+func (c *chainStateBase) ProviderStatus() []*chainstate.ProviderStatus {
+	return nil
+}
+
 func (c *chainStateBase) QueryTransaction(context.Context, string,
 	chainstate.RequiredIn, time.Duration,
 ) (*chainstate.TransactionInfo, error) {
@@ -60,6 +64,16 @@ func (c *chainStateBase) QueryTimeout() time.Duration {
 	return 10 * time.Second
 }
 
+func (c *chainStateBase) RefreshFeeUnit(context.Context) (*chainstate.FeeUnitRefresh, error) {
+	return nil, chainstate.ErrFeeQuotesDisabled
+}
+
+func (c *chainStateBase) SetFeeUnit(*utils.FeeUnit) {}
+
+func (c *chainStateBase) FeeUnitForPriority(priority chainstate.FeePriority) (*utils.FeeUnit, error) {
+	return chainstate.DefaultFeePriorities().FeeUnit(chainstate.MockDefaultFee, priority)
+}
+
 func (c *chainStateBase) ValidateMiners(_ context.Context) {}
 
 type chainStateEverythingInMempool struct {
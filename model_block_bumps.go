@@ -0,0 +1,279 @@
+package bux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/BuxOrg/bux/utils"
+	"github.com/libsv/go-bc"
+	"github.com/mrz1836/go-datastore"
+)
+
+// blockBUMPIDUnresolved is the block BUMP ID of the mined transactions whose BUMP could not be resolved
+// by the merkle proofs migration (see MigrateLegacyMerkleProofs)
+const blockBUMPIDUnresolved = "unresolved"
+
+// BlockBUMP is an object representing the merged BUMP of all the known transactions of a block
+//
+// The block is identified by its merkle root (it can always be calculated from the BUMP, unlike the block hash).
+// Transactions reference the block BUMP and the offset of their leaf, so the shared upper levels of the
+// merkle paths are stored only once per block
+//
+// Gorm related models & indexes: https://gorm.io/docs/models.html - https://gorm.io/docs/indexes.html
+type BlockBUMP struct {
+	// Base model
+	Model `bson:",inline"`
+
+	// Model specific fields
+	ID          string `json:"id" toml:"id" yaml:"id" gorm:"<-:create;type:char(64);primaryKey;comment:This is the merkle root of the block" bson:"_id"`
+	BlockHeight uint64 `json:"block_height" toml:"block_height" yaml:"block_height" gorm:"<-:create;type:bigint;index;comment:This is the block height" bson:"block_height"`
+	BUMP        BUMP   `json:"bump" toml:"bump" yaml:"bump" gorm:"<-;type:text;comment:Merged BSV Unified Merkle Path (BUMP) of the block" bson:"bump"`
+}
+
+// newBlockBUMP will start a new model for the block with the given merkle root
+func newBlockBUMP(merkleRoot string, blockHeight uint64, opts ...ModelOps) *BlockBUMP {
+	return &BlockBUMP{
+		ID:          merkleRoot,
+		Model:       *NewBaseModel(ModelBlockBUMP, opts...),
+		BlockHeight: blockHeight,
+	}
+}
+
+// getBlockBUMP will get the block BUMP with the given merkle root
+func getBlockBUMP(ctx context.Context, merkleRoot string, opts ...ModelOps) (*BlockBUMP, error) {
+	blockBUMP := &BlockBUMP{ID: merkleRoot}
+	blockBUMP.enrich(ModelBlockBUMP, opts...)
+
+	if err := Get(ctx, blockBUMP, nil, false, defaultDatabaseReadTimeout, false); err != nil {
+		if errors.Is(err, datastore.ErrNoResults) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return blockBUMP, nil
+}
+
+// getBlockBUMPsByIDs will get the block BUMPs with the given merkle roots
+func getBlockBUMPsByIDs(ctx context.Context, merkleRoots []string, opts ...ModelOps) ([]*BlockBUMP, error) {
+	modelItems := make([]*BlockBUMP, 0)
+	if err := getModelsByConditions(
+		ctx, ModelBlockBUMP, &modelItems, nil, generateTxIDFilterConditions(merkleRoots), nil, opts...,
+	); err != nil {
+		return nil, err
+	}
+
+	return modelItems, nil
+}
+
+// merge will merge the BUMP of a transaction into the block BUMP
+func (m *BlockBUMP) merge(bump BUMP) error {
+	if len(m.BUMP.Path) == 0 {
+		m.BUMP = BUMP{BlockHeight: bump.BlockHeight, Path: bump.Path}
+		return nil
+	}
+
+	merged, err := CalculateMergedBUMP([]BUMP{m.BUMP, bump})
+	if err != nil {
+		return err
+	}
+	m.BUMP = *merged
+	return nil
+}
+
+// GetModelName will get the name of the current model
+func (m *BlockBUMP) GetModelName() string {
+	return ModelBlockBUMP.String()
+}
+
+// GetModelTableName will get the db table name of the current model
+func (m *BlockBUMP) GetModelTableName() string {
+	return tableBlockBUMPs
+}
+
+// Save will save the model into the Datastore
+func (m *BlockBUMP) Save(ctx context.Context) error {
+	return Save(ctx, m)
+}
+
+// GetID will get the ID
+func (m *BlockBUMP) GetID() string {
+	return m.ID
+}
+
+// BeforeCreating will fire before the model is being inserted into the Datastore
+func (m *BlockBUMP) BeforeCreating(_ context.Context) error {
+	m.Client().Logger().Debug().
+		Str("blockBumpID", m.ID).
+		Msgf("starting: %s BeforeCreating hook...", m.Name())
+
+	// Make sure ID is valid
+	if len(m.ID) == 0 {
+		return ErrMissingFieldID
+	}
+
+	m.Client().Logger().Debug().
+		Str("blockBumpID", m.ID).
+		Msgf("end: %s BeforeCreating hook", m.Name())
+	return nil
+}
+
+// Migrate model specific migration on startup
+func (m *BlockBUMP) Migrate(client datastore.ClientInterface) error {
+	return client.IndexMetadata(client.GetTableName(tableBlockBUMPs), metadataField)
+}
+
+// storeBlockBUMP will merge the BUMP of the transaction into its block BUMP and reference the block BUMP
+//
+// Returns false if the BUMP could not be stored, it is then kept on the transaction
+func (m *Transaction) storeBlockBUMP(ctx context.Context) bool {
+	offset, ok := m.BUMP.txLeafOffset(m.ID)
+	if !ok {
+		return false
+	}
+
+	merkleRoot, err := m.BUMP.calculateMerkleRoot()
+	if err == nil && merkleRoot != m.BlockBUMPID {
+		err = mergeBlockBUMP(ctx, m.Client(), merkleRoot, m.BUMP, m.GetOptions(false)...)
+	}
+	if err != nil {
+		m.Client().Logger().Warn().
+			Str("txID", m.ID).
+			Err(err).
+			Msg("could not store the BUMP in the block BUMP")
+		return false
+	}
+
+	m.BlockBUMPID = merkleRoot
+	m.BUMPOffset = offset
+	return true
+}
+
+// mergeBlockBUMP will merge the BUMP into the block BUMP with the given merkle root (created if not found)
+func mergeBlockBUMP(ctx context.Context, client ClientInterface, merkleRoot string, bump BUMP, opts ...ModelOps) error {
+	unlock, err := newWaitWriteLock(ctx, fmt.Sprintf(lockKeyBlockBUMP, merkleRoot), client.Cachestore())
+	defer unlock()
+	if err != nil {
+		return err
+	}
+
+	var blockBUMP *BlockBUMP
+	if blockBUMP, err = getBlockBUMP(ctx, merkleRoot, opts...); err != nil {
+		return err
+	} else if blockBUMP == nil {
+		blockBUMP = newBlockBUMP(merkleRoot, bump.BlockHeight, append(opts, New())...)
+	}
+
+	if err = blockBUMP.merge(bump); err != nil {
+		return err
+	}
+	return blockBUMP.Save(ctx)
+}
+
+// loadTransactionBUMPs will set the BUMPs of the transactions from the referenced block BUMPs
+func loadTransactionBUMPs(ctx context.Context, transactions []*Transaction, opts ...ModelOps) error {
+	merkleRoots := make([]string, 0)
+	for _, tx := range transactions {
+		if tx.BlockBUMPID != "" && tx.BlockBUMPID != blockBUMPIDUnresolved && len(tx.BUMP.Path) == 0 &&
+			!utils.StringInSlice(tx.BlockBUMPID, merkleRoots) {
+			merkleRoots = append(merkleRoots, tx.BlockBUMPID)
+		}
+	}
+	if len(merkleRoots) == 0 {
+		return nil
+	}
+
+	blockBUMPs, err := getBlockBUMPsByIDs(ctx, merkleRoots, opts...)
+	if err != nil {
+		return err
+	}
+	bumps := make(map[string]*BUMP, len(blockBUMPs))
+	for _, blockBUMP := range blockBUMPs {
+		bumps[blockBUMP.ID] = &blockBUMP.BUMP
+	}
+
+	for _, tx := range transactions {
+		bump, ok := bumps[tx.BlockBUMPID]
+		if !ok || len(tx.BUMP.Path) > 0 {
+			continue
+		}
+		if tx.BUMP, err = bump.extract(tx.BUMPOffset); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// txLeafOffset will return the offset of the txid leaf of the transaction in the BUMP
+func (bump *BUMP) txLeafOffset(txID string) (uint64, bool) {
+	if len(bump.Path) == 0 {
+		return 0, false
+	}
+	for _, leaf := range bump.Path[0] {
+		if leaf.TxID && leaf.Hash == txID {
+			return leaf.Offset, true
+		}
+	}
+	return 0, false
+}
+
+// extract will return the BUMP of the single transaction at the offset (leaf and its pairs up to the merkle root)
+func (bump *BUMP) extract(offset uint64) (BUMP, error) {
+	if len(bump.Path) == 0 {
+		return BUMP{}, errors.New("empty BUMP given")
+	}
+
+	txLeaf := findLeafByOffset(offset, bump.Path[0])
+	if txLeaf == nil || !txLeaf.TxID {
+		return BUMP{}, fmt.Errorf("could not find txid leaf at offset %d", offset)
+	}
+
+	extracted := BUMP{BlockHeight: bump.BlockHeight, Path: make([][]BUMPLeaf, len(bump.Path))}
+	for level := range bump.Path {
+		pairOffset := getOffsetPair(offset >> level)
+		pair := findLeafByOffset(pairOffset, bump.Path[level])
+		if pair == nil {
+			// the pair is not in the path when it can be calculated from the lower levels
+			hash, err := bump.nodeHash(level, pairOffset)
+			if err != nil {
+				return BUMP{}, err
+			}
+			pair = &BUMPLeaf{Offset: pairOffset, Hash: hash}
+		}
+		pair.TxID = false
+
+		if level == 0 {
+			extracted.Path[level] = []BUMPLeaf{*txLeaf, *pair}
+			sort.Slice(extracted.Path[level], func(i, j int) bool {
+				return extracted.Path[level][i].Offset < extracted.Path[level][j].Offset
+			})
+			continue
+		}
+		extracted.Path[level] = []BUMPLeaf{*pair}
+	}
+
+	return extracted, nil
+}
+
+// nodeHash will return the hash of the node at the level and offset, calculating it from the lower levels if needed
+func (bump *BUMP) nodeHash(level int, offset uint64) (string, error) {
+	if leaf := findLeafByOffset(offset, bump.Path[level]); leaf != nil && !leaf.Duplicate {
+		return leaf.Hash, nil
+	} else if level == 0 {
+		return "", errors.New("could not find pair")
+	}
+
+	left, err := bump.nodeHash(level-1, offset*2)
+	if err != nil {
+		return "", err
+	}
+	right := left
+	if leaf := findLeafByOffset(offset*2+1, bump.Path[level-1]); leaf == nil || !leaf.Duplicate {
+		if right, err = bump.nodeHash(level-1, offset*2+1); err != nil {
+			return "", err
+		}
+	}
+
+	return bc.MerkleTreeParentStr(left, right)
+}
@@ -0,0 +1,164 @@
+package bux
+
+import (
+	"testing"
+
+	"github.com/BuxOrg/bux/utils"
+	"github.com/libsv/go-bc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// createTestBlockBUMPs will create the BUMPs of the transactions at the offsets 0 and 2 of a block with 4 transactions
+func createTestBlockBUMPs(t *testing.T, txIDs ...string) (BUMP, BUMP) {
+	hashes := make([]string, 4)
+	for i := range hashes {
+		var err error
+		hashes[i], err = utils.RandomHex(32)
+		require.NoError(t, err)
+	}
+	copy(hashes, txIDs)
+	if len(txIDs) > 1 {
+		hashes[2] = txIDs[1]
+	}
+
+	left, err := bc.MerkleTreeParentStr(hashes[0], hashes[1])
+	require.NoError(t, err)
+	right, err := bc.MerkleTreeParentStr(hashes[2], hashes[3])
+	require.NoError(t, err)
+
+	return BUMP{
+		BlockHeight: 800000,
+		Path: [][]BUMPLeaf{
+			{{Offset: 0, Hash: hashes[0], TxID: true}, {Offset: 1, Hash: hashes[1]}},
+			{{Offset: 1, Hash: right}},
+		},
+	}, BUMP{
+		BlockHeight: 800000,
+		Path: [][]BUMPLeaf{
+			{{Offset: 2, Hash: hashes[2], TxID: true}, {Offset: 3, Hash: hashes[3]}},
+			{{Offset: 0, Hash: left}},
+		},
+	}
+}
+
+// TestBUMPModel_extract will test the method extract()
+func TestBUMPModel_extract(t *testing.T) {
+	t.Parallel()
+
+	first, second := createTestBlockBUMPs(t)
+
+	t.Run("extract from merged BUMP", func(t *testing.T) {
+		merged, err := CalculateMergedBUMP([]BUMP{first, second})
+		require.NoError(t, err)
+
+		var extracted BUMP
+		extracted, err = merged.extract(0)
+		require.NoError(t, err)
+		assert.Equal(t, first, extracted)
+
+		extracted, err = merged.extract(2)
+		require.NoError(t, err)
+		assert.Equal(t, second, extracted)
+	})
+
+	t.Run("calculate the missing pairs", func(t *testing.T) {
+		bump := BUMP{
+			BlockHeight: 800000,
+			Path: [][]BUMPLeaf{
+				{first.Path[0][0], first.Path[0][1], second.Path[0][0], second.Path[0][1]},
+				{},
+			},
+		}
+
+		extracted, err := bump.extract(0)
+		require.NoError(t, err)
+		assert.Equal(t, first, extracted)
+	})
+
+	t.Run("no txid leaf at the offset", func(t *testing.T) {
+		_, err := first.extract(1)
+		require.Error(t, err)
+
+		_, err = (&BUMP{}).extract(0)
+		require.Error(t, err)
+	})
+}
+
+// TestTransaction_storeBlockBUMP will test storing the BUMPs of the transactions in the block BUMP
+func TestTransaction_storeBlockBUMP(t *testing.T) {
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup())
+	defer deferMe()
+
+	_, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+	bumps := make([]BUMP, 2)
+	bumps[0], bumps[1] = createTestBlockBUMPs(t, txs[0].TxID(), txs[1].TxID())
+
+	for i, btTx := range txs {
+		tx, err := txFromHex(btTx.String(), append(client.DefaultModelOptions(), New())...)
+		require.NoError(t, err)
+		tx.BUMP = bumps[i]
+		tx.BlockHeight = bumps[i].BlockHeight
+		require.NoError(t, tx.Save(ctx))
+
+		// the BUMP is kept on the saved model
+		assert.Equal(t, bumps[i], tx.BUMP)
+		assert.NotEmpty(t, tx.BlockBUMPID)
+	}
+
+	merkleRoot, err := bumps[0].calculateMerkleRoot()
+	require.NoError(t, err)
+
+	t.Run("one BUMP per block", func(t *testing.T) {
+		blockBUMP, err := getBlockBUMP(ctx, merkleRoot, client.DefaultModelOptions()...)
+		require.NoError(t, err)
+		require.NotNil(t, blockBUMP)
+		assert.Equal(t, uint64(800000), blockBUMP.BlockHeight)
+
+		offset, ok := blockBUMP.BUMP.txLeafOffset(txs[0].TxID())
+		assert.True(t, ok)
+		assert.Equal(t, uint64(0), offset)
+		offset, ok = blockBUMP.BUMP.txLeafOffset(txs[1].TxID())
+		assert.True(t, ok)
+		assert.Equal(t, uint64(2), offset)
+	})
+
+	t.Run("the BUMP is not stored on the transaction", func(t *testing.T) {
+		tx := emptyTx(client.DefaultModelOptions()...)
+		tx.ID = txs[0].TxID()
+		require.NoError(t, Get(ctx, tx, nil, false, defaultDatabaseReadTimeout, false))
+		assert.Empty(t, tx.BUMP.Path)
+		assert.Equal(t, merkleRoot, tx.BlockBUMPID)
+	})
+
+	t.Run("the BUMP is cleared on mongo", func(t *testing.T) {
+		// Mongo updates the transaction with $set, the empty fields must be set to clear the previous values
+		raw, err := bson.Marshal(emptyTx(client.DefaultModelOptions()...))
+		require.NoError(t, err)
+		for _, field := range []string{bumpField, "block_bump_id", "bump_offset"} {
+			_, err = bson.Raw(raw).LookupErr(field)
+			assert.NoError(t, err, field)
+		}
+	})
+
+	t.Run("transactions are loaded with their BUMP", func(t *testing.T) {
+		transactions, err := client.GetTransactionsByIDs(ctx, []string{txs[0].TxID(), txs[1].TxID()})
+		require.NoError(t, err)
+		require.Len(t, transactions, 2)
+		for _, tx := range transactions {
+			assert.Equal(t, merkleRoot, tx.BlockBUMPID)
+			if tx.ID == txs[0].TxID() {
+				assert.Equal(t, bumps[0], tx.BUMP)
+			} else {
+				assert.Equal(t, bumps[1], tx.BUMP)
+				assert.Equal(t, uint64(2), tx.BUMPOffset)
+			}
+		}
+
+		var tx *Transaction
+		tx, err = client.GetTransaction(ctx, "", txs[1].TxID())
+		require.NoError(t, err)
+		assert.Equal(t, bumps[1], tx.BUMP)
+	})
+}
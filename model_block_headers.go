@@ -0,0 +1,186 @@
+package bux
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+
+	"github.com/libsv/go-bc"
+	"github.com/libsv/go-bk/crypto"
+	"github.com/libsv/go-bt/v2"
+	"github.com/mrz1836/go-datastore"
+)
+
+// BlockHeader is an object representing a block header of the local header store
+//
+// The headers are linked by the hash of the previous block, the headers of the longest chain
+// (the chain with the most cumulative work) are flagged with LongestChain
+//
+// Gorm related models & indexes: https://gorm.io/docs/models.html - https://gorm.io/docs/indexes.html
+type BlockHeader struct {
+	// Base model
+	Model `bson:",inline"`
+
+	// Model specific fields
+	ID                string `json:"id" toml:"id" yaml:"id" gorm:"<-:create;type:char(64);primaryKey;comment:This is the block hash" bson:"_id"`
+	Height            uint64 `json:"height" toml:"height" yaml:"height" gorm:"<-:create;index;comment:This is the block height" bson:"height"`
+	HashPreviousBlock string `json:"hash_previous_block" toml:"hash_previous_block" yaml:"hash_previous_block" gorm:"<-:create;type:char(64);comment:This is the hash of the previous block" bson:"hash_previous_block"`
+	MerkleRoot        string `json:"merkle_root" toml:"merkle_root" yaml:"merkle_root" gorm:"<-:create;type:char(64);index;comment:This is the merkle root of the block" bson:"merkle_root"`
+	Version           uint32 `json:"version" toml:"version" yaml:"version" gorm:"<-:create;comment:This is the block version" bson:"version"`
+	Time              uint32 `json:"time" toml:"time" yaml:"time" gorm:"<-:create;comment:This is the block timestamp" bson:"time"`
+	Bits              string `json:"bits" toml:"bits" yaml:"bits" gorm:"<-:create;type:char(8);comment:This is the difficulty target in compact format" bson:"bits"`
+	Nonce             uint32 `json:"nonce" toml:"nonce" yaml:"nonce" gorm:"<-:create;comment:This is the block nonce" bson:"nonce"`
+	ChainWork         string `json:"chain_work" toml:"chain_work" yaml:"chain_work" gorm:"<-:create;type:char(64);comment:This is the cumulative work of the chain up to this block (hex)" bson:"chain_work"`
+	LongestChain      bool   `json:"longest_chain" toml:"longest_chain" yaml:"longest_chain" gorm:"<-;index;comment:If the block is part of the longest chain" bson:"longest_chain"`
+}
+
+// newBlockHeader will start a new model from the header, at the given height and with the given cumulative work
+func newBlockHeader(header *bc.BlockHeader, height uint64, chainWork *big.Int, opts ...ModelOps) *BlockHeader {
+	return &BlockHeader{
+		ID:                blockHeaderHash(header),
+		Model:             *NewBaseModel(ModelBlockHeader, opts...),
+		Height:            height,
+		HashPreviousBlock: header.HashPrevBlockStr(),
+		MerkleRoot:        header.HashMerkleRootStr(),
+		Version:           header.Version,
+		Time:              header.Time,
+		Bits:              header.BitsStr(),
+		Nonce:             header.Nonce,
+		ChainWork:         chainWorkToHex(chainWork),
+	}
+}
+
+// getBlockHeader will get the block header with the given hash
+func getBlockHeader(ctx context.Context, hash string, opts ...ModelOps) (*BlockHeader, error) {
+	header := &BlockHeader{ID: hash}
+	header.enrich(ModelBlockHeader, opts...)
+
+	if err := Get(ctx, header, nil, false, defaultDatabaseReadTimeout, false); err != nil {
+		if errors.Is(err, datastore.ErrNoResults) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return header, nil
+}
+
+// getBlockHeaderByHeight will get the block header of the longest chain at the given height
+func getBlockHeaderByHeight(ctx context.Context, height uint64, opts ...ModelOps) (*BlockHeader, error) {
+	header := &BlockHeader{}
+	header.enrich(ModelBlockHeader, opts...)
+
+	conditions := map[string]interface{}{
+		heightField:       height,
+		longestChainField: true,
+	}
+	if err := Get(ctx, header, conditions, false, defaultDatabaseReadTimeout, false); err != nil {
+		if errors.Is(err, datastore.ErrNoResults) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return header, nil
+}
+
+// getChainTip will get the block header at the tip of the longest chain
+func getChainTip(ctx context.Context, opts ...ModelOps) (*BlockHeader, error) {
+	headers, err := getBlockHeaders(ctx, &map[string]interface{}{longestChainField: true}, &datastore.QueryParams{
+		Page:          1,
+		PageSize:      1,
+		OrderByField:  heightField,
+		SortDirection: datastore.SortDesc,
+	}, opts...)
+	if err != nil || len(headers) == 0 {
+		return nil, err
+	}
+	return headers[0], nil
+}
+
+// getBlockHeaders will get all the block headers with the given conditions
+func getBlockHeaders(ctx context.Context, conditions *map[string]interface{},
+	queryParams *datastore.QueryParams, opts ...ModelOps,
+) ([]*BlockHeader, error) {
+	modelItems := make([]*BlockHeader, 0)
+	if err := getModelsByConditions(ctx, ModelBlockHeader, &modelItems, nil, conditions, queryParams, opts...); err != nil {
+		return nil, err
+	}
+
+	// Loop and enrich
+	for index := range modelItems {
+		modelItems[index].enrich(ModelBlockHeader, opts...)
+	}
+
+	return modelItems, nil
+}
+
+// chainWork will return the cumulative work of the chain up to the block header
+func (m *BlockHeader) chainWork() *big.Int {
+	work, ok := new(big.Int).SetString(m.ChainWork, 16)
+	if !ok {
+		return big.NewInt(0)
+	}
+	return work
+}
+
+// GetModelName will get the name of the current model
+func (m *BlockHeader) GetModelName() string {
+	return ModelBlockHeader.String()
+}
+
+// GetModelTableName will get the db table name of the current model
+func (m *BlockHeader) GetModelTableName() string {
+	return tableBlockHeaders
+}
+
+// Save will save the model into the Datastore
+func (m *BlockHeader) Save(ctx context.Context) error {
+	return Save(ctx, m)
+}
+
+// GetID will get the ID
+func (m *BlockHeader) GetID() string {
+	return m.ID
+}
+
+// BeforeCreating will fire before the model is being inserted into the Datastore
+func (m *BlockHeader) BeforeCreating(_ context.Context) error {
+	m.Client().Logger().Debug().
+		Str("blockHeaderID", m.ID).
+		Msgf("starting: %s BeforeCreating hook...", m.Name())
+
+	// Make sure ID is valid
+	if len(m.ID) == 0 {
+		return ErrMissingFieldID
+	}
+
+	m.Client().Logger().Debug().
+		Str("blockHeaderID", m.ID).
+		Msgf("end: %s BeforeCreating hook", m.Name())
+	return nil
+}
+
+// Migrate model specific migration on startup
+func (m *BlockHeader) Migrate(client datastore.ClientInterface) error {
+	return client.IndexMetadata(client.GetTableName(tableBlockHeaders), metadataField)
+}
+
+// blockHeaderHash will return the hash of the block header (hex, display order)
+func blockHeaderHash(header *bc.BlockHeader) string {
+	return hex.EncodeToString(bt.ReverseBytes(crypto.Sha256d(header.Bytes())))
+}
+
+// blockWork will return the proof of work of a block with the given bits: 2^256 / (target + 1)
+func blockWork(bits string) (*big.Int, error) {
+	target, err := bc.ExpandTargetFromAsInt(bits)
+	if err != nil {
+		return nil, err
+	}
+	denominator := new(big.Int).Add(target, big.NewInt(1))
+	return new(big.Int).Div(new(big.Int).Lsh(big.NewInt(1), 256), denominator), nil
+}
+
+// chainWorkToHex will encode the cumulative work as a fixed length hex string (sortable)
+func chainWorkToHex(work *big.Int) string {
+	return hex.EncodeToString(work.FillBytes(make([]byte, 32)))
+}
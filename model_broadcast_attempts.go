@@ -0,0 +1,113 @@
+package bux
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/BuxOrg/bux/chainstate"
+	"github.com/BuxOrg/bux/utils"
+	"github.com/mrz1836/go-datastore"
+)
+
+// BroadcastAttempt is an object representing the outcome of a broadcast of a transaction to a provider
+//
+// Gorm related models & indexes: https://gorm.io/docs/models.html - https://gorm.io/docs/indexes.html
+type BroadcastAttempt struct {
+	// Base model
+	Model `bson:",inline"`
+
+	// Model specific fields
+	ID              string    `json:"id" toml:"id" yaml:"id" gorm:"<-:create;type:char(64);primaryKey;comment:This is the unique broadcast attempt id" bson:"_id"`
+	TxID            string    `json:"tx_id" toml:"tx_id" yaml:"tx_id" gorm:"<-:create;type:char(64);index;comment:This is the transaction id" bson:"tx_id"`
+	Provider        string    `json:"provider" toml:"provider" yaml:"provider" gorm:"<-:create;type:varchar(64);index;comment:This is the broadcast provider" bson:"provider"`
+	StartedAt       time.Time `json:"started_at" toml:"started_at" yaml:"started_at" gorm:"<-:create;comment:When the attempt started" bson:"started_at"`
+	LatencyMs       int64     `json:"latency_ms" toml:"latency_ms" yaml:"latency_ms" gorm:"<-:create;comment:This is the latency of the attempt in milliseconds" bson:"latency_ms"`
+	Success         bool      `json:"success" toml:"success" yaml:"success" gorm:"<-:create;comment:If the provider accepted the transaction" bson:"success"`
+	ResponseCode    string    `json:"response_code" toml:"response_code" yaml:"response_code" gorm:"<-:create;type:varchar(32);comment:This is the raw response code of the provider" bson:"response_code"`
+	ResponseMessage string    `json:"response_message" toml:"response_message" yaml:"response_message" gorm:"<-:create;type:text;comment:This is the raw response message of the provider" bson:"response_message"`
+	TxStatus        string    `json:"tx_status" toml:"tx_status" yaml:"tx_status" gorm:"<-:create;type:varchar(32);comment:This is the resulting transaction status" bson:"tx_status"`
+	ErrorClass      string    `json:"error_class" toml:"error_class" yaml:"error_class" gorm:"<-:create;type:varchar(10);comment:This is the class of the error (network, rejected, timeout)" bson:"error_class"`
+	Error           string    `json:"error" toml:"error" yaml:"error" gorm:"<-:create;type:text;comment:This is the error of the attempt" bson:"error"`
+}
+
+// newBroadcastAttempt will start a new model from the broadcast attempt of the chainstate
+func newBroadcastAttempt(attempt *chainstate.BroadcastAttempt, opts ...ModelOps) *BroadcastAttempt {
+	return &BroadcastAttempt{
+		ID: utils.Hash(fmt.Sprintf(
+			"%s:%s:%d", attempt.TxID, attempt.Provider, attempt.StartedAt.UnixNano(),
+		)),
+		Model:           *NewBaseModel(ModelBroadcastAttempt, opts...),
+		TxID:            attempt.TxID,
+		Provider:        attempt.Provider,
+		StartedAt:       attempt.StartedAt,
+		LatencyMs:       attempt.Latency.Milliseconds(),
+		Success:         attempt.Success,
+		ResponseCode:    attempt.ResponseCode,
+		ResponseMessage: attempt.ResponseMessage,
+		TxStatus:        string(attempt.TxStatus),
+		ErrorClass:      attempt.ErrorClass,
+		Error:           attempt.Error,
+	}
+}
+
+// getBroadcastAttempts will get the broadcast attempts of the transaction (oldest first)
+func getBroadcastAttempts(ctx context.Context, txID string, opts ...ModelOps) ([]*BroadcastAttempt, error) {
+	modelItems := make([]*BroadcastAttempt, 0)
+	conditions := map[string]interface{}{
+		txIDField: txID,
+	}
+	queryParams := &datastore.QueryParams{
+		OrderByField:  startedAtField,
+		SortDirection: datastore.SortAsc,
+	}
+	if err := getModelsByConditions(
+		ctx, ModelBroadcastAttempt, &modelItems, nil, &conditions, queryParams, opts...,
+	); err != nil {
+		return nil, err
+	}
+
+	return modelItems, nil
+}
+
+// GetModelName will get the name of the current model
+func (m *BroadcastAttempt) GetModelName() string {
+	return ModelBroadcastAttempt.String()
+}
+
+// GetModelTableName will get the db table name of the current model
+func (m *BroadcastAttempt) GetModelTableName() string {
+	return tableBroadcastAttempts
+}
+
+// Save will save the model into the Datastore
+func (m *BroadcastAttempt) Save(ctx context.Context) error {
+	return Save(ctx, m)
+}
+
+// GetID will get the ID
+func (m *BroadcastAttempt) GetID() string {
+	return m.ID
+}
+
+// BeforeCreating will fire before the model is being inserted into the Datastore
+func (m *BroadcastAttempt) BeforeCreating(_ context.Context) error {
+	m.Client().Logger().Debug().
+		Str("broadcastAttemptID", m.ID).
+		Msgf("starting: %s BeforeCreating hook...", m.Name())
+
+	// Make sure ID is valid
+	if len(m.ID) == 0 {
+		return ErrMissingFieldID
+	}
+
+	m.Client().Logger().Debug().
+		Str("broadcastAttemptID", m.ID).
+		Msgf("end: %s BeforeCreating hook", m.Name())
+	return nil
+}
+
+// Migrate model specific migration on startup
+func (m *BroadcastAttempt) Migrate(client datastore.ClientInterface) error {
+	return client.IndexMetadata(client.GetTableName(tableBroadcastAttempts), metadataField)
+}
@@ -0,0 +1,122 @@
+package bux
+
+import (
+	"context"
+	"errors"
+
+	"github.com/BuxOrg/bux/utils"
+	"github.com/bitcoin-sv/go-broadcast-client/broadcast"
+	"github.com/mrz1836/go-datastore"
+)
+
+// Reasons for recording a broadcast callback
+const (
+	BroadcastCallbackInvalid = "invalid" // The merkle path could not be verified
+	BroadcastCallbackLate    = "late"    // The transaction was already synced on-chain (in another block)
+	BroadcastCallbackUnknown = "unknown" // The transaction is not found
+)
+
+// BroadcastCallback is an object representing a broadcast (ARC) callback which could not be applied
+//
+// The callbacks are recorded for inspection: unknown transactions, late callbacks and invalid merkle paths
+//
+// Gorm related models & indexes: https://gorm.io/docs/models.html - https://gorm.io/docs/indexes.html
+type BroadcastCallback struct {
+	// Base model
+	Model `bson:",inline"`
+
+	// Model specific fields
+	ID          string `json:"id" toml:"id" yaml:"id" gorm:"<-:create;type:char(64);primaryKey;comment:This is the unique callback id" bson:"_id"`
+	TxID        string `json:"tx_id" toml:"tx_id" yaml:"tx_id" gorm:"<-:create;type:char(64);index;comment:This is the transaction id" bson:"tx_id"`
+	TxStatus    string `json:"tx_status" toml:"tx_status" yaml:"tx_status" gorm:"<-:create;type:varchar(32);comment:This is the transaction status" bson:"tx_status"`
+	BlockHash   string `json:"block_hash" toml:"block_hash" yaml:"block_hash" gorm:"<-:create;type:char(64);comment:This is the block hash" bson:"block_hash"`
+	BlockHeight uint64 `json:"block_height" toml:"block_height" yaml:"block_height" gorm:"<-:create;type:bigint;comment:This is the block height" bson:"block_height"`
+	MerklePath  string `json:"merkle_path" toml:"merkle_path" yaml:"merkle_path" gorm:"<-:create;type:text;comment:This is the merkle path (BUMP hex)" bson:"merkle_path"`
+	Reason      string `json:"reason" toml:"reason" yaml:"reason" gorm:"<-:create;type:varchar(10);index;comment:This is the reason of the recording" bson:"reason"`
+	Message     string `json:"message" toml:"message" yaml:"message" gorm:"<-:create;type:text;comment:This is the detail of the reason" bson:"message"`
+}
+
+// newBroadcastCallback will start a new model from the callback
+//
+// The id is the hash of the callback content, the same callback is recorded only once
+func newBroadcastCallback(callback *broadcast.SubmittedTx, reason, message string, opts ...ModelOps) *BroadcastCallback {
+	return &BroadcastCallback{
+		ID:          utils.Hash(callback.TxID + string(callback.TxStatus) + callback.BlockHash + callback.MerklePath),
+		Model:       *NewBaseModel(ModelBroadcastCallback, opts...),
+		TxID:        callback.TxID,
+		TxStatus:    string(callback.TxStatus),
+		BlockHash:   callback.BlockHash,
+		BlockHeight: uint64(callback.BlockHeight),
+		MerklePath:  callback.MerklePath,
+		Reason:      reason,
+		Message:     message,
+	}
+}
+
+// getBroadcastCallback will get the recorded broadcast callback with the given id
+func getBroadcastCallback(ctx context.Context, id string, opts ...ModelOps) (*BroadcastCallback, error) {
+	callback := &BroadcastCallback{ID: id}
+	callback.enrich(ModelBroadcastCallback, opts...)
+
+	if err := Get(ctx, callback, nil, false, defaultDatabaseReadTimeout, false); err != nil {
+		if errors.Is(err, datastore.ErrNoResults) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return callback, nil
+}
+
+// getBroadcastCallbacks will get all the recorded broadcast callbacks with the given conditions
+func getBroadcastCallbacks(ctx context.Context, conditions *map[string]interface{},
+	queryParams *datastore.QueryParams, opts ...ModelOps,
+) ([]*BroadcastCallback, error) {
+	modelItems := make([]*BroadcastCallback, 0)
+	if err := getModelsByConditions(ctx, ModelBroadcastCallback, &modelItems, nil, conditions, queryParams, opts...); err != nil {
+		return nil, err
+	}
+
+	return modelItems, nil
+}
+
+// GetModelName will get the name of the current model
+func (m *BroadcastCallback) GetModelName() string {
+	return ModelBroadcastCallback.String()
+}
+
+// GetModelTableName will get the db table name of the current model
+func (m *BroadcastCallback) GetModelTableName() string {
+	return tableBroadcastCallbacks
+}
+
+// Save will save the model into the Datastore
+func (m *BroadcastCallback) Save(ctx context.Context) error {
+	return Save(ctx, m)
+}
+
+// GetID will get the ID
+func (m *BroadcastCallback) GetID() string {
+	return m.ID
+}
+
+// BeforeCreating will fire before the model is being inserted into the Datastore
+func (m *BroadcastCallback) BeforeCreating(_ context.Context) error {
+	m.Client().Logger().Debug().
+		Str("broadcastCallbackID", m.ID).
+		Msgf("starting: %s BeforeCreating hook...", m.Name())
+
+	// Make sure ID is valid
+	if len(m.ID) == 0 {
+		return ErrMissingFieldID
+	}
+
+	m.Client().Logger().Debug().
+		Str("broadcastCallbackID", m.ID).
+		Msgf("end: %s BeforeCreating hook", m.Name())
+	return nil
+}
+
+// Migrate model specific migration on startup
+func (m *BroadcastCallback) Migrate(client datastore.ClientInterface) error {
+	return client.IndexMetadata(client.GetTableName(tableBroadcastCallbacks), metadataField)
+}
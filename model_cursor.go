@@ -0,0 +1,242 @@
+package bux
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/go-datastore"
+)
+
+// CursorQueryParams are the params for cursor (keyset) pagination
+//
+// Cursor is the opaque next cursor returned by the previous page (empty for the first page)
+type CursorQueryParams struct {
+	Cursor   string `json:"cursor,omitempty"`
+	PageSize int    `json:"page_size,omitempty"`
+}
+
+// cursorModel is a model that can be paginated using a cursor (created_at + id)
+type cursorModel interface {
+	GetID() string
+	getCreatedAt() time.Time
+}
+
+// modelCursor is the decoded position of the last record of a page
+type modelCursor struct {
+	createdAt time.Time
+	id        string
+}
+
+// getCreatedAt will return the created at time of the model
+func (m *Model) getCreatedAt() time.Time {
+	return m.CreatedAt
+}
+
+// encodeCursor will encode the position of the given model into an opaque cursor
+func encodeCursor(model cursorModel) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(
+		strconv.FormatInt(model.getCreatedAt().UnixNano(), 10) + ":" + model.GetID(),
+	))
+}
+
+// decodeCursor will decode an opaque cursor
+func decodeCursor(cursor string) (*modelCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 || len(parts[1]) == 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	var nanos int64
+	if nanos, err = strconv.ParseInt(parts[0], 10, 64); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &modelCursor{
+		createdAt: time.Unix(0, nanos).UTC(),
+		id:        parts[1],
+	}, nil
+}
+
+// getModelsByCursor will get a page of models ordered by (created_at, id) starting after the given cursor
+//
+// Only single field ordering is available in the datastore, so records sharing the same created_at
+// at the end of a page are never split: the page is cut before them, or the group is read ordered by id.
+// Returns the models and the next cursor (empty if there are no more records)
+func getModelsByCursor[M cursorModel](ctx context.Context, modelName ModelName,
+	metadata *Metadata, conditions *map[string]interface{}, params *CursorQueryParams,
+	opts ...ModelOps,
+) ([]M, string, error) {
+	pageSize := defaultPageSize
+	var cursor *modelCursor
+	if params != nil {
+		if params.PageSize > 0 {
+			pageSize = params.PageSize
+		}
+		if params.Cursor != "" {
+			var err error
+			if cursor, err = decodeCursor(params.Cursor); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+
+	// getPage will get the models matching the conditions and the keyset condition
+	//
+	// the keyset and the conditions stay at the top level when possible, Mongo only keeps the time values there
+	getPage := func(keyset map[string]interface{}, orderBy string, limit int) ([]M, error) {
+		dbConditions := map[string]interface{}{}
+		for key, condition := range keyset {
+			dbConditions[key] = condition
+		}
+		if metadata != nil {
+			dbConditions[metadataField] = metadata
+		}
+		if conditions != nil {
+			mergeConditions(dbConditions, *conditions)
+		}
+
+		models := make([]M, 0)
+		if err := getModels(
+			ctx, NewBaseModel(modelName, opts...).Client().Datastore(),
+			&models, dbConditions, &datastore.QueryParams{
+				Page:          1,
+				PageSize:      limit,
+				OrderByField:  orderBy,
+				SortDirection: datastore.SortAsc,
+			}, defaultDatabaseReadTimeout,
+		); err != nil && !errors.Is(err, datastore.ErrNoResults) {
+			return nil, err
+		}
+		return models, nil
+	}
+
+	// Rest of the records sharing the created_at of the cursor
+	models := make([]M, 0)
+	hasMore := false
+	if cursor != nil {
+		var err error
+		if models, err = getPage(map[string]interface{}{
+			createdAtField: cursor.createdAt,
+			idField:        map[string]interface{}{conditionGreaterThan: cursor.id},
+		}, idField, pageSize); err != nil {
+			return nil, "", err
+		}
+		hasMore = len(models) == pageSize
+	}
+
+	// Records created after the cursor
+	if need := pageSize - len(models); need > 0 {
+		keyset := map[string]interface{}{}
+		if cursor != nil {
+			keyset[createdAtField] = map[string]interface{}{conditionGreaterThan: cursor.createdAt}
+		}
+
+		// get one extra record to know if the last created_at group was cut
+		next, err := getPage(keyset, createdAtField, need+1)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if len(next) > need {
+			hasMore = true
+			last := next[need-1].getCreatedAt()
+			groupCut := next[need].getCreatedAt().Equal(last)
+			next = next[:need]
+
+			// drop the cut trailing group, it will be read by the next page
+			if groupCut {
+				cut := len(next)
+				for cut > 0 && next[cut-1].getCreatedAt().Equal(last) {
+					cut--
+				}
+				next = next[:cut]
+
+				// the whole page shares the same created_at, read the group ordered by id
+				if cut == 0 && len(models) == 0 {
+					if next, err = getPage(map[string]interface{}{
+						createdAtField: last,
+					}, idField, pageSize); err != nil {
+						return nil, "", err
+					}
+				}
+			}
+		}
+		models = append(models, next...)
+	}
+
+	// Order the records by the keyset (created_at, id)
+	sort.SliceStable(models, func(i, j int) bool {
+		if !models[i].getCreatedAt().Equal(models[j].getCreatedAt()) {
+			return models[i].getCreatedAt().Before(models[j].getCreatedAt())
+		}
+		return models[i].GetID() < models[j].GetID()
+	})
+
+	if !hasMore || len(models) == 0 {
+		return models, "", nil
+	}
+	return models, encodeCursor(models[len(models)-1]), nil
+}
+
+// iterateModels will stream all the models matching the conditions to the given function,
+// reading them in batches (ordered by created_at, id) to keep the memory bounded
+//
+// The batch size can be set using WithPageSize(), the iteration stops on the first error
+func iterateModels[M cursorModel](ctx context.Context, modelName ModelName,
+	conditions *map[string]interface{}, fn func(M) error, opts ...ModelOps,
+) error {
+	return iterateModelBatches[M](ctx, modelName, conditions, func(models []M) error {
+		for _, model := range models {
+			if err := fn(model); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, opts...)
+}
+
+// iterateModelBatches will stream all the models matching the conditions to the given function, batch by batch
+//
+// Use it to load the related data once per batch, see iterateModels()
+func iterateModelBatches[M cursorModel](ctx context.Context, modelName ModelName,
+	conditions *map[string]interface{}, fn func([]M) error, opts ...ModelOps,
+) error {
+	params := &CursorQueryParams{
+		PageSize: NewBaseModel(modelName, opts...).pageSize,
+	}
+	if params.PageSize == 0 {
+		params.PageSize = defaultIterateBatchSize
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		models, nextCursor, err := getModelsByCursor[M](ctx, modelName, nil, conditions, params, opts...)
+		if err != nil {
+			return err
+		}
+
+		if len(models) > 0 {
+			if err = fn(models); err != nil {
+				return err
+			}
+		}
+
+		if nextCursor == "" {
+			return nil
+		}
+		params.Cursor = nextCursor
+	}
+}
@@ -0,0 +1,156 @@
+package bux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCursor_EncodeDecode will test the methods encodeCursor() and decodeCursor()
+func TestCursor_EncodeDecode(t *testing.T) {
+	t.Run("valid cursor", func(t *testing.T) {
+		utxo := newUtxo(testXPubID, testTxID, testLockingScript, 12, 1225)
+		utxo.CreatedAt = time.Date(2023, 12, 1, 10, 11, 12, 13, time.UTC)
+
+		cursor, err := decodeCursor(encodeCursor(utxo))
+		require.NoError(t, err)
+		assert.Equal(t, utxo.ID, cursor.id)
+		assert.True(t, utxo.CreatedAt.Equal(cursor.createdAt))
+	})
+
+	t.Run("invalid cursors", func(t *testing.T) {
+		for _, cursor := range []string{"!!!", "MTIz", "YWJjOmlk", "MTIzOg"} {
+			_, err := decodeCursor(cursor)
+			assert.ErrorIs(t, err, ErrInvalidCursor, cursor)
+		}
+	})
+}
+
+// TestClient_GetUtxosWithCursor will test the method GetUtxosWithCursor()
+func TestClient_GetUtxosWithCursor(t *testing.T) {
+	t.Run("walk all pages", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup())
+		defer deferMe()
+
+		// 3 utxos sharing the same created_at to test groups cut by the page size
+		created := time.Now().UTC().Add(-time.Hour).Truncate(time.Millisecond)
+		createdAt := []time.Time{
+			created, created.Add(time.Second), created.Add(time.Second), created.Add(time.Second),
+			created.Add(2 * time.Second), created.Add(3 * time.Second), created.Add(3 * time.Second),
+		}
+		expected := make(map[string]bool)
+		for i, at := range createdAt {
+			utxo := newUtxo(testXPubID, testTxID, testLockingScript, uint32(i), 1225, append(client.DefaultModelOptions(), New())...)
+			utxo.CreatedAt = at
+			require.NoError(t, utxo.Save(ctx))
+			expected[utxo.ID] = true
+		}
+
+		for _, pageSize := range []int{1, 2, 3, 10} {
+			seen := make(map[string]bool)
+			var last *Utxo
+			cursor := ""
+			for pages := 0; pages < 20; pages++ {
+				utxos, next, err := client.GetUtxosWithCursor(ctx, nil, nil, &CursorQueryParams{
+					Cursor:   cursor,
+					PageSize: pageSize,
+				})
+				require.NoError(t, err)
+				assert.LessOrEqual(t, len(utxos), pageSize)
+				for _, utxo := range utxos {
+					assert.False(t, seen[utxo.ID], "duplicate utxo")
+					seen[utxo.ID] = true
+					if last != nil {
+						assert.False(t, utxo.CreatedAt.Before(last.CreatedAt))
+					}
+					last = utxo
+				}
+				if next == "" {
+					break
+				}
+				cursor = next
+			}
+			assert.Equal(t, expected, seen, "page size %d", pageSize)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup())
+		defer deferMe()
+
+		_, _, err := client.GetUtxosWithCursor(ctx, nil, nil, &CursorQueryParams{Cursor: "!!!"})
+		require.ErrorIs(t, err, ErrInvalidCursor)
+	})
+
+	t.Run("with conditions", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup())
+		defer deferMe()
+		require.NoError(t, createTestUtxos(ctx, client))
+
+		utxos, next, err := client.GetUtxosWithCursor(ctx, nil, &map[string]interface{}{
+			xPubIDField: testXPubID,
+		}, &CursorQueryParams{PageSize: 10})
+		require.NoError(t, err)
+		assert.Len(t, utxos, 5)
+		assert.Equal(t, "", next)
+
+		utxos, _, err = client.GetUtxosWithCursor(ctx, nil, &map[string]interface{}{
+			xPubIDField: "unknown",
+		}, nil)
+		require.NoError(t, err)
+		assert.Len(t, utxos, 0)
+	})
+}
+
+// TestClient_IterateUtxos will test the method IterateUtxos()
+func TestClient_IterateUtxos(t *testing.T) {
+	t.Run("iterate in batches", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup())
+		defer deferMe()
+		require.NoError(t, createTestUtxos(ctx, client))
+
+		seen := make(map[string]bool)
+		err := client.IterateUtxos(ctx, &map[string]interface{}{
+			xPubIDField: testXPubID,
+		}, func(utxo *Utxo) error {
+			seen[utxo.ID] = true
+			return nil
+		}, WithPageSize(2))
+		require.NoError(t, err)
+		assert.Len(t, seen, 5)
+	})
+
+	t.Run("stop on error", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup())
+		defer deferMe()
+		require.NoError(t, createTestUtxos(ctx, client))
+
+		count := 0
+		err := client.IterateUtxos(ctx, nil, func(utxo *Utxo) error {
+			count++
+			if count == 3 {
+				return ErrMissingUtxo
+			}
+			return nil
+		}, WithPageSize(2))
+		require.ErrorIs(t, err, ErrMissingUtxo)
+		assert.Equal(t, 3, count)
+	})
+}
+
+// TestClient_IterateTransactions will test the method IterateTransactions()
+func TestClient_IterateTransactions(t *testing.T) {
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup())
+	defer deferMe()
+	createTestUnconfirmedChain(ctx, t, client)
+
+	txIDs := make([]string, 0)
+	err := client.IterateTransactions(ctx, nil, func(tx *Transaction) error {
+		txIDs = append(txIDs, tx.ID)
+		return nil
+	}, WithPageSize(1))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{testTxID, testTxID2}, txIDs)
+}
@@ -0,0 +1,103 @@
+package bux
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/BuxOrg/bux/chainstate"
+	"github.com/BuxOrg/bux/utils"
+	"github.com/mrz1836/go-datastore"
+)
+
+// FeeHistory is an object representing the fee unit selected by a fee quotes refresh
+//
+// Gorm related models & indexes: https://gorm.io/docs/models.html - https://gorm.io/docs/indexes.html
+type FeeHistory struct {
+	// Base model
+	Model `bson:",inline"`
+
+	// Model specific fields
+	ID               string `json:"id" toml:"id" yaml:"id" gorm:"<-:create;type:char(64);primaryKey;comment:This is the unique fee history id" bson:"_id"`
+	Satoshis         int    `json:"satoshis" toml:"satoshis" yaml:"satoshis" gorm:"<-:create;comment:This is the satoshis of the fee unit" bson:"satoshis"`
+	Bytes            int    `json:"bytes" toml:"bytes" yaml:"bytes" gorm:"<-:create;comment:This is the bytes of the fee unit" bson:"bytes"`
+	PreviousSatoshis int    `json:"previous_satoshis" toml:"previous_satoshis" yaml:"previous_satoshis" gorm:"<-:create;comment:This is the satoshis of the previous fee unit" bson:"previous_satoshis"`
+	PreviousBytes    int    `json:"previous_bytes" toml:"previous_bytes" yaml:"previous_bytes" gorm:"<-:create;comment:This is the bytes of the previous fee unit" bson:"previous_bytes"`
+	Changed          bool   `json:"changed" toml:"changed" yaml:"changed" gorm:"<-:create;index;comment:If the fee unit changed" bson:"changed"`
+	Provider         string `json:"provider" toml:"provider" yaml:"provider" gorm:"<-:create;type:varchar(64);comment:This is the provider of the fee quotes" bson:"provider"`
+	Quotes           int    `json:"quotes" toml:"quotes" yaml:"quotes" gorm:"<-:create;comment:This is the number of fee quotes" bson:"quotes"`
+}
+
+// newFeeHistory will start a new model from the fee unit refresh
+func newFeeHistory(refresh *chainstate.FeeUnitRefresh, opts ...ModelOps) *FeeHistory {
+	history := &FeeHistory{
+		Model:    *NewBaseModel(ModelFeeHistory, opts...),
+		Satoshis: refresh.FeeUnit.Satoshis,
+		Bytes:    refresh.FeeUnit.Bytes,
+		Changed:  refresh.Changed,
+		Provider: refresh.Provider,
+		Quotes:   len(refresh.Quotes),
+	}
+	if refresh.Previous != nil {
+		history.PreviousSatoshis = refresh.Previous.Satoshis
+		history.PreviousBytes = refresh.Previous.Bytes
+	}
+	history.ID = utils.Hash(fmt.Sprintf(
+		"%s:%d:%d:%d", history.Provider, history.Satoshis, history.Bytes, time.Now().UnixNano(),
+	))
+	return history
+}
+
+// getFeeHistory will get the fee history with the given conditions
+func getFeeHistory(ctx context.Context, conditions *map[string]interface{},
+	queryParams *datastore.QueryParams, opts ...ModelOps,
+) ([]*FeeHistory, error) {
+	modelItems := make([]*FeeHistory, 0)
+	if err := getModelsByConditions(ctx, ModelFeeHistory, &modelItems, nil, conditions, queryParams, opts...); err != nil {
+		return nil, err
+	}
+
+	return modelItems, nil
+}
+
+// GetModelName will get the name of the current model
+func (m *FeeHistory) GetModelName() string {
+	return ModelFeeHistory.String()
+}
+
+// GetModelTableName will get the db table name of the current model
+func (m *FeeHistory) GetModelTableName() string {
+	return tableFeeHistory
+}
+
+// Save will save the model into the Datastore
+func (m *FeeHistory) Save(ctx context.Context) error {
+	return Save(ctx, m)
+}
+
+// GetID will get the ID
+func (m *FeeHistory) GetID() string {
+	return m.ID
+}
+
+// BeforeCreating will fire before the model is being inserted into the Datastore
+func (m *FeeHistory) BeforeCreating(_ context.Context) error {
+	m.Client().Logger().Debug().
+		Str("feeHistoryID", m.ID).
+		Msgf("starting: %s BeforeCreating hook...", m.Name())
+
+	// Make sure ID is valid
+	if len(m.ID) == 0 {
+		return ErrMissingFieldID
+	}
+
+	m.Client().Logger().Debug().
+		Str("feeHistoryID", m.ID).
+		Msgf("end: %s BeforeCreating hook", m.Name())
+	return nil
+}
+
+// Migrate model specific migration on startup
+func (m *FeeHistory) Migrate(client datastore.ClientInterface) error {
+	return client.IndexMetadata(client.GetTableName(tableFeeHistory), metadataField)
+}
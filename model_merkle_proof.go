@@ -0,0 +1,124 @@
+package bux
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/BuxOrg/bux/utils"
+	"github.com/libsv/go-bc"
+	"github.com/libsv/go-bk/crypto"
+	"github.com/libsv/go-bt/v2"
+)
+
+// Legacy TSC merkle proof target types
+const (
+	merkleProofTargetHash       = "hash"
+	merkleProofTargetHeader     = "header"
+	merkleProofTargetMerkleRoot = "merkleRoot"
+)
+
+// MerkleProof represents the legacy TSC merkle proof (JSON format) stored by older versions, superseded by BUMP
+//
+// https://tsc.bitcoinassociation.net/standards/merkle-proof-standardised-format/
+type MerkleProof struct {
+	Index         uint64   `json:"index"`
+	TxOrID        string   `json:"txOrId"`
+	Target        string   `json:"target"`
+	Nodes         []string `json:"nodes"`
+	TargetType    string   `json:"targetType,omitempty"`
+	ProofType     string   `json:"proofType,omitempty"`
+	ComposedProof bool     `json:"composedProof,omitempty"`
+}
+
+// MerkleProofMigration is the result of the migration of the legacy merkle proofs to BUMP
+type MerkleProofMigration struct {
+	Converted int `json:"converted"` // Transactions with a BUMP converted from the legacy merkle proof
+	Moved     int `json:"moved"`     // Transactions with an inline BUMP moved to the block BUMP
+	Queried   int `json:"queried"`   // Transactions with a BUMP queried again through chainstate
+	Failed    int `json:"failed"`    // Transactions still without a BUMP
+}
+
+// ToBUMP will convert the merkle proof into the BUMP of the transaction at the given block height
+//
+// Only branch (not composite) proofs can be converted, when the target is the merkle root or the
+// block header, the merkle root calculated from the BUMP must match it
+func (m *MerkleProof) ToBUMP(blockHeight uint64) (BUMP, error) {
+	if m.ComposedProof || (m.ProofType != "" && m.ProofType != "branch") {
+		return BUMP{}, errors.New("only branch merkle proofs can be converted to BUMP")
+	} else if len(m.Nodes) == 0 {
+		return BUMP{}, errors.New("merkle proof has no nodes")
+	}
+
+	// the proof can contain the full transaction instead of the txid
+	txID := m.TxOrID
+	if len(txID) > 64 {
+		rawTx, err := hex.DecodeString(txID)
+		if err != nil {
+			return BUMP{}, err
+		}
+		txID = hex.EncodeToString(bt.ReverseBytes(crypto.Sha256d(rawTx)))
+	}
+
+	bump := merkleProofToBUMP(&bc.MerkleProof{
+		Index:  m.Index,
+		TxOrID: txID,
+		Target: m.Target,
+		Nodes:  m.Nodes,
+	}, blockHeight)
+
+	merkleRoot, err := bump.calculateMerkleRoot()
+	if err != nil {
+		return BUMP{}, err
+	}
+
+	switch m.TargetType {
+	case "", merkleProofTargetHash:
+		// the block hash cannot be checked against the BUMP
+	case merkleProofTargetMerkleRoot:
+		if merkleRoot != m.Target {
+			return BUMP{}, fmt.Errorf("merkle root %s does not match the merkle proof target", merkleRoot)
+		}
+	case merkleProofTargetHeader:
+		var headerMerkleRoot string
+		if headerMerkleRoot, err = bc.ExtractMerkleRootFromBlockHeader(m.Target); err != nil {
+			return BUMP{}, err
+		} else if merkleRoot != headerMerkleRoot {
+			return BUMP{}, fmt.Errorf("merkle root %s does not match the merkle proof block header", merkleRoot)
+		}
+	default:
+		return BUMP{}, fmt.Errorf("unknown merkle proof target type: %s", m.TargetType)
+	}
+
+	return bump, nil
+}
+
+// Scan scan value into Json, implements sql.Scanner interface
+func (m *MerkleProof) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	byteValue, err := utils.ToByteArray(value)
+	if err != nil || bytes.Equal(byteValue, []byte("")) || bytes.Equal(byteValue, []byte("\"\"")) {
+		return nil
+	}
+
+	return json.Unmarshal(byteValue, &m)
+}
+
+// Value return json value, implement driver.Valuer interface
+func (m MerkleProof) Value() (driver.Value, error) {
+	if m.TxOrID == "" && len(m.Nodes) == 0 {
+		return nil, nil
+	}
+	marshal, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(marshal), nil
+}
@@ -0,0 +1,257 @@
+package bux
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/BuxOrg/bux/chainstate"
+	"github.com/BuxOrg/bux/utils"
+	"github.com/libsv/go-bc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chainStateQueryTimeout is a chainstate where all the transaction queries time out
+type chainStateQueryTimeout struct {
+	chainStateEverythingOnChain
+}
+
+func (c *chainStateQueryTimeout) QueryTransaction(context.Context, string,
+	chainstate.RequiredIn, time.Duration,
+) (*chainstate.TransactionInfo, error) {
+	return nil, context.DeadlineExceeded
+}
+
+// createTestMerkleProof will create the legacy merkle proof of the first transaction of a block with 4 transactions
+func createTestMerkleProof(t *testing.T, txID string) (*MerkleProof, string) {
+	hashes := make([]string, 3)
+	for i := range hashes {
+		var err error
+		hashes[i], err = utils.RandomHex(32)
+		require.NoError(t, err)
+	}
+
+	left, err := bc.MerkleTreeParentStr(txID, hashes[0])
+	require.NoError(t, err)
+	right, err := bc.MerkleTreeParentStr(hashes[1], hashes[2])
+	require.NoError(t, err)
+	merkleRoot, err := bc.MerkleTreeParentStr(left, right)
+	require.NoError(t, err)
+
+	return &MerkleProof{
+		Index:      0,
+		TxOrID:     txID,
+		Target:     merkleRoot,
+		TargetType: merkleProofTargetMerkleRoot,
+		Nodes:      []string{hashes[0], right},
+	}, merkleRoot
+}
+
+// TestMerkleProof_ToBUMP will test the method ToBUMP()
+func TestMerkleProof_ToBUMP(t *testing.T) {
+	t.Parallel()
+
+	txID, err := utils.RandomHex(32)
+	require.NoError(t, err)
+
+	t.Run("merkle root target", func(t *testing.T) {
+		mp, merkleRoot := createTestMerkleProof(t, txID)
+
+		bump, err := mp.ToBUMP(800000)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(800000), bump.BlockHeight)
+		require.Len(t, bump.Path, 2)
+		assert.Equal(t, BUMPLeaf{Offset: 0, Hash: txID, TxID: true}, bump.Path[0][0])
+
+		var calculated string
+		calculated, err = bump.calculateMerkleRoot()
+		require.NoError(t, err)
+		assert.Equal(t, merkleRoot, calculated)
+	})
+
+	t.Run("block header target", func(t *testing.T) {
+		mp, merkleRoot := createTestMerkleProof(t, txID)
+		rootBytes, err := hex.DecodeString(merkleRoot)
+		require.NoError(t, err)
+		header := &bc.BlockHeader{
+			HashPrevBlock:  make([]byte, 32),
+			HashMerkleRoot: rootBytes,
+			Bits:           []byte{0x20, 0x7f, 0xff, 0xff},
+		}
+		mp.Target = header.String()
+		mp.TargetType = merkleProofTargetHeader
+
+		_, err = mp.ToBUMP(800000)
+		require.NoError(t, err)
+	})
+
+	t.Run("block hash target", func(t *testing.T) {
+		mp, _ := createTestMerkleProof(t, txID)
+		mp.Target, _ = utils.RandomHex(32)
+		mp.TargetType = ""
+
+		_, err := mp.ToBUMP(800000)
+		require.NoError(t, err)
+	})
+
+	t.Run("target does not match", func(t *testing.T) {
+		mp, _ := createTestMerkleProof(t, txID)
+		mp.Target, _ = utils.RandomHex(32)
+
+		_, err := mp.ToBUMP(800000)
+		require.Error(t, err)
+	})
+
+	t.Run("composite proof", func(t *testing.T) {
+		mp, _ := createTestMerkleProof(t, txID)
+		mp.ComposedProof = true
+
+		_, err := mp.ToBUMP(800000)
+		require.Error(t, err)
+	})
+
+	t.Run("no nodes", func(t *testing.T) {
+		_, err := (&MerkleProof{TxOrID: txID}).ToBUMP(800000)
+		require.Error(t, err)
+	})
+}
+
+// TestClient_MigrateLegacyMerkleProofs will test the method MigrateLegacyMerkleProofs()
+func TestClient_MigrateLegacyMerkleProofs(t *testing.T) {
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+		WithCustomChainstate(&chainStateEverythingOnChain{}))
+	defer deferMe()
+
+	// mined transactions without BUMP
+	_, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+	for _, btTx := range txs {
+		tx, err := txFromHex(btTx.String(), append(client.DefaultModelOptions(), New())...)
+		require.NoError(t, err)
+		tx.BlockHeight = 800000
+		tx.BlockHash, _ = utils.RandomHex(32)
+		require.NoError(t, tx.Save(ctx))
+	}
+
+	// the first one has a legacy merkle proof (column of older versions)
+	tableName := client.Datastore().GetTableName(tableTransactions)
+	require.NoError(t, client.Datastore().Execute("ALTER TABLE "+tableName+" ADD COLUMN "+merkleProofField+" text").Error)
+	mp, merkleRoot := createTestMerkleProof(t, txs[0].TxID())
+	mpJSON, err := json.Marshal(mp)
+	require.NoError(t, err)
+	require.NoError(t, client.Datastore().Execute(
+		"UPDATE "+tableName+" SET "+merkleProofField+" = '"+string(mpJSON)+"' WHERE id = '"+txs[0].TxID()+"'",
+	).Error)
+
+	var result *MerkleProofMigration
+	result, err = client.MigrateLegacyMerkleProofs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &MerkleProofMigration{Converted: 1, Queried: 1}, result)
+
+	var tx *Transaction
+	tx, err = client.GetTransaction(ctx, "", txs[0].TxID())
+	require.NoError(t, err)
+	assert.Equal(t, merkleRoot, tx.BlockBUMPID)
+	assert.Equal(t, uint64(800000), tx.BUMP.BlockHeight)
+
+	tx, err = client.GetTransaction(ctx, "", txs[1].TxID())
+	require.NoError(t, err)
+	assert.NotEmpty(t, tx.BUMP.Path)
+
+	// nothing left to migrate
+	result, err = client.MigrateLegacyMerkleProofs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &MerkleProofMigration{}, result)
+}
+
+// TestClient_MigrateLegacyMerkleProofs_unresolved will test the method MigrateLegacyMerkleProofs()
+// with transactions which cannot be resolved
+func TestClient_MigrateLegacyMerkleProofs_unresolved(t *testing.T) {
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(), WithSPVOnly())
+	defer deferMe()
+
+	_, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+	tx, err := txFromHex(txs[0].String(), append(client.DefaultModelOptions(), New())...)
+	require.NoError(t, err)
+	tx.BlockHeight = 800000
+	require.NoError(t, tx.Save(ctx))
+
+	var result *MerkleProofMigration
+	result, err = client.MigrateLegacyMerkleProofs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &MerkleProofMigration{Failed: 1}, result)
+
+	tx, err = client.GetTransaction(ctx, "", txs[0].TxID())
+	require.NoError(t, err)
+	assert.Equal(t, blockBUMPIDUnresolved, tx.BlockBUMPID)
+	assert.Empty(t, tx.BUMP.Path)
+
+	// the unresolved transaction is not tried again
+	result, err = client.MigrateLegacyMerkleProofs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &MerkleProofMigration{}, result)
+}
+
+// TestClient_MigrateLegacyMerkleProofs_moved will test the method MigrateLegacyMerkleProofs()
+// with a transaction which has its BUMP stored inline
+func TestClient_MigrateLegacyMerkleProofs_moved(t *testing.T) {
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+		WithCustomChainstate(&chainStateEverythingOnChain{}))
+	defer deferMe()
+
+	bumps, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+	tx, err := txFromHex(txs[0].String(), append(client.DefaultModelOptions(), New())...)
+	require.NoError(t, err)
+	tx.BlockHeight = 800000
+	require.NoError(t, tx.Save(ctx))
+
+	// the BUMP is stored on the transaction (older versions)
+	bumpJSON, err := json.Marshal(bumps[0])
+	require.NoError(t, err)
+	require.NoError(t, client.Datastore().Execute(
+		"UPDATE "+client.Datastore().GetTableName(tableTransactions)+" SET "+bumpField+" = '"+string(bumpJSON)+
+			"' WHERE id = '"+txs[0].TxID()+"'",
+	).Error)
+
+	var result *MerkleProofMigration
+	result, err = client.MigrateLegacyMerkleProofs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &MerkleProofMigration{Moved: 1}, result)
+
+	merkleRoot, err := bumps[0].calculateMerkleRoot()
+	require.NoError(t, err)
+	tx, err = client.GetTransaction(ctx, "", txs[0].TxID())
+	require.NoError(t, err)
+	assert.Equal(t, merkleRoot, tx.BlockBUMPID)
+	assert.Equal(t, *bumps[0], tx.BUMP)
+}
+
+// TestClient_MigrateLegacyMerkleProofs_queryFailed will test the method MigrateLegacyMerkleProofs()
+// with a transaction which cannot be queried for now
+func TestClient_MigrateLegacyMerkleProofs_queryFailed(t *testing.T) {
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+		WithCustomChainstate(&chainStateQueryTimeout{}))
+	defer deferMe()
+
+	_, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+	tx, err := txFromHex(txs[0].String(), append(client.DefaultModelOptions(), New())...)
+	require.NoError(t, err)
+	tx.BlockHeight = 800000
+	require.NoError(t, tx.Save(ctx))
+
+	var result *MerkleProofMigration
+	result, err = client.MigrateLegacyMerkleProofs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &MerkleProofMigration{Failed: 1}, result)
+
+	tx, err = client.GetTransaction(ctx, "", txs[0].TxID())
+	require.NoError(t, err)
+	assert.Empty(t, tx.BlockBUMPID)
+
+	// the transaction is tried again by the next run
+	result, err = client.MigrateLegacyMerkleProofs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &MerkleProofMigration{Failed: 1}, result)
+}
@@ -4,22 +4,21 @@ import (
 	"bytes"
 	"database/sql/driver"
 	"encoding/json"
+	"time"
 
 	"github.com/BuxOrg/bux/utils"
 )
 
 // SyncConfig is the configuration used for syncing a transaction (on-chain)
 type SyncConfig struct {
-	Broadcast        bool `json:"broadcast" toml:"broadcast" yaml:"broadcast"`                         // Transaction should be broadcasted
-	BroadcastInstant bool `json:"broadcast_instant" toml:"broadcast_instant" yaml:"broadcast_instant"` // Transaction should be broadcasted instantly (ASAP)
-	PaymailP2P       bool `json:"paymail_p2p" toml:"paymail_p2p" yaml:"paymail_p2p"`                   // Transaction will be sent to all related paymail providers if P2P is detected
-	SyncOnChain      bool `json:"sync_on_chain" toml:"sync_on_chain" yaml:"sync_on_chain"`             // Transaction should be checked that it's on-chain
+	Broadcast        bool          `json:"broadcast" toml:"broadcast" yaml:"broadcast"`                            // Transaction should be broadcasted
+	BroadcastInstant bool          `json:"broadcast_instant" toml:"broadcast_instant" yaml:"broadcast_instant"`    // Transaction should be broadcasted instantly (ASAP)
+	DelayToBroadcast time.Duration `json:"delay_to_broadcast" toml:"delay_to_broadcast" yaml:"delay_to_broadcast"` // Delay for broadcasting (the broadcast is scheduled)
+	Miner            string        `json:"miner" toml:"miner" yaml:"miner"`                                        // Use a specific miner or provider for broadcasting (IE: miner name)
+	MinerFailover    bool          `json:"miner_failover" toml:"miner_failover" yaml:"miner_failover"`             // Use the other providers if the specific miner fails
+	PaymailP2P       bool          `json:"paymail_p2p" toml:"paymail_p2p" yaml:"paymail_p2p"`                      // Transaction will be sent to all related paymail providers if P2P is detected
+	SyncOnChain      bool          `json:"sync_on_chain" toml:"sync_on_chain" yaml:"sync_on_chain"`                // Transaction should be checked that it's on-chain
 	// FUTURE IDEAS:
-	// DelayToBroadcast time.Duration `json:"delay_to_broadcast" toml:"delay_to_broadcast" yaml:"delay_to_broadcast"` // Delay for broadcasting
-	// Miner       string `json:"miner" toml:"miner" yaml:"miner"`  // Use a specific miner
-	// miners: []miner{name, token, feeQuote}
-	// default: miner
-	// failover: miner
 	// keep tx updated until x blocks?
 }
 
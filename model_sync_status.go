@@ -16,6 +16,9 @@ const (
 	// SyncStatusReady is when the sync is ready (waiting for workers)
 	SyncStatusReady SyncStatus = statusReady
 
+	// SyncStatusScheduled is when the broadcast is scheduled (delayed until the broadcast time)
+	SyncStatusScheduled SyncStatus = statusScheduled
+
 	// SyncStatusProcessing is when the sync is processing (worker is running task)
 	SyncStatusProcessing SyncStatus = statusProcessing
 
@@ -30,6 +33,12 @@ const (
 
 	// SyncStatusComplete is when the sync is complete
 	SyncStatusComplete SyncStatus = statusComplete
+
+	// SyncStatusDeadLetter is when the retries are exhausted (see SyncRetryPolicy)
+	SyncStatusDeadLetter SyncStatus = statusDeadLetter
+
+	// SyncStatusUnverifiable is when the sync cannot be done without a transaction lookup (SPV only mode)
+	SyncStatusUnverifiable SyncStatus = statusUnverifiable
 )
 
 // Scan will scan the value into Struct, implements sql.Scanner interface
@@ -44,6 +53,8 @@ func (t *SyncStatus) Scan(value interface{}) error {
 		*t = SyncStatusPending
 	case statusReady:
 		*t = SyncStatusReady
+	case statusScheduled:
+		*t = SyncStatusScheduled
 	case statusProcessing:
 		*t = SyncStatusProcessing
 	case statusCanceled:
@@ -54,6 +65,10 @@ func (t *SyncStatus) Scan(value interface{}) error {
 		*t = SyncStatusComplete
 	case statusSkipped:
 		*t = SyncStatusSkipped
+	case statusDeadLetter:
+		*t = SyncStatusDeadLetter
+	case statusUnverifiable:
+		*t = SyncStatusUnverifiable
 	}
 
 	return nil
@@ -2,9 +2,13 @@ package bux
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/mrz1836/go-datastore"
 	customTypes "github.com/mrz1836/go-datastore/custom_types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // SyncTransaction is an object representing the chain-state sync configuration and results for a given transaction
@@ -17,14 +21,18 @@ type SyncTransaction struct {
 	// Model specific fields
 	ID              string               `json:"id" toml:"id" yaml:"id" gorm:"<-:create;type:char(64);primaryKey;comment:This is the unique transaction id" bson:"_id"`
 	Configuration   SyncConfig           `json:"configuration" toml:"configuration" yaml:"configuration" gorm:"<-;type:text;comment:This is the configuration struct in JSON" bson:"configuration"`
+	BroadcastAt     customTypes.NullTime `json:"broadcast_at" toml:"broadcast_at" yaml:"broadcast_at" gorm:"<-;index;comment:When the scheduled broadcast is due" bson:"broadcast_at,omitempty"`
 	LastAttempt     customTypes.NullTime `json:"last_attempt" toml:"last_attempt" yaml:"last_attempt" gorm:"<-;comment:When the last broadcast occurred" bson:"last_attempt,omitempty"`
+	Attempts        uint32               `json:"attempts" toml:"attempts" yaml:"attempts" gorm:"<-;type:int;comment:This is the number of failed attempts" bson:"attempts"`
+	NextAttempt     customTypes.NullTime `json:"next_attempt" toml:"next_attempt" yaml:"next_attempt" gorm:"<-;index;comment:When the next attempt is due" bson:"next_attempt,omitempty"`
 	Results         SyncResults          `json:"results" toml:"results" yaml:"results" gorm:"<-;type:text;comment:This is the results struct in JSON" bson:"results"`
 	BroadcastStatus SyncStatus           `json:"broadcast_status" toml:"broadcast_status" yaml:"broadcast_status" gorm:"<-;type:varchar(10);index;comment:This is the status of the broadcast" bson:"broadcast_status"`
 	P2PStatus       SyncStatus           `json:"p2p_status" toml:"p2p_status" yaml:"p2p_status" gorm:"<-;column:p2p_status;type:varchar(10);index;comment:This is the status of the p2p paymail requests" bson:"p2p_status"`
-	SyncStatus      SyncStatus           `json:"sync_status" toml:"sync_status" yaml:"sync_status" gorm:"<-;type:varchar(10);index;comment:This is the status of the on-chain sync" bson:"sync_status"`
+	SyncStatus      SyncStatus           `json:"sync_status" toml:"sync_status" yaml:"sync_status" gorm:"<-;type:varchar(12);index;comment:This is the status of the on-chain sync" bson:"sync_status"`
 
 	// internal fields
-	transaction *Transaction
+	claimedAction string // Action claimed by a worker (see claimSyncTransaction)
+	transaction   *Transaction
 }
 
 // newSyncTransaction will start a new model (config is required)
@@ -34,10 +42,17 @@ func newSyncTransaction(txID string, config *SyncConfig, opts ...ModelOps) *Sync
 		return nil
 	}
 
-	// Broadcasting
+	// Broadcasting (scheduled if delayed)
 	bs := SyncStatusReady
+	var broadcastAt customTypes.NullTime
 	if !config.Broadcast {
 		bs = SyncStatusSkipped
+	} else if config.DelayToBroadcast > 0 {
+		bs = SyncStatusScheduled
+		broadcastAt = customTypes.NullTime{NullTime: sql.NullTime{
+			Time:  time.Now().UTC().Add(config.DelayToBroadcast),
+			Valid: true,
+		}}
 	}
 
 	// Notify Paymail P2P
@@ -53,15 +68,39 @@ func newSyncTransaction(txID string, config *SyncConfig, opts ...ModelOps) *Sync
 	}
 
 	return &SyncTransaction{
+		BroadcastAt:     broadcastAt,
 		BroadcastStatus: bs,
 		Configuration:   *config,
 		ID:              txID,
 		Model:           *NewBaseModel(ModelSyncTransaction, opts...),
+		NextAttempt:     nextAttemptAt(time.Now().UTC()),
 		P2PStatus:       ps,
 		SyncStatus:      ss,
 	}
 }
 
+// xPubID will return the xPub of the transaction used for grouping (empty if it has no input xPubs or is not loaded)
+func (m *SyncTransaction) xPubID() string {
+	if m.transaction == nil || len(m.transaction.XpubInIDs) == 0 {
+		return "" // fallback if we have no input xpubs
+	}
+
+	// use the first xpub for the grouping
+	// in most cases when we are broadcasting, there should be only 1 xpub in
+	return m.transaction.XpubInIDs[0]
+}
+
+// resetAttempts will reset the failed attempts (the action is complete, the next action starts over)
+func (m *SyncTransaction) resetAttempts() {
+	m.Attempts = 0
+	m.NextAttempt = nextAttemptAt(time.Now().UTC())
+}
+
+// nextAttemptAt will return the next attempt (it is never null, the due sync transactions are queried by it)
+func nextAttemptAt(at time.Time) customTypes.NullTime {
+	return customTypes.NullTime{NullTime: sql.NullTime{Time: at, Valid: true}}
+}
+
 // GetID will get the ID
 func (m *SyncTransaction) GetID() string {
 	return m.ID
@@ -78,7 +117,12 @@ func (m *SyncTransaction) GetModelTableName() string {
 }
 
 // Save will save the model into the Datastore
+//
+// A sync transaction claimed by a worker is only saved if the claimed action was not changed meanwhile
 func (m *SyncTransaction) Save(ctx context.Context) error {
+	if len(m.claimedAction) > 0 {
+		return m.saveClaimed(ctx)
+	}
 	return Save(ctx, m)
 }
 
@@ -93,6 +137,11 @@ func (m *SyncTransaction) BeforeCreating(_ context.Context) error {
 		return ErrMissingFieldID
 	}
 
+	// Due immediately
+	if !m.NextAttempt.Valid {
+		m.NextAttempt = nextAttemptAt(time.Now().UTC())
+	}
+
 	m.Client().Logger().Debug().
 		Str("txID", m.ID).
 		Msgf("end: %s BeforeCreate hook", m.Name())
@@ -137,5 +186,26 @@ func (m *SyncTransaction) BeforeUpdating(_ context.Context) error {
 
 // Migrate model specific migration on startup
 func (m *SyncTransaction) Migrate(client datastore.ClientInterface) error {
-	return client.IndexMetadata(client.GetTableName(tableSyncTransactions), metadataField)
+	tableName := client.GetTableName(tableSyncTransactions)
+	if err := m.migrateNextAttempt(client, tableName); err != nil {
+		return err
+	}
+	return client.IndexMetadata(tableName, metadataField)
+}
+
+// migrateNextAttempt will set the next attempt of the sync transactions created without one (due at creation)
+func (m *SyncTransaction) migrateNextAttempt(client datastore.ClientInterface, tableName string) error {
+	if client.Engine() == datastore.MongoDB {
+		_, err := client.GetMongoCollectionByTableName(tableName).UpdateMany(
+			context.Background(),
+			bson.M{nextAttemptField: nil},
+			mongo.Pipeline{{{Key: "$set", Value: bson.M{nextAttemptField: "$" + createdAtField}}}},
+		)
+		return err
+	}
+
+	return client.Execute(
+		"UPDATE " + tableName + " SET " + nextAttemptField + " = " + createdAtField +
+			" WHERE " + nextAttemptField + " IS NULL",
+	).Error
 }
@@ -3,6 +3,7 @@ package bux
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -134,3 +135,51 @@ func TestSyncTransaction_SaveHook(t *testing.T) {
 		require.Equal(t, 20, resultsLen)
 	})
 }
+
+// TestSyncTransaction_Migrate will test the method Migrate()
+func TestSyncTransaction_Migrate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sync transactions without next attempt are due", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, true, withTaskManagerMockup())
+		defer deferMe()
+
+		opts := []ModelOps{WithClient(client), New()}
+		syncTx := newSyncTransaction(testTxID, &SyncConfig{SyncOnChain: true}, opts...)
+		require.True(t, syncTx.NextAttempt.Valid)
+		require.NoError(t, syncTx.Save(ctx))
+
+		// created before the next attempt was set
+		tableName := client.Datastore().GetTableName(tableSyncTransactions)
+		require.NoError(t, client.Datastore().Execute(
+			"UPDATE "+tableName+" SET "+nextAttemptField+" = NULL",
+		).Error)
+		txs, err := getTransactionsToSync(ctx, nil, client.DefaultModelOptions()...)
+		require.NoError(t, err)
+		assert.Empty(t, txs)
+
+		require.NoError(t, syncTx.Migrate(client.Datastore()))
+		txs, err = getTransactionsToSync(ctx, nil, client.DefaultModelOptions()...)
+		require.NoError(t, err)
+		require.Len(t, txs, 1)
+		assert.Equal(t, testTxID, txs[0].ID)
+	})
+}
+
+// Test_dueConditions will test the method dueConditions()
+func Test_dueConditions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("the time bound is at the top level", func(t *testing.T) {
+		now := time.Now().UTC()
+		conditions := dueConditions(syncStatusField, now)
+
+		// Mongo converts the nested conditions ($and, $or) to JSON, the time would be a string
+		assert.Equal(t, map[string]interface{}{
+			syncStatusField:  SyncStatusReady.String(),
+			nextAttemptField: map[string]interface{}{conditionLessThanOrEqual: now},
+		}, conditions)
+		assert.NotContains(t, conditions, conditionOr)
+		assert.NotContains(t, conditions, conditionAnd)
+	})
+}
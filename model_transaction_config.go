@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/BuxOrg/bux/chainstate"
 	"github.com/BuxOrg/bux/utils"
 	"github.com/bitcoin-sv/go-paymail"
 	magic "github.com/bitcoinschema/go-map"
@@ -19,20 +20,21 @@ import (
 
 // TransactionConfig is the configuration used to start a transaction
 type TransactionConfig struct {
-	ChangeDestinations         []*Destination       `json:"change_destinations" toml:"change_destinations" yaml:"change_destinations" bson:"change_destinations"`
-	ChangeDestinationsStrategy ChangeStrategy       `json:"change_destinations_strategy" toml:"change_destinations_strategy" yaml:"change_destinations_strategy" bson:"change_destinations_strategy"`
-	ChangeMinimumSatoshis      uint64               `json:"change_minimum_satoshis" toml:"change_minimum_satoshis" yaml:"change_minimum_satoshis" bson:"change_minimum_satoshis"`
-	ChangeNumberOfDestinations int                  `json:"change_number_of_destinations" toml:"change_number_of_destinations" yaml:"change_number_of_destinations" bson:"change_number_of_destinations"`
-	ChangeSatoshis             uint64               `json:"change_satoshis" toml:"change_satoshis" yaml:"change_satoshis" bson:"change_satoshis"` // The satoshis used for change
-	ExpiresIn                  time.Duration        `json:"expires_in" toml:"expires_in" yaml:"expires_in" bson:"expires_in"`                     // The expiration time for the draft and utxos
-	Fee                        uint64               `json:"fee" toml:"fee" yaml:"fee" bson:"fee"`                                                 // The fee used for the transaction (auto generated)
-	FeeUnit                    *utils.FeeUnit       `json:"fee_unit" toml:"fee_unit" yaml:"fee_unit" bson:"fee_unit"`                             // Fee unit to use (overrides chainstate if set)
-	FromUtxos                  []*UtxoPointer       `json:"from_utxos" toml:"from_utxos" yaml:"from_utxos" bson:"from_utxos"`                     // Use these specific utxos for the transaction
-	IncludeUtxos               []*UtxoPointer       `json:"include_utxos" toml:"include_utxos" yaml:"include_utxos" bson:"include_utxos"`         // Include these utxos for the transaction, among others necessary if more is needed for fees
-	Inputs                     []*TransactionInput  `json:"inputs" toml:"inputs" yaml:"inputs" bson:"inputs"`                                     // All transaction inputs
-	Outputs                    []*TransactionOutput `json:"outputs" toml:"outputs" yaml:"outputs" bson:"outputs"`                                 // All transaction outputs
-	SendAllTo                  *TransactionOutput   `json:"send_all_to,omitempty" toml:"send_all_to" yaml:"send_all_to" bson:"send_all_to"`       // Send ALL utxos to the output
-	Sync                       *SyncConfig          `json:"sync" toml:"sync" yaml:"sync" bson:"sync"`                                             // Sync config for broadcasting and on-chain sync
+	ChangeDestinations         []*Destination         `json:"change_destinations" toml:"change_destinations" yaml:"change_destinations" bson:"change_destinations"`
+	ChangeDestinationsStrategy ChangeStrategy         `json:"change_destinations_strategy" toml:"change_destinations_strategy" yaml:"change_destinations_strategy" bson:"change_destinations_strategy"`
+	ChangeMinimumSatoshis      uint64                 `json:"change_minimum_satoshis" toml:"change_minimum_satoshis" yaml:"change_minimum_satoshis" bson:"change_minimum_satoshis"`
+	ChangeNumberOfDestinations int                    `json:"change_number_of_destinations" toml:"change_number_of_destinations" yaml:"change_number_of_destinations" bson:"change_number_of_destinations"`
+	ChangeSatoshis             uint64                 `json:"change_satoshis" toml:"change_satoshis" yaml:"change_satoshis" bson:"change_satoshis"`         // The satoshis used for change
+	ExpiresIn                  time.Duration          `json:"expires_in" toml:"expires_in" yaml:"expires_in" bson:"expires_in"`                             // The expiration time for the draft and utxos
+	Fee                        uint64                 `json:"fee" toml:"fee" yaml:"fee" bson:"fee"`                                                         // The fee used for the transaction (auto generated)
+	FeeUnit                    *utils.FeeUnit         `json:"fee_unit" toml:"fee_unit" yaml:"fee_unit" bson:"fee_unit"`                                     // Fee unit to use (overrides chainstate if set)
+	FeePriority                chainstate.FeePriority `json:"fee_priority,omitempty" toml:"fee_priority" yaml:"fee_priority" bson:"fee_priority,omitempty"` // Fee priority (multiplier over the chainstate fee unit) if no fee unit is set
+	FromUtxos                  []*UtxoPointer         `json:"from_utxos" toml:"from_utxos" yaml:"from_utxos" bson:"from_utxos"`                             // Use these specific utxos for the transaction
+	IncludeUtxos               []*UtxoPointer         `json:"include_utxos" toml:"include_utxos" yaml:"include_utxos" bson:"include_utxos"`                 // Include these utxos for the transaction, among others necessary if more is needed for fees
+	Inputs                     []*TransactionInput    `json:"inputs" toml:"inputs" yaml:"inputs" bson:"inputs"`                                             // All transaction inputs
+	Outputs                    []*TransactionOutput   `json:"outputs" toml:"outputs" yaml:"outputs" bson:"outputs"`                                         // All transaction outputs
+	SendAllTo                  *TransactionOutput     `json:"send_all_to,omitempty" toml:"send_all_to" yaml:"send_all_to" bson:"send_all_to"`               // Send ALL utxos to the output
+	Sync                       *SyncConfig            `json:"sync" toml:"sync" yaml:"sync" bson:"sync"`                                                     // Sync config for broadcasting and on-chain sync
 	// Future ideas:
 	// Conditions (utxo strategy, chain limit, split utxos)
 	// NlockTime uint32
@@ -77,6 +79,8 @@ type PaymailPayloadFormat uint32
 const (
 	BasicPaymailPayloadFormat PaymailPayloadFormat = iota
 	BeefPaymailPayloadFormat
+	BeefV2PaymailPayloadFormat
+	AtomicBeefPaymailPayloadFormat
 )
 
 func (format PaymailPayloadFormat) String() string {
@@ -87,11 +91,23 @@ func (format PaymailPayloadFormat) String() string {
 	case BeefPaymailPayloadFormat:
 		return "BeefPaymailPayloadFormat"
 
+	case BeefV2PaymailPayloadFormat:
+		return "BeefV2PaymailPayloadFormat"
+
+	case AtomicBeefPaymailPayloadFormat:
+		return "AtomicBeefPaymailPayloadFormat"
+
 	default:
 		return fmt.Sprintf("%d", uint32(format))
 	}
 }
 
+// isBeef will return true if the format sends the transaction with its ancestors (any BEEF format)
+func (format PaymailPayloadFormat) isBeef() bool {
+	return format == BeefPaymailPayloadFormat || format == BeefV2PaymailPayloadFormat ||
+		format == AtomicBeefPaymailPayloadFormat
+}
+
 // PaymailP4 paymail configuration for the p2p payments on this output
 type PaymailP4 struct {
 	Alias           string               `json:"alias" toml:"alias" yaml:"alias" bson:"alias,omitempty"`                                                       // Alias of the paymail {alias}@domain.com
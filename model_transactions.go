@@ -53,7 +53,10 @@ type Transaction struct {
 	TotalValue      uint64          `json:"total_value" toml:"total_value" yaml:"total_value" gorm:"<-create;type:bigint" bson:"total_value,omitempty"`
 	XpubMetadata    XpubMetadata    `json:"-" toml:"xpub_metadata" gorm:"<-;type:json;xpub_id specific metadata" bson:"xpub_metadata,omitempty"`
 	XpubOutputValue XpubOutputValue `json:"-" toml:"xpub_output_value" gorm:"<-;type:json;xpub_id specific value" bson:"xpub_output_value,omitempty"`
-	BUMP            BUMP            `json:"bump" toml:"bump" yaml:"bump" gorm:"<-;type:text;comment:BSV Unified Merkle Path (BUMP) Format" bson:"bump,omitempty"`
+	BUMP            BUMP            `json:"bump" toml:"bump" yaml:"bump" gorm:"<-;type:text;comment:BSV Unified Merkle Path (BUMP) Format" bson:"bump"`
+	BlockBUMPID     string          `json:"block_bump_id" toml:"block_bump_id" yaml:"block_bump_id" gorm:"<-;type:char(64);index;comment:This is the related block BUMP (merkle root)" bson:"block_bump_id"`
+	BUMPOffset      uint64          `json:"bump_offset" toml:"bump_offset" yaml:"bump_offset" gorm:"<-;type:bigint;comment:This is the offset of the transaction leaf in the block BUMP" bson:"bump_offset"`
+	MerkleProof     *MerkleProof    `json:"-" toml:"-" yaml:"-" gorm:"->;-:migration;type:text;comment:Legacy TSC merkle proof (superseded by BUMP)" bson:"merkle_proof,omitempty"`
 	TxStatus        string          `json:"txStatus" toml:"txStatus" yaml:"txStatus" gorm:"<-;type:varchar(64);comment:TxStatus retrieved from Arc API." bson:"txStatus,omitempty"`
 
 	// Virtual Fields
@@ -312,7 +315,7 @@ func (m *TransactionBase) hasOneKnownDestination(ctx context.Context, client Cli
 	// todo: this can be optimized searching X records at a time vs loop->query->loop->query
 	for _, output := range m.parsedTx.Outputs {
 		lockingScript := output.LockingScript.String()
-		destination, err := getDestinationWithCache(ctx, client, "", "", lockingScript)
+		destination, err := getDestinationWithCache(ctx, client, "", "", lockingScript, client.DefaultModelOptions()...)
 
 		if err != nil {
 			client.Logger().Error().Str("txID", m.ID).Msgf("error getting destination: %s", err.Error())
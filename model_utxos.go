@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/BuxOrg/bux/utils"
+	"github.com/libsv/go-bt/v2"
 	"github.com/mrz1836/go-datastore"
 	customTypes "github.com/mrz1836/go-datastore/custom_types"
 	"github.com/pkg/errors"
@@ -164,6 +165,11 @@ func reserveUtxos(ctx context.Context, xPubID, draftID string,
 	feeNeeded := uint64(0)
 	reservedSatoshis := uint64(0)
 
+	// Utxos skipped because of too many unconfirmed ancestors (they stay in the spendable results)
+	maxAncestors := m.Client().MaxUnconfirmedAncestors()
+	ancestorDepths := make(map[string]uint32)
+	skippedUtxos := make(map[string]bool)
+
 	queryParams := &datastore.QueryParams{}
 	if fromUtxos == nil {
 		// if we are not getting all utxos, paginate the retrieval
@@ -191,7 +197,29 @@ reserveUtxoLoop:
 		size := utils.GetInputSizeForType(utils.ScriptTypePubKeyHash)
 
 		// Loop the returned utxos
+		newUtxos := 0
 		for _, utxo := range freeUtxos {
+			if skippedUtxos[utxo.ID] {
+				continue
+			}
+			newUtxos++
+
+			// Skip the utxo if the chain of unconfirmed ancestors is too long to be accepted by miners
+			if maxAncestors > 0 {
+				var depth uint32
+				if depth, err = getUnconfirmedAncestorDepth(
+					ctx, utxo.TransactionID, maxAncestors, ancestorDepths, opts...,
+				); err != nil {
+					return nil, err
+				}
+				if depth > maxAncestors {
+					m.Client().Logger().Debug().
+						Str("utxoID", utxo.ID).
+						Msgf("skipping utxo, unconfirmed ancestors depth exceeds %d", maxAncestors)
+					skippedUtxos[utxo.ID] = true
+					continue
+				}
+			}
 
 			// Set the values on the UTXO
 			utxo.DraftID.Valid = true
@@ -222,15 +250,24 @@ reserveUtxoLoop:
 			// break the loop if we are not paginating
 			break reserveUtxoLoop
 		}
+
+		// skipped utxos are never reserved, move past a page that only contains skipped utxos
+		if newUtxos == 0 {
+			queryParams.Page++
+		}
 	}
 
 	if reservedSatoshis < satoshis {
+		notEnoughErr := ErrNotEnoughUtxos
+		if len(skippedUtxos) > 0 {
+			notEnoughErr = ErrUtxoUnconfirmedAncestorsLimit
+		}
 		if err = unReserveUtxos(
 			ctx, xPubID, draftID, m.GetOptions(false)...,
 		); err != nil {
-			return nil, errors.Wrap(err, ErrNotEnoughUtxos.Error())
+			return nil, errors.Wrap(err, notEnoughErr.Error())
 		}
-		return nil, ErrNotEnoughUtxos
+		return nil, notEnoughErr
 	}
 
 	// check whether an utxo was used twice, this is not valid
@@ -245,6 +282,57 @@ reserveUtxoLoop:
 	return *utxos, nil
 }
 
+// getUnconfirmedAncestorDepth will return the length of the longest chain of unconfirmed
+// transactions ending with the given transaction (0 = the transaction is mined)
+//
+// Transactions not handled by Bux are treated as mined. The walk stops as soon as the chain is longer
+// than the budget (a depth over the budget is returned), only the exact depths are cached in the given map
+// for the other utxos of the same reservation.
+func getUnconfirmedAncestorDepth(ctx context.Context, txID string, budget uint32,
+	depths map[string]uint32, opts ...ModelOps,
+) (uint32, error) {
+	if depth, ok := depths[txID]; ok {
+		return depth, nil
+	}
+
+	tx, err := getTransactionByID(ctx, "", txID, opts...)
+	if err != nil {
+		return 0, err
+	}
+
+	// mined (or unknown) transactions end the chain
+	if tx == nil || tx.isMined() || len(tx.BUMP.Path) > 0 {
+		depths[txID] = 0
+		return 0, nil
+	} else if budget == 0 {
+		return 1, nil // over the budget, the ancestors are not walked
+	}
+
+	var btTx *bt.Tx
+	if btTx, err = bt.NewTxFromString(tx.Hex); err != nil {
+		return 0, err
+	}
+
+	depth := uint32(1)
+	for _, input := range btTx.Inputs {
+		var parentDepth uint32
+		if parentDepth, err = getUnconfirmedAncestorDepth(
+			ctx, input.PreviousTxIDStr(), budget-1, depths, opts...,
+		); err != nil {
+			return 0, err
+		}
+		if parentDepth+1 > depth {
+			depth = parentDepth + 1
+		}
+		if depth > budget {
+			return depth, nil // over the budget, not an exact depth
+		}
+	}
+
+	depths[txID] = depth
+	return depth, nil
+}
+
 // newUtxoFromTxID will start a new utxo model
 func newUtxoFromTxID(txID string, index uint32, opts ...ModelOps) *Utxo {
 	return &Utxo{
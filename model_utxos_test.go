@@ -248,6 +248,56 @@ func TestUtxo_ReserveUtxos(t *testing.T) {
 		assert.Equal(t, uint32(16), utxos[0].OutputIndex)
 	})
 
+	t.Run("reserve with unconfirmed ancestors within limit", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(), WithMaxUnconfirmedAncestors(2))
+		defer deferMe()
+		err := createTestUtxos(ctx, client)
+		require.NoError(t, err)
+		createTestUnconfirmedChain(ctx, t, client)
+
+		var utxos []*Utxo
+		utxos, err = reserveUtxos(ctx, testXPubID, testDraftID2, 1000, 0.5, nil, client.DefaultModelOptions()...)
+		require.NoError(t, err)
+		assert.Len(t, utxos, 1)
+	})
+
+	t.Run("reserve with unconfirmed ancestors over limit", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(), WithMaxUnconfirmedAncestors(1))
+		defer deferMe()
+		err := createTestUtxos(ctx, client)
+		require.NoError(t, err)
+		createTestUnconfirmedChain(ctx, t, client)
+
+		_, err = reserveUtxos(ctx, testXPubID, testDraftID2, 1000, 0.5, nil, client.DefaultModelOptions()...)
+		require.ErrorIs(t, err, ErrUtxoUnconfirmedAncestorsLimit)
+
+		// nothing should stay reserved
+		var utxos []*Utxo
+		utxos, err = getUtxosByDraftID(ctx, testDraftID2, nil, client.DefaultModelOptions()...)
+		require.NoError(t, err)
+		assert.Len(t, utxos, 0)
+	})
+
+	t.Run("reserve with unconfirmed ancestors over limit, paginated", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(), WithMaxUnconfirmedAncestors(1))
+		defer deferMe()
+		err := createTestUtxos(ctx, client)
+		require.NoError(t, err)
+		createTestUnconfirmedChain(ctx, t, client)
+
+		// a confirmed utxo that is only found on a later page
+		opts := append(client.DefaultModelOptions(), New())
+		utxo := newUtxo(testXPubID, testTxInID, testLockingScript, 0, 5000, opts...)
+		require.NoError(t, utxo.Save(ctx))
+
+		var utxos []*Utxo
+		utxos, err = reserveUtxos(ctx, testXPubID, testDraftID2, 1000, 0.5, nil,
+			append(client.DefaultModelOptions(), WithPageSize(2))...)
+		require.NoError(t, err)
+		require.Len(t, utxos, 1)
+		assert.Equal(t, testTxInID, utxos[0].TransactionID)
+	})
+
 	t.Run("reserve fromUtxos 2", func(t *testing.T) {
 		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup())
 		defer deferMe()
@@ -324,6 +374,37 @@ func TestUtxo_ReserveUtxos(t *testing.T) {
 	})
 }
 
+// TestUtxo_getUnconfirmedAncestorDepth will test the method getUnconfirmedAncestorDepth()
+func TestUtxo_getUnconfirmedAncestorDepth(t *testing.T) {
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup())
+	defer deferMe()
+	createTestUnconfirmedChain(ctx, t, client)
+
+	tx, err := txFromHex(testTxHex, client.DefaultModelOptions()...)
+	require.NoError(t, err)
+
+	t.Run("exact depth", func(t *testing.T) {
+		depths := make(map[string]uint32)
+		depth, err := getUnconfirmedAncestorDepth(ctx, tx.ID, 10, depths, client.DefaultModelOptions()...)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(2), depth)
+		assert.Equal(t, uint32(2), depths[tx.ID])
+	})
+
+	t.Run("stops at the budget", func(t *testing.T) {
+		depths := make(map[string]uint32)
+		depth, err := getUnconfirmedAncestorDepth(ctx, tx.ID, 0, depths, client.DefaultModelOptions()...)
+		require.NoError(t, err)
+		assert.Greater(t, depth, uint32(0))
+		assert.Empty(t, depths) // the ancestors are not walked, nothing is cached
+
+		depth, err = getUnconfirmedAncestorDepth(ctx, tx.ID, 1, depths, client.DefaultModelOptions()...)
+		require.NoError(t, err)
+		assert.Greater(t, depth, uint32(1))
+		assert.NotContains(t, depths, tx.ID)
+	})
+}
+
 // TestUtxo_GetSpendableUtxos get spendable utxos
 func TestUtxo_GetSpendableUtxos(t *testing.T) {
 	t.Run("spendable", func(t *testing.T) {
@@ -427,3 +508,13 @@ func checkUtxoValues(t *testing.T, utxo *Utxo, index uint32, satoshis uint64) {
 	assert.Equal(t, utils.ScriptTypePubKeyHash, utxo.Type)
 	assert.Equal(t, ModelUtxo.String(), utxo.GetModelName())
 }
+
+// createTestUnconfirmedChain will save the unconfirmed testTxID (spending the unconfirmed testTxID2)
+func createTestUnconfirmedChain(ctx context.Context, t *testing.T, client ClientInterface) {
+	opts := append(client.DefaultModelOptions(), New())
+	for _, txHex := range []string{testTxHex, testTx2Hex} {
+		tx, err := txFromHex(txHex, opts...)
+		require.NoError(t, err)
+		require.NoError(t, tx.Save(ctx))
+	}
+}
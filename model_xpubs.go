@@ -242,6 +242,29 @@ func (m *Xpub) incrementNextNum(ctx context.Context, chain uint32) (uint32, erro
 	return uint32(newNum - 1), err
 }
 
+// advanceNextNum will advance the next num of the chain up to next (never backwards)
+func (m *Xpub) advanceNextNum(ctx context.Context, chain, next uint32) error {
+	fieldName, current := nextExternalNumField, m.NextExternalNum
+	if chain == utils.ChainInternal {
+		fieldName, current = nextInternalNumField, m.NextInternalNum
+	}
+	if next <= current {
+		return nil
+	}
+
+	newNum, err := incrementField(ctx, m, fieldName, int64(next-current))
+	if err != nil {
+		return err
+	}
+	if chain == utils.ChainInternal {
+		m.NextInternalNum = uint32(newNum)
+	} else {
+		m.NextExternalNum = uint32(newNum)
+	}
+
+	return m.AfterUpdated(ctx)
+}
+
 // ChildModels will get any related sub models
 func (m *Xpub) ChildModels() (childModels []ModelInterface) {
 	for index := range m.destinations {
@@ -29,7 +29,7 @@ func TestModelName_String(t *testing.T) {
 		assert.Equal(t, "transaction", ModelTransaction.String())
 		assert.Equal(t, "utxo", ModelUtxo.String())
 		assert.Equal(t, "xpub", ModelXPub.String())
-		assert.Len(t, AllModelNames, 9)
+		assert.Len(t, AllModelNames, 10)
 	})
 }
 
@@ -0,0 +1,275 @@
+package bux
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BuxOrg/bux/chainstate"
+	"github.com/BuxOrg/bux/cluster"
+	"github.com/BuxOrg/bux/utils"
+	"github.com/libsv/go-bt"
+)
+
+// maxMonitorTxSize is the max size of a line (raw transaction hex) of a file source
+const maxMonitorTxSize = 32 * 1024 * 1024
+
+// MonitorSource is a source of raw transactions for the monitor (IE: a node or mempool feed)
+type MonitorSource interface {
+	// Transactions will return the stream of raw transaction hexes (closed when the source is exhausted)
+	Transactions(ctx context.Context) (<-chan string, error)
+}
+
+// MonitorFilter is a filter of the monitor, returns the transaction if it matches (see chainstate/filters)
+type MonitorFilter func(tx *chainstate.TxInfo) (*bt.Tx, error)
+
+// monitorOptions holds the configuration and the state of the transaction monitor
+type monitorOptions struct {
+	bloom   *chainstate.BloomFilter  // Locking scripts of all the destinations
+	cancel  context.CancelFunc       // Stops the monitor
+	done    chan struct{}            // Closed when the monitor is stopped
+	filters map[string]MonitorFilter // Registered filters by name
+	source  MonitorSource            // Source of the transactions (nil: disabled)
+}
+
+// channelMonitorSource is a source reading the transactions from a channel
+type channelMonitorSource struct {
+	transactions <-chan string
+}
+
+// NewChannelMonitorSource will return a source reading the raw transaction hexes from the channel
+func NewChannelMonitorSource(transactions <-chan string) MonitorSource {
+	return &channelMonitorSource{transactions: transactions}
+}
+
+// Transactions will return the channel of the source
+func (s *channelMonitorSource) Transactions(_ context.Context) (<-chan string, error) {
+	return s.transactions, nil
+}
+
+// fileMonitorSource is a source reading the transactions from a file
+type fileMonitorSource struct {
+	path string
+}
+
+// NewFileMonitorSource will return a source reading a file with one raw transaction hex per line
+//
+// Empty lines and lines starting with # are ignored
+func NewFileMonitorSource(path string) MonitorSource {
+	return &fileMonitorSource{path: path}
+}
+
+// Transactions will stream the lines of the file
+func (s *fileMonitorSource) Transactions(ctx context.Context) (<-chan string, error) {
+	file, err := os.Open(s.path) //nolint:gosec // the path is given by the configuration
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make(chan string)
+	go func() {
+		defer close(transactions)
+		defer func() {
+			_ = file.Close()
+		}()
+
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxMonitorTxSize)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if len(line) == 0 || strings.HasPrefix(line, "#") {
+				continue
+			}
+			select {
+			case transactions <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return transactions, nil
+}
+
+// RegexMonitorFilter will return a filter matching the transactions with an output script (hex) matching the expression
+func RegexMonitorFilter(expr *regexp.Regexp) MonitorFilter {
+	return func(tx *chainstate.TxInfo) (*bt.Tx, error) {
+		for _, out := range tx.Vout {
+			if expr.MatchString(out.ScriptPubKey.Hex) {
+				return bt.NewTxFromString(tx.Hex)
+			}
+		}
+		return nil, nil
+	}
+}
+
+// loadMonitor will load the destinations into the bloom filter and start the monitor (if a source is set)
+func (c *Client) loadMonitor(ctx context.Context) error {
+	m := c.options.monitor
+	if m.source == nil {
+		return nil
+	}
+
+	// Load the locking scripts of the existing destinations
+	m.bloom = chainstate.DefaultBloomFilter()
+	if err := iterateModels[*Destination](
+		ctx, ModelDestination, nil, func(destination *Destination) error {
+			m.bloom.Add(destination.LockingScript)
+			return nil
+		}, c.DefaultModelOptions()...,
+	); err != nil {
+		return err
+	}
+
+	// New destinations (created on any node of the cluster)
+	if c.Cluster() != nil {
+		if _, err := c.Cluster().Subscribe(cluster.DestinationNew, func(lockingScript string) {
+			m.bloom.Add(lockingScript)
+		}); err != nil {
+			return err
+		}
+	}
+
+	monitorCtx, cancel := context.WithCancel(context.Background())
+	transactions, err := m.source.Transactions(monitorCtx)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	m.cancel = cancel
+	m.done = make(chan struct{})
+	go c.runMonitor(monitorCtx, transactions)
+	return nil
+}
+
+// stopMonitor will stop the monitor and wait for the transaction being processed
+func (c *Client) stopMonitor() {
+	m := c.options.monitor
+	if m.cancel == nil {
+		return
+	}
+	m.cancel()
+	<-m.done
+	m.cancel = nil
+}
+
+// runMonitor will process the transactions of the source until it's exhausted or the monitor is stopped
+func (c *Client) runMonitor(ctx context.Context, transactions <-chan string) {
+	defer close(c.options.monitor.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case txHex, ok := <-transactions:
+			if !ok {
+				c.Logger().Info().Msg("monitor source is exhausted")
+				return
+			}
+			if _, err := c.monitorTransaction(ctx, txHex); err != nil {
+				c.Logger().Warn().Err(err).Msg("monitor failed to process the transaction")
+			}
+		}
+	}
+}
+
+// monitorTransaction will record the transaction if it matches the bloom filter or a registered filter
+//
+// Returns nil if the transaction did not match or was already recorded
+func (c *Client) monitorTransaction(ctx context.Context, txHex string) (*Transaction, error) {
+	txInfo, err := monitorTxInfo(txHex)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter string
+	if filter, err = c.matchMonitorFilter(ctx, txInfo); err != nil || len(filter) == 0 {
+		return nil, err
+	}
+
+	// Already recorded (IE: a transaction of bux or seen before)
+	var existing *Transaction
+	if existing, err = getTransactionByID(
+		ctx, "", txInfo.TxID, c.DefaultModelOptions()...,
+	); err != nil || existing != nil {
+		return nil, err
+	}
+
+	c.Logger().Debug().
+		Str("txID", txInfo.TxID).
+		Str("filter", filter).
+		Msg("monitor is recording the transaction")
+
+	return c.RecordRawTransaction(ctx, txInfo.Hex, WithMetadata(MonitorFilterMetadataKey, filter))
+}
+
+// matchMonitorFilter will return the name of the filter matching the transaction (empty if none)
+func (c *Client) matchMonitorFilter(ctx context.Context, txInfo *chainstate.TxInfo) (string, error) {
+	m := c.options.monitor
+
+	// Destinations of bux (the bloom filter has false positives, so the destination is checked)
+	if m.bloom != nil {
+		for _, out := range txInfo.Vout {
+			lockingScript := utils.GetDestinationLockingScript(out.ScriptPubKey.Hex)
+			if !m.bloom.Test(lockingScript) {
+				continue
+			}
+			if _, err := getDestinationWithCache(
+				ctx, c, "", "", lockingScript, c.DefaultModelOptions()...,
+			); err == nil {
+				return chainstate.FilterBloom, nil
+			} else if !errors.Is(err, ErrMissingDestination) {
+				return "", err
+			}
+		}
+	}
+
+	// Registered filters (by name, for a deterministic match)
+	names := make([]string, 0, len(m.filters))
+	for name := range m.filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		tx, err := m.filters[name](txInfo)
+		if err != nil {
+			return "", err
+		} else if tx != nil {
+			return name, nil
+		}
+	}
+	return "", nil
+}
+
+// monitorTxInfo will parse the raw transaction into the info used by the filters
+func monitorTxInfo(txHex string) (*chainstate.TxInfo, error) {
+	tx, err := bt.NewTxFromString(txHex)
+	if err != nil {
+		return nil, err
+	}
+
+	txInfo := &chainstate.TxInfo{
+		Hash:     tx.GetTxID(),
+		Hex:      txHex,
+		LockTime: int64(tx.LockTime),
+		Size:     int64(len(txHex) / 2),
+		TxID:     tx.GetTxID(),
+		Version:  int64(tx.Version),
+	}
+	for index, out := range tx.Outputs {
+		lockingScript := out.LockingScript.ToString()
+		txInfo.Vout = append(txInfo.Vout, chainstate.VoutInfo{
+			N: int64(index),
+			ScriptPubKey: chainstate.ScriptPubKeyInfo{
+				Hex:  lockingScript,
+				Type: utils.GetDestinationType(lockingScript),
+			},
+			Value: float64(out.Satoshis) / 1e8,
+		})
+	}
+	return txInfo, nil
+}
@@ -0,0 +1,147 @@
+package bux
+
+import (
+	"context"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/BuxOrg/bux/chainstate"
+	"github.com/BuxOrg/bux/utils"
+	"github.com/libsv/go-bt/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestMonitorTx will return a transaction paying to the address (with an optional OP_RETURN)
+func newTestMonitorTx(t *testing.T, address string, data []byte) *bt.Tx {
+	tx := bt.NewTx()
+	require.NoError(t, tx.From(testTxID, 0, testLockingScript, 20000))
+	require.NoError(t, tx.PayToAddress(address, 10000))
+	if len(data) > 0 {
+		require.NoError(t, tx.AddOpReturnOutput(data))
+	}
+	return tx
+}
+
+// TestClient_monitorTransaction will test the method monitorTransaction()
+func TestClient_monitorTransaction(t *testing.T) {
+	data := []byte("bux monitor")
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+		WithMonitor(NewChannelMonitorSource(make(chan string))),
+		WithMonitorFilter("bux", RegexMonitorFilter(regexp.MustCompile(hex.EncodeToString(data)))),
+	)
+	defer deferMe()
+	c := client.(*Client)
+
+	_, err := client.NewXpub(ctx, testXPub, client.DefaultModelOptions()...)
+	require.NoError(t, err)
+
+	// created after the start of the monitor (added to the bloom filter via the cluster)
+	var destination *Destination
+	destination, err = client.NewDestination(
+		ctx, testXPub, utils.ChainExternal, utils.ScriptTypePubKeyHash, client.DefaultModelOptions()...,
+	)
+	require.NoError(t, err)
+
+	t.Run("destination of bux", func(t *testing.T) {
+		tx := newTestMonitorTx(t, destination.Address, nil)
+
+		recorded, err := c.monitorTransaction(ctx, tx.String())
+		require.NoError(t, err)
+		require.NotNil(t, recorded)
+		assert.Equal(t, tx.TxID(), recorded.ID)
+		assert.Equal(t, chainstate.FilterBloom, recorded.Metadata[MonitorFilterMetadataKey])
+
+		var utxo *Utxo
+		utxo, err = client.GetUtxoByTransactionID(ctx, tx.TxID(), 0)
+		require.NoError(t, err)
+		assert.Equal(t, testXPubID, utxo.XpubID)
+
+		// already recorded
+		recorded, err = c.monitorTransaction(ctx, tx.String())
+		require.NoError(t, err)
+		assert.Nil(t, recorded)
+	})
+
+	t.Run("registered filter", func(t *testing.T) {
+		tx := newTestMonitorTx(t, "1A1PjKqjWMNBzTVdcBru27EV1PHcXWc63W", data)
+
+		recorded, err := c.monitorTransaction(ctx, tx.String())
+		require.NoError(t, err)
+		require.NotNil(t, recorded)
+		assert.Equal(t, "bux", recorded.Metadata[MonitorFilterMetadataKey])
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		tx := newTestMonitorTx(t, "1A1PjKqjWMNBzTVdcBru27EV1PHcXWc63W", []byte("other"))
+
+		recorded, err := c.monitorTransaction(ctx, tx.String())
+		require.NoError(t, err)
+		assert.Nil(t, recorded)
+	})
+
+	t.Run("invalid hex", func(t *testing.T) {
+		_, err = c.monitorTransaction(ctx, "invalid")
+		require.Error(t, err)
+	})
+
+	t.Run("existing destinations are loaded", func(t *testing.T) {
+		c.stopMonitor()
+		require.NoError(t, c.loadMonitor(ctx))
+		assert.True(t, c.options.monitor.bloom.Test(destination.LockingScript))
+	})
+}
+
+// TestClient_Monitor will test the transactions of the monitor source
+func TestClient_Monitor(t *testing.T) {
+	tx := newTestMonitorTx(t, "1A1PjKqjWMNBzTVdcBru27EV1PHcXWc63W", []byte("bux monitor"))
+	filter := RegexMonitorFilter(regexp.MustCompile(hex.EncodeToString([]byte("bux monitor"))))
+
+	// the monitor stops when the source is exhausted
+	requireRecorded := func(ctx context.Context, t *testing.T, client ClientInterface, txID string) {
+		select {
+		case <-client.(*Client).options.monitor.done:
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "the monitor did not process the source")
+		}
+
+		recorded, err := client.GetTransaction(ctx, "", txID)
+		require.NoError(t, err)
+		assert.Equal(t, txID, recorded.ID)
+	}
+
+	t.Run("channel source", func(t *testing.T) {
+		transactions := make(chan string, 2)
+		transactions <- "invalid"
+		transactions <- tx.String()
+		close(transactions)
+
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+			WithMonitor(NewChannelMonitorSource(transactions)), WithMonitorFilter("bux", filter),
+		)
+		defer deferMe()
+
+		requireRecorded(ctx, t, client, tx.TxID())
+	})
+
+	t.Run("file source", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "transactions.txt")
+		require.NoError(t, os.WriteFile(path, []byte("# transactions\n\n"+tx.String()+"\n"), 0o600))
+
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+			WithMonitor(NewFileMonitorSource(path)), WithMonitorFilter("bux", filter),
+		)
+		defer deferMe()
+
+		requireRecorded(ctx, t, client, tx.TxID())
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := NewFileMonitorSource("missing.txt").Transactions(context.Background())
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
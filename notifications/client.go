@@ -20,6 +20,9 @@ const (
 
 	// EventTypeBroadcast when a transaction is broadcasted (sync tx)
 	EventTypeBroadcast EventType = "broadcast"
+
+	// EventTypeDeadLetter when the retries of a sync tx are exhausted (dead-letter status)
+	EventTypeDeadLetter EventType = "dead_letter"
 )
 
 type (
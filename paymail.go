@@ -66,15 +66,32 @@ func getCapabilities(ctx context.Context, cs cachestore.ClientInterface, client
 	return &response.CapabilitiesPayload, nil
 }
 
+// Capabilities of the BEEF formats (BRC-95 & BRC-96) for receiving P2P transactions
+const (
+	BRFCAtomicBeefTransaction = "df7af05e0dff" // Atomic BEEF Transaction, BuxOrg, v1
+	BRFCBeefV2Transaction     = "f06694dfaa44" // BEEF V2 Transaction, BuxOrg, v1
+)
+
 // hasP2P will return the P2P urls and true if they are both found
+//
+// The best payload format supported by the provider is negotiated: Atomic BEEF, BEEF V2, BEEF and then basic
 func hasP2P(capabilities *paymail.CapabilitiesPayload) (success bool, p2pDestinationURL, p2pSubmitTxURL string, format PaymailPayloadFormat) {
 	p2pDestinationURL = capabilities.GetString(paymail.BRFCP2PPaymentDestination, "")
 	p2pSubmitTxURL = capabilities.GetString(paymail.BRFCP2PTransactions, "")
-	p2pBeefSubmitTxURL := capabilities.GetString(paymail.BRFCBeefTransaction, "")
 
-	if len(p2pBeefSubmitTxURL) > 0 {
-		p2pSubmitTxURL = p2pBeefSubmitTxURL
-		format = BeefPaymailPayloadFormat
+	for _, beefFormat := range []struct {
+		brfc   string
+		format PaymailPayloadFormat
+	}{
+		{brfc: BRFCAtomicBeefTransaction, format: AtomicBeefPaymailPayloadFormat},
+		{brfc: BRFCBeefV2Transaction, format: BeefV2PaymailPayloadFormat},
+		{brfc: paymail.BRFCBeefTransaction, format: BeefPaymailPayloadFormat},
+	} {
+		if p2pBeefSubmitTxURL := capabilities.GetString(beefFormat.brfc, ""); len(p2pBeefSubmitTxURL) > 0 {
+			p2pSubmitTxURL = p2pBeefSubmitTxURL
+			format = beefFormat.format
+			break
+		}
 	}
 
 	if len(p2pSubmitTxURL) > 0 && len(p2pDestinationURL) > 0 {
@@ -150,6 +167,22 @@ func buildP2pTx(ctx context.Context, p4 *PaymailP4, transaction *Transaction) (*
 
 		p2pTransaction.Beef = beef
 
+	case BeefV2PaymailPayloadFormat:
+		beef, err := ToBeefV2(ctx, transaction, transaction.client)
+		if err != nil {
+			return nil, err
+		}
+
+		p2pTransaction.Beef = beef
+
+	case AtomicBeefPaymailPayloadFormat:
+		beef, err := ToAtomicBeef(ctx, transaction, transaction.client)
+		if err != nil {
+			return nil, err
+		}
+
+		p2pTransaction.Beef = beef
+
 	case BasicPaymailPayloadFormat:
 		p2pTransaction.Hex = transaction.Hex
 
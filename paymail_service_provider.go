@@ -14,6 +14,7 @@ import (
 	"github.com/bitcoin-sv/go-paymail/spv"
 	"github.com/bitcoinschema/go-bitcoin/v2"
 	"github.com/libsv/go-bk/bec"
+	"github.com/libsv/go-bt/v2/bscript"
 )
 
 // PaymailDefaultServiceProvider is an interface for overriding the paymail actions in go-paymail/server
@@ -254,7 +255,7 @@ func getXpubForPaymail(ctx context.Context, client ClientInterface, paymailAddre
 }
 
 func createDestination(ctx context.Context, paymailAddress *PaymailAddress, pubKey *derivedPubKey, opts ...ModelOps) (destination *Destination, err error) {
-	lockingScript, err := createLockingScript(pubKey.ecPubKey)
+	lockingScript, address, err := createLockingScript(pubKey.ecPubKey, !pubKey.testNet)
 	if err != nil {
 		return nil, err
 	}
@@ -262,6 +263,7 @@ func createDestination(ctx context.Context, paymailAddress *PaymailAddress, pubK
 	// create a new destination, based on the External xPub child
 	// this is not yet possible using the xpub struct. That needs the full xPub, which we don't have.
 	destination = newDestination(paymailAddress.XpubID, lockingScript, append(opts, New())...)
+	destination.Address = address // the address uses the network of the xPub (IE: a tpub on regtest)
 	destination.Chain = utils.ChainExternal
 	destination.Num = pubKey.chainNum
 
@@ -276,12 +278,12 @@ func lockKey(paymailAddress *PaymailAddress) string {
 	return fmt.Sprintf(lockKeyProcessXpub, paymailAddress.XpubID)
 }
 
-func createLockingScript(ecPubKey *bec.PublicKey) (lockingScript string, err error) {
-	bsvAddress, err := bitcoin.GetAddressFromPubKey(ecPubKey, true)
+func createLockingScript(ecPubKey *bec.PublicKey, mainnet bool) (lockingScript, address string, err error) {
+	bsvAddress, err := bscript.NewAddressFromPublicKey(ecPubKey, mainnet)
 	if err != nil {
 		return
 	}
-	address := bsvAddress.AddressString
+	address = bsvAddress.AddressString
 
 	lockingScript, err = bitcoin.ScriptFromAddress(address)
 	return
@@ -291,6 +293,7 @@ type derivedPubKey struct {
 	ecPubKey *bec.PublicKey
 	chainNum uint32
 	pubKey   string
+	testNet  bool // If the xPub has the test version bytes (testnet & regtest)
 }
 
 func deriveKey(rawXPubKey string, num uint32) (k *derivedPubKey, err error) {
@@ -300,6 +303,7 @@ func deriveKey(rawXPubKey string, num uint32) (k *derivedPubKey, err error) {
 	if err != nil {
 		return
 	}
+	k.testNet = utils.IsTestNetKey(hdKey)
 
 	derivedKey, err := bitcoin.GetHDKeyChild(hdKey, num)
 	if err != nil {
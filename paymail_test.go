@@ -2,7 +2,9 @@ package bux
 
 import (
 	"context"
+	"encoding/hex"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/bitcoin-sv/go-paymail"
 	"github.com/bitcoin-sv/go-paymail/server"
 	"github.com/jarcoal/httpmock"
+	"github.com/libsv/go-bt/v2"
 	"github.com/mrz1836/go-cache"
 	"github.com/mrz1836/go-datastore"
 	"github.com/rs/zerolog"
@@ -171,6 +174,105 @@ func Test_hasP2P_beefCapabilities(t *testing.T) {
 	})
 }
 
+// Test_hasP2P_beefFormats will test the method hasP2P() negotiating the BEEF format
+//
+// Atomic BEEF is preferred over BEEF V2, BEEF V2 over BEEF (V1) and BEEF over the basic format
+func Test_hasP2P_beefFormats(t *testing.T) {
+	t.Parallel()
+
+	const (
+		atomicBeefURL = "https://test.com/atomic/beef/{alias}@{domain.tld}"
+		beefV2URL     = "https://test.com/v2/beef/{alias}@{domain.tld}"
+	)
+
+	for _, tc := range []struct {
+		name        string
+		beef        bool
+		brfcs       map[string]string
+		format      PaymailPayloadFormat
+		submitTxURL func(capabilities *paymail.CapabilitiesPayload) interface{}
+	}{
+		{
+			name:   "basic",
+			format: BasicPaymailPayloadFormat,
+			submitTxURL: func(capabilities *paymail.CapabilitiesPayload) interface{} {
+				return capabilities.Capabilities[paymail.BRFCP2PTransactions]
+			},
+		},
+		{
+			name:   "beef",
+			beef:   true,
+			format: BeefPaymailPayloadFormat,
+			submitTxURL: func(capabilities *paymail.CapabilitiesPayload) interface{} {
+				return capabilities.Capabilities[paymail.BRFCBeefTransaction]
+			},
+		},
+		{
+			name:   "beef v2",
+			beef:   true,
+			brfcs:  map[string]string{BRFCBeefV2Transaction: beefV2URL},
+			format: BeefV2PaymailPayloadFormat,
+			submitTxURL: func(*paymail.CapabilitiesPayload) interface{} {
+				return beefV2URL
+			},
+		},
+		{
+			name:   "atomic beef",
+			beef:   true,
+			brfcs:  map[string]string{BRFCBeefV2Transaction: beefV2URL, BRFCAtomicBeefTransaction: atomicBeefURL},
+			format: AtomicBeefPaymailPayloadFormat,
+			submitTxURL: func(*paymail.CapabilitiesPayload) interface{} {
+				return atomicBeefURL
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			capabilities := mockCapabilities(t, true, tc.beef)
+			for brfc, url := range tc.brfcs {
+				capabilities.Capabilities[brfc] = url
+			}
+
+			success, _, p2pSubmitTxURL, format := hasP2P(capabilities)
+			assert.Equal(t, true, success)
+			assert.Equal(t, tc.format, format)
+			assert.Equal(t, tc.submitTxURL(capabilities), p2pSubmitTxURL)
+		})
+	}
+}
+
+// Test_buildP2pTx will test the method buildP2pTx() with each payload format
+func Test_buildP2pTx(t *testing.T) {
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup())
+	defer deferMe()
+
+	opts := append(client.DefaultModelOptions(), New())
+	bumps, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+	parent, err := txFromHex(txs[0].String(), opts...)
+	require.NoError(t, err)
+	parent.BUMP = *bumps[0]
+	parent.BlockHeight = bumps[0].BlockHeight
+	require.NoError(t, parent.Save(ctx))
+
+	transaction, err := txFromHex(txs[1].String(), opts...)
+	require.NoError(t, err)
+
+	for format, prefix := range map[PaymailPayloadFormat]string{
+		BeefPaymailPayloadFormat:       "0100beef",
+		BeefV2PaymailPayloadFormat:     "0200beef",
+		AtomicBeefPaymailPayloadFormat: "01010101" + hex.EncodeToString(bt.ReverseBytes(txs[1].TxIDBytes())),
+	} {
+		p2pTx, buildErr := buildP2pTx(ctx, &PaymailP4{Format: format}, transaction)
+		require.NoError(t, buildErr)
+		assert.True(t, strings.HasPrefix(p2pTx.Beef, prefix), format.String())
+		assert.Empty(t, p2pTx.Hex)
+	}
+
+	p2pTx, err := buildP2pTx(ctx, &PaymailP4{Format: BasicPaymailPayloadFormat}, transaction)
+	require.NoError(t, err)
+	assert.Equal(t, transaction.Hex, p2pTx.Hex)
+	assert.Empty(t, p2pTx.Beef)
+}
+
 // Test_startP2PTransaction will test the method startP2PTransaction()
 func Test_startP2PTransaction(t *testing.T) {
 	// t.Parallel() mocking does not allow parallel tests
@@ -0,0 +1,310 @@
+package bux
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/go-datastore"
+	"gorm.io/gorm/schema"
+)
+
+// filterOperator is the operator of a Filter
+type filterOperator string
+
+const (
+	filterAnd      filterOperator = "and"
+	filterEq       filterOperator = "eq"
+	filterIn       filterOperator = "in"
+	filterMetadata filterOperator = "metadata"
+	filterOr       filterOperator = "or"
+	filterRange    filterOperator = "range"
+)
+
+// Filter is a typed query condition for the Get* methods
+//
+// Filters are compiled into the datastore conditions (SQL & Mongo) using Client.FilterConditions(),
+// validating the fields against the fields of the model
+//
+// On Mongo, the values nested in an or condition are converted to JSON: time values are only
+// available at the top level or in an And() (IE: a time Range() in an Or() is invalid)
+type Filter struct {
+	field    string
+	filters  []*Filter
+	from     interface{}
+	operator filterOperator
+	to       interface{}
+	values   []interface{}
+}
+
+// Eq will filter the records where the field equals the value (nil = field is not set)
+func Eq(field string, value interface{}) *Filter {
+	return &Filter{operator: filterEq, field: field, values: []interface{}{value}}
+}
+
+// In will filter the records where the field equals one of the values
+func In(field string, values ...interface{}) *Filter {
+	return &Filter{operator: filterIn, field: field, values: values}
+}
+
+// Range will filter the records where the field is between from and to (inclusive)
+//
+// Use nil for an open bound
+func Range(field string, from, to interface{}) *Filter {
+	return &Filter{operator: filterRange, field: field, from: from, to: to}
+}
+
+// MetadataMatch will filter the records where the metadata path (key or key.subKey) equals the value
+func MetadataMatch(path string, value interface{}) *Filter {
+	return &Filter{operator: filterMetadata, field: path, values: []interface{}{value}}
+}
+
+// And will filter the records matching all the filters
+func And(filters ...*Filter) *Filter {
+	return &Filter{operator: filterAnd, filters: filters}
+}
+
+// Or will filter the records matching any of the filters
+func Or(filters ...*Filter) *Filter {
+	return &Filter{operator: filterOr, filters: filters}
+}
+
+// FilterConditions will compile the filter into conditions for the Get* methods of the given model
+//
+// Returns ErrFilterUnknownField if a field does not exist on the model
+func (c *Client) FilterConditions(modelName ModelName, filter *Filter) (*map[string]interface{}, error) {
+	for _, model := range c.options.models.models {
+		if model.(ModelInterface).Name() == modelName.String() {
+			return filter.compile(
+				modelName, getFilterFields(model), c.Datastore().Engine(),
+			)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrFilterUnknownModel, modelName)
+}
+
+// compile will compile the filter into datastore conditions, fields are the model fields (column => engine field)
+func (f *Filter) compile(modelName ModelName, fields map[string]string,
+	engine datastore.Engine,
+) (*map[string]interface{}, error) {
+	if f == nil {
+		return nil, fmt.Errorf("%w: missing filter", ErrFilterInvalid)
+	}
+
+	// Compile the nested filters
+	if f.operator == filterAnd || f.operator == filterOr {
+		if len(f.filters) == 0 {
+			return nil, fmt.Errorf("%w: %s without filters", ErrFilterInvalid, f.operator)
+		}
+		nested := make([]map[string]interface{}, 0, len(f.filters))
+		for _, filter := range f.filters {
+			conditions, err := filter.compile(modelName, fields, engine)
+			if err != nil {
+				return nil, err
+			}
+			nested = append(nested, *conditions)
+		}
+
+		// All the conditions of an and are kept at the top level when possible (see mergeConditions)
+		if f.operator == filterAnd {
+			conditions := make(map[string]interface{}, len(nested))
+			for _, condition := range nested {
+				mergeConditions(conditions, condition)
+			}
+			return &conditions, nil
+		}
+		if engine == datastore.MongoDB && hasTimeValue(nested) {
+			return nil, fmt.Errorf("%w: time values in %s on mongo", ErrFilterInvalid, f.operator)
+		}
+		return &map[string]interface{}{conditionOr: nested}, nil
+	}
+
+	// Metadata is stored as key/value pairs in Mongo and as a JSON object in SQL
+	if f.operator == filterMetadata {
+		if _, ok := fields[metadataField]; !ok {
+			return nil, fmt.Errorf("%w: %s on model %s", ErrFilterUnknownField, metadataField, modelName)
+		}
+		path := strings.Split(f.field, ".")
+		if len(path) > 2 || len(path[0]) == 0 || len(path[len(path)-1]) == 0 {
+			return nil, fmt.Errorf("%w: metadata path %q (key or key.subKey)", ErrFilterInvalid, f.field)
+		}
+		if len(path) == 1 {
+			return &map[string]interface{}{metadataField: map[string]interface{}{path[0]: f.values[0]}}, nil
+		}
+
+		// The key and the sub key must match the same key/value pair (see processMetadataPathConditions)
+		field := metadataField
+		if engine == datastore.MongoDB {
+			field = metadataPathField
+		}
+		return &map[string]interface{}{field: map[string]interface{}{
+			path[0]: map[string]interface{}{path[1]: f.values[0]},
+		}}, nil
+	}
+
+	// Validate the field
+	column, ok := fields[f.field]
+	if !ok || f.field == metadataField {
+		return nil, fmt.Errorf("%w: %s on model %s", ErrFilterUnknownField, f.field, modelName)
+	}
+	if engine != datastore.MongoDB {
+		column = f.field
+	}
+
+	switch f.operator { //nolint:exhaustive // nested & metadata filters are compiled above
+	case filterEq:
+		return &map[string]interface{}{column: f.values[0]}, nil
+	case filterIn:
+		if len(f.values) == 0 {
+			return nil, fmt.Errorf("%w: %s in without values", ErrFilterInvalid, f.field)
+		}
+		or := make([]map[string]interface{}, 0, len(f.values))
+		for _, value := range f.values {
+			or = append(or, map[string]interface{}{column: value})
+		}
+		if engine == datastore.MongoDB && hasTimeValue(or) {
+			return nil, fmt.Errorf("%w: time values in %s on mongo", ErrFilterInvalid, f.operator)
+		}
+		return &map[string]interface{}{conditionOr: or}, nil
+	case filterRange:
+		bounds := map[string]interface{}{}
+		if f.from != nil {
+			bounds[conditionGreaterThanOrEqual] = f.from
+		}
+		if f.to != nil {
+			bounds[conditionLessThanOrEqual] = f.to
+		}
+		if len(bounds) == 0 {
+			return nil, fmt.Errorf("%w: %s range without bounds", ErrFilterInvalid, f.field)
+		}
+		return &map[string]interface{}{column: bounds}, nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown operator %s", ErrFilterInvalid, f.operator)
+}
+
+// mergeConditions will add the conditions to the top level of the destination conditions
+//
+// A condition on a field which is already set is merged if both are operator maps without common operators
+// (IE: two bounds of a range), otherwise it is nested in an and condition
+func mergeConditions(dst, src map[string]interface{}) {
+	for key, value := range src {
+		current, ok := dst[key]
+		if !ok {
+			dst[key] = value
+			continue
+		}
+
+		if key == conditionAnd {
+			if and, isSlice := value.([]map[string]interface{}); isSlice {
+				appendAndConditions(dst, and...)
+				continue
+			}
+		} else if merged, isMerged := mergeOperators(current, value); isMerged {
+			dst[key] = merged
+			continue
+		}
+
+		appendAndConditions(dst, map[string]interface{}{key: value})
+	}
+}
+
+// appendAndConditions will append the conditions to the and condition of the destination conditions
+func appendAndConditions(dst map[string]interface{}, conditions ...map[string]interface{}) {
+	and, ok := dst[conditionAnd].([]map[string]interface{})
+	if current, exists := dst[conditionAnd]; exists && !ok {
+		and = []map[string]interface{}{{conditionAnd: current}}
+	}
+	dst[conditionAnd] = append(and, conditions...)
+}
+
+// mergeOperators will merge two maps of conditions on the same field, false if they cannot be merged
+func mergeOperators(current, value interface{}) (map[string]interface{}, bool) {
+	currentMap, ok := current.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	valueMap, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	merged := make(map[string]interface{}, len(currentMap)+len(valueMap))
+	for key, condition := range currentMap {
+		merged[key] = condition
+	}
+	for key, condition := range valueMap {
+		if _, ok = merged[key]; ok {
+			return nil, false
+		}
+		merged[key] = condition
+	}
+	return merged, true
+}
+
+// hasTimeValue will return true if the conditions contain a time value
+func hasTimeValue(conditions interface{}) bool {
+	switch value := conditions.(type) {
+	case time.Time, *time.Time:
+		return true
+	case map[string]interface{}:
+		for _, condition := range value {
+			if hasTimeValue(condition) {
+				return true
+			}
+		}
+	case []map[string]interface{}:
+		for _, condition := range value {
+			if hasTimeValue(condition) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getFilterFields will return the persisted fields of the model (SQL column => Mongo field)
+func getFilterFields(model interface{}) map[string]string {
+	fields := make(map[string]string)
+	addFilterFields(reflect.TypeOf(model), fields)
+	return fields
+}
+
+// addFilterFields will add the persisted fields of the struct type (and embedded structs)
+func addFilterFields(modelType reflect.Type, fields map[string]string) {
+	if modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	if modelType.Kind() != reflect.Struct {
+		return
+	}
+
+	naming := schema.NamingStrategy{}
+	for i := 0; i < modelType.NumField(); i++ {
+		field := modelType.Field(i)
+		if field.Anonymous {
+			addFilterFields(field.Type, fields)
+			continue
+		}
+
+		// Skip private and virtual fields
+		gormTag := field.Tag.Get("gorm")
+		if !field.IsExported() || gormTag == "-" {
+			continue
+		}
+
+		column := naming.ColumnName("", field.Name)
+		for _, setting := range strings.Split(gormTag, ";") {
+			if strings.HasPrefix(setting, "column:") {
+				column = strings.TrimPrefix(setting, "column:")
+			}
+		}
+
+		mongoField := strings.Split(field.Tag.Get("bson"), ",")[0]
+		if mongoField == "" || mongoField == "-" {
+			mongoField = column
+		}
+		fields[column] = mongoField
+	}
+}
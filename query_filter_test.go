@@ -0,0 +1,182 @@
+package bux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mrz1836/go-datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFilter_compile will test the method compile()
+func TestFilter_compile(t *testing.T) {
+	fields := getFilterFields(&Transaction{})
+
+	t.Run("model fields", func(t *testing.T) {
+		assert.Equal(t, "_id", fields[idField])
+		assert.Equal(t, "txStatus", fields["tx_status"])
+		assert.Equal(t, metadataField, fields[metadataField])
+		assert.Equal(t, createdAtField, fields[createdAtField])
+		assert.NotContains(t, fields, "output_value")
+	})
+
+	t.Run("eq, in, range", func(t *testing.T) {
+		conditions, err := And(
+			Eq(draftIDField, testDraftID),
+			In(blockHeightField, 1, 2),
+			Range("fee", 10, nil),
+		).compile(ModelTransaction, fields, datastore.SQLite)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			draftIDField: testDraftID,
+			conditionOr:  []map[string]interface{}{{blockHeightField: 1}, {blockHeightField: 2}},
+			"fee":        map[string]interface{}{conditionGreaterThanOrEqual: 10},
+		}, *conditions)
+	})
+
+	t.Run("and conflicts", func(t *testing.T) {
+		conditions, err := And(
+			Range("fee", 10, nil),
+			Range("fee", nil, 20),
+			Eq(draftIDField, testDraftID),
+			Eq(draftIDField, "other"),
+			In(blockHeightField, 1),
+			In(blockHeightField, 2),
+		).compile(ModelTransaction, fields, datastore.SQLite)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"fee":        map[string]interface{}{conditionGreaterThanOrEqual: 10, conditionLessThanOrEqual: 20},
+			draftIDField: testDraftID,
+			conditionOr:  []map[string]interface{}{{blockHeightField: 1}},
+			conditionAnd: []map[string]interface{}{
+				{draftIDField: "other"},
+				{conditionOr: []map[string]interface{}{{blockHeightField: 2}}},
+			},
+		}, *conditions)
+	})
+
+	t.Run("mongo field names", func(t *testing.T) {
+		conditions, err := Or(
+			Eq("tx_status", "MINED"),
+			MetadataMatch("tags.user", "test"),
+		).compile(ModelTransaction, fields, datastore.MongoDB)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			conditionOr: []map[string]interface{}{
+				{"txStatus": "MINED"},
+				{metadataPathField: map[string]interface{}{"tags": map[string]interface{}{"user": "test"}}},
+			},
+		}, *conditions)
+	})
+
+	t.Run("mongo time range", func(t *testing.T) {
+		from := time.Now().UTC().Add(-time.Hour)
+		to := from.Add(30 * time.Minute)
+		conditions, err := And(
+			Range(createdAtField, from, to),
+			Eq("tx_status", "MINED"),
+		).compile(ModelTransaction, fields, datastore.MongoDB)
+		require.NoError(t, err)
+
+		// The time bounds are at the top level (Mongo converts the nested conditions to JSON)
+		assert.Equal(t, map[string]interface{}{
+			createdAtField: map[string]interface{}{conditionGreaterThanOrEqual: from, conditionLessThanOrEqual: to},
+			"txStatus":     "MINED",
+		}, *conditions)
+
+		_, err = Or(Range(createdAtField, from, nil), Eq("tx_status", "MINED")).
+			compile(ModelTransaction, fields, datastore.MongoDB)
+		assert.ErrorIs(t, err, ErrFilterInvalid)
+
+		_, err = In(createdAtField, from, to).compile(ModelTransaction, fields, datastore.MongoDB)
+		assert.ErrorIs(t, err, ErrFilterInvalid)
+	})
+
+	t.Run("mongo metadata path", func(t *testing.T) {
+		conditions, err := And(
+			MetadataMatch("tags.user", "test"),
+			MetadataMatch("tags.group", "admin"),
+		).compile(ModelTransaction, fields, datastore.MongoDB)
+		require.NoError(t, err)
+
+		// The key and the sub key match the same key/value pair
+		processCustomFields(conditions)
+		assert.Equal(t, map[string]interface{}{
+			"$elemMatch": map[string]interface{}{"k": "tags", "v.user": "test"},
+		}, (*conditions)[metadataField])
+		assert.Equal(t, []map[string]interface{}{{
+			metadataPathField: map[string]interface{}{"tags": map[string]interface{}{"group": "admin"}},
+		}}, (*conditions)[conditionAnd])
+		assert.NotContains(t, *conditions, metadataPathField)
+
+		// The nested condition is processed the same way
+		nested := (*conditions)[conditionAnd].([]map[string]interface{})[0]
+		processCustomFields(&nested)
+		assert.Equal(t, map[string]interface{}{
+			metadataField: map[string]interface{}{
+				"$elemMatch": map[string]interface{}{"k": "tags", "v.group": "admin"},
+			},
+		}, nested)
+	})
+
+	t.Run("metadata path", func(t *testing.T) {
+		conditions, err := MetadataMatch("tags.user", "test").compile(ModelTransaction, fields, datastore.SQLite)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			metadataField: map[string]interface{}{"tags": map[string]interface{}{"user": "test"}},
+		}, *conditions)
+
+		_, err = MetadataMatch("a.b.c", "test").compile(ModelTransaction, fields, datastore.SQLite)
+		assert.ErrorIs(t, err, ErrFilterInvalid)
+	})
+
+	t.Run("invalid filters", func(t *testing.T) {
+		for _, filter := range []*Filter{
+			nil, And(), Or(), In(draftIDField), Range("fee", nil, nil), And(Eq(draftIDField, ""), nil),
+		} {
+			_, err := filter.compile(ModelTransaction, fields, datastore.SQLite)
+			assert.ErrorIs(t, err, ErrFilterInvalid)
+		}
+	})
+
+	t.Run("unknown fields", func(t *testing.T) {
+		for _, filter := range []*Filter{
+			Eq("unknown", 1), Eq("output_value", 1), Eq(metadataField, "test"), Or(Eq(draftIDField, ""), In("fees", 1)),
+		} {
+			_, err := filter.compile(ModelTransaction, fields, datastore.SQLite)
+			assert.ErrorIs(t, err, ErrFilterUnknownField)
+		}
+	})
+}
+
+// TestClient_FilterConditions will test the method FilterConditions()
+func TestClient_FilterConditions(t *testing.T) {
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup())
+	defer deferMe()
+	require.NoError(t, createTestUtxos(ctx, client))
+
+	t.Run("get utxos", func(t *testing.T) {
+		conditions, err := client.FilterConditions(ModelUtxo, And(
+			Eq(xPubIDField, testXPubID),
+			In("output_index", 12, 13, 20),
+			Range(satoshisField, 1000, 2000),
+		))
+		require.NoError(t, err)
+
+		var utxos []*Utxo
+		utxos, err = client.GetUtxos(ctx, nil, conditions, nil)
+		require.NoError(t, err)
+		assert.Len(t, utxos, 2)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := client.FilterConditions(ModelUtxo, Eq("block_height", 1))
+		assert.ErrorIs(t, err, ErrFilterUnknownField)
+	})
+
+	t.Run("unknown model", func(t *testing.T) {
+		_, err := client.FilterConditions(ModelName("unknown"), Eq(idField, 1))
+		assert.ErrorIs(t, err, ErrFilterUnknownModel)
+	})
+}
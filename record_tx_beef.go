@@ -0,0 +1,179 @@
+package bux
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/BuxOrg/bux/utils"
+	"github.com/bitcoin-sv/go-paymail/beef"
+	"github.com/bitcoin-sv/go-paymail/spv"
+)
+
+// verifyBEEF will decode the BEEF hex (BEEF, BEEF V2 or Atomic BEEF) and verify it using SPV
+//
+// Checks the scripts & amounts of the unmined transactions, the merkle roots of the BUMPs (HeaderService)
+// and the fee of the subject transaction
+func verifyBEEF(ctx context.Context, c ClientInterface, beefHex string) (*beef.DecodedBEEF, error) {
+	dBeef, knownTxIDs, err := decodeBEEFHex(beefHex)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBEEF, err.Error())
+	}
+	if len(dBeef.Transactions) == 0 {
+		return nil, fmt.Errorf("%w: no transactions", ErrInvalidBEEF)
+	} else if !dBeef.Transactions[len(dBeef.Transactions)-1].Unmined() {
+		// SPV does not check the scripts & amounts of a mined transaction
+		return nil, fmt.Errorf("%w: the subject transaction has a BUMP", ErrInvalidBEEF)
+	}
+
+	// txid only ancestors (BEEF V2) must be known
+	if err = hydrateKnownBEEFTxs(ctx, c, dBeef, knownTxIDs); err != nil {
+		return nil, err
+	}
+
+	// SPV expects the parent outputs of the unmined transactions to exist
+	if err = checkBEEFInputs(dBeef); err != nil {
+		return nil, err
+	}
+
+	// SPV only checks the BUMPs of the mined ancestors of the subject, all the mined transactions are saved
+	if err = checkBEEFBUMPs(dBeef); err != nil {
+		return nil, err
+	}
+
+	// the paymail service provider verifies the merkle roots using the HeaderService
+	if err = spv.ExecuteSimplifiedPaymentVerification(
+		ctx, dBeef, &PaymailDefaultServiceProvider{client: c},
+	); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBEEFVerificationFailed, err.Error())
+	}
+
+	if err = checkBEEFFee(dBeef, c.Chainstate().FeeUnit()); err != nil {
+		return nil, err
+	}
+
+	return dBeef, nil
+}
+
+// checkBEEFInputs will check that the parent output of each input of the unmined transactions is in the BEEF
+func checkBEEFInputs(dBeef *beef.DecodedBEEF) error {
+	for _, txData := range dBeef.Transactions {
+		if txData.Transaction == nil {
+			return fmt.Errorf("%w: missing transaction %s", ErrInvalidBEEF, txData.GetTxID())
+		} else if !txData.Unmined() {
+			continue
+		}
+		for _, input := range txData.Transaction.Inputs {
+			parentID := input.PreviousTxIDStr()
+			var parent *beef.TxData
+			for _, tx := range dBeef.Transactions {
+				if tx.Transaction != nil && tx.GetTxID() == parentID {
+					parent = tx
+					break
+				}
+			}
+			if parent == nil {
+				return fmt.Errorf("%w: missing parent transaction %s", ErrInvalidBEEF, parentID)
+			} else if int(input.PreviousTxOutIndex) >= len(parent.Transaction.Outputs) {
+				return fmt.Errorf(
+					"%w: missing output %d of parent transaction %s", ErrInvalidBEEF, input.PreviousTxOutIndex, parentID,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// checkBEEFBUMPs will check that each mined transaction is a txid leaf of its BUMP
+func checkBEEFBUMPs(dBeef *beef.DecodedBEEF) error {
+	for _, txData := range dBeef.Transactions {
+		if txData.Unmined() {
+			continue
+		}
+
+		txID := txData.GetTxID()
+		bumpIndex := uint64(*txData.BumpIndex)
+		if bumpIndex >= uint64(len(dBeef.BUMPs)) || dBeef.BUMPs[bumpIndex] == nil ||
+			len(dBeef.BUMPs[bumpIndex].Path) == 0 {
+			return fmt.Errorf("%w: missing BUMP %d of transaction %s", ErrInvalidBEEF, bumpIndex, txID)
+		}
+
+		found := false
+		for _, leaf := range dBeef.BUMPs[bumpIndex].Path[0] {
+			if leaf.TxId && leaf.Hash == txID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: transaction %s is not in its BUMP %d", ErrInvalidBEEF, txID, bumpIndex)
+		}
+	}
+	return nil
+}
+
+// checkBEEFFee will check that the subject transaction pays at least the fee unit
+func checkBEEFFee(dBeef *beef.DecodedBEEF, feeUnit *utils.FeeUnit) error {
+	if feeUnit == nil || feeUnit.IsZero() || !feeUnit.IsValid() {
+		return nil
+	}
+
+	tx := dBeef.GetLatestTx()
+	inputsValue := uint64(0)
+	for _, input := range tx.Inputs {
+		parentID := input.PreviousTxIDStr()
+		found := false
+		for _, parent := range dBeef.Transactions {
+			if parent.GetTxID() == parentID && int(input.PreviousTxOutIndex) < len(parent.Transaction.Outputs) {
+				inputsValue += parent.Transaction.Outputs[input.PreviousTxOutIndex].Satoshis
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: missing parent transaction %s", ErrInvalidBEEF, parentID)
+		}
+	}
+
+	outputsValue := tx.TotalOutputSatoshis()
+	if inputsValue < outputsValue {
+		return fmt.Errorf("%w: outputs exceed inputs", ErrBEEFFeeTooLow)
+	}
+
+	fee := inputsValue - outputsValue
+	requiredFee := uint64(math.Ceil(float64(tx.Size()) * (float64(feeUnit.Satoshis) / float64(feeUnit.Bytes))))
+	if fee < requiredFee {
+		return fmt.Errorf("%w: paid %d, required %d", ErrBEEFFeeTooLow, fee, requiredFee)
+	}
+
+	return nil
+}
+
+// recordBEEFTransaction will record the verified subject transaction and save its ancestors
+func recordBEEFTransaction(ctx context.Context, c ClientInterface, dBeef *beef.DecodedBEEF,
+	opts ...ModelOps,
+) (*Transaction, error) {
+	rts, err := getIncomingTxRecordStrategy(ctx, c, dBeef.GetLatestTx().String())
+	if err != nil {
+		return nil, err
+	}
+	if err = rts.Validate(); err != nil {
+		return nil, err
+	}
+
+	rts.ForceBroadcast(true)
+	rts.FailOnBroadcastError(true)
+
+	transaction, err := recordTransaction(ctx, c, rts, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// ancestors of an internal transaction are already known
+	if reflect.TypeOf(rts) == reflect.TypeOf(&externalIncomingTx{}) {
+		saveBEEFTxInputs(ctx, c, dBeef)
+	}
+
+	return transaction, nil
+}
@@ -0,0 +1,235 @@
+package bux
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/BuxOrg/bux/chainstate"
+	"github.com/BuxOrg/bux/utils"
+	"github.com/bitcoin-sv/go-paymail/beef"
+	"github.com/libsv/go-bk/bec"
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chainStateMerkleRootsInvalid is a chainstate where all the merkle roots are invalid
+type chainStateMerkleRootsInvalid struct {
+	chainStateEverythingOnChain
+}
+
+func (c *chainStateMerkleRootsInvalid) VerifyMerkleRoots(context.Context, []chainstate.MerkleRootConfirmationRequestItem) error {
+	return errors.New("invalid merkle roots")
+}
+
+// createTestBEEF will create a BEEF with a mined parent and a signed subject transaction paying to the locking script
+func createTestBEEF(t *testing.T, lockingScript string, satoshis, fee uint64) (string, *bt.Tx) {
+	bumps, txs := createTestBEEFTxs(t, lockingScript, satoshis, fee)
+	beefHex, err := toBeefHex(bumps, txs)
+	require.NoError(t, err)
+
+	return beefHex, txs[1]
+}
+
+// createTestBEEFTxs will create a mined parent (with its BUMP) and a signed subject transaction paying to the locking script
+func createTestBEEFTxs(t *testing.T, lockingScript string, satoshis, fee uint64) (BUMPs, []*bt.Tx) {
+	privateKey, err := bec.NewPrivateKey(bec.S256())
+	require.NoError(t, err)
+
+	parentScript, err := bscript.NewP2PKHFromPubKeyEC(privateKey.PubKey())
+	require.NoError(t, err)
+
+	parent := bt.NewTx()
+	require.NoError(t, parent.From(testTxScriptSigID, 0, testTxScriptSigOut, satoshis+fee+100))
+	require.NoError(t, parent.AddP2PKHOutputFromScript(parentScript, satoshis+fee))
+
+	otherHash, err := utils.RandomHex(32)
+	require.NoError(t, err)
+
+	child := bt.NewTx()
+	require.NoError(t, child.From(parent.TxID(), 0, parentScript.String(), satoshis+fee))
+	childScript, err := bscript.NewFromHexString(lockingScript)
+	require.NoError(t, err)
+	child.AddOutput(&bt.Output{Satoshis: satoshis, LockingScript: childScript})
+	require.NoError(t, child.FillAllInputs(context.Background(), &account{PrivateKey: privateKey}))
+
+	return BUMPs{{
+		BlockHeight: 800000,
+		Path: [][]BUMPLeaf{{
+			{Offset: 0, Hash: parent.TxID(), TxID: true},
+			{Offset: 1, Hash: otherHash},
+		}},
+	}}, []*bt.Tx{parent, child}
+}
+
+// createTestBEEFDestination will create a destination for a new xPub and return its locking script
+func createTestBEEFDestination(ctx context.Context, t *testing.T, client ClientInterface) string {
+	_, _, rawXPub := CreateNewXPub(ctx, t, client)
+	destination, err := client.NewDestination(ctx, rawXPub, utils.ChainExternal, utils.ScriptTypePubKeyHash)
+	require.NoError(t, err)
+
+	return destination.LockingScript
+}
+
+// TestClient_RecordBEEFTransaction will test the method RecordBEEFTransaction()
+func TestClient_RecordBEEFTransaction(t *testing.T) {
+	t.Run("valid beef", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+			WithCustomChainstate(&chainStateEverythingOnChain{}))
+		defer deferMe()
+
+		beefHex, subject := createTestBEEF(t, createTestBEEFDestination(ctx, t, client), 10000, 100)
+
+		transaction, err := client.RecordBEEFTransaction(ctx, beefHex)
+		require.NoError(t, err)
+		assert.Equal(t, subject.TxID(), transaction.ID)
+		assert.Equal(t, uint64(10000), transaction.TotalValue)
+	})
+
+	t.Run("valid atomic beef", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+			WithCustomChainstate(&chainStateEverythingOnChain{}))
+		defer deferMe()
+
+		bumps, txs := createTestBEEFTxs(t, createTestBEEFDestination(ctx, t, client), 10000, 100)
+		beefHex, err := toAtomicBeefHex(beefVersion1, bumps, txs, nil)
+		require.NoError(t, err)
+
+		var transaction *Transaction
+		transaction, err = client.RecordBEEFTransaction(ctx, beefHex)
+		require.NoError(t, err)
+		assert.Equal(t, txs[1].TxID(), transaction.ID)
+	})
+
+	t.Run("beef v2 with a known ancestor", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+			WithCustomChainstate(&chainStateEverythingOnChain{}))
+		defer deferMe()
+
+		bumps, txs := createTestBEEFTxs(t, createTestBEEFDestination(ctx, t, client), 10000, 100)
+		beefHex, err := toBeefV2Hex(nil, txs, []string{txs[0].TxID()})
+		require.NoError(t, err)
+
+		// the ancestor is unknown
+		_, err = client.RecordBEEFTransaction(ctx, beefHex)
+		require.ErrorIs(t, err, ErrInvalidBEEF)
+
+		parent, err := txFromHex(txs[0].String(), append(client.DefaultModelOptions(), New())...)
+		require.NoError(t, err)
+		parent.BUMP = *bumps[0]
+		parent.BlockHeight = bumps[0].BlockHeight
+		require.NoError(t, parent.Save(ctx))
+
+		var transaction *Transaction
+		transaction, err = client.RecordBEEFTransaction(ctx, beefHex)
+		require.NoError(t, err)
+		assert.Equal(t, txs[1].TxID(), transaction.ID)
+	})
+
+	t.Run("invalid beef", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+			WithCustomChainstate(&chainStateEverythingOnChain{}))
+		defer deferMe()
+
+		_, err := client.RecordBEEFTransaction(ctx, "0100beef00")
+		require.ErrorIs(t, err, ErrInvalidBEEF)
+
+		_, err = client.RecordBEEFTransaction(ctx, "invalid")
+		require.ErrorIs(t, err, ErrInvalidBEEF)
+
+		_, err = client.RecordBEEFTransaction(ctx, "0200beefffffffffffffffff7f")
+		require.ErrorIs(t, err, ErrInvalidBEEF)
+	})
+
+	t.Run("missing parent output", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+			WithCustomChainstate(&chainStateEverythingOnChain{}))
+		defer deferMe()
+
+		bumps, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+		txs[1].Inputs[0].PreviousTxOutIndex = 5
+		beefHex, err := toBeefHex(bumps, txs)
+		require.NoError(t, err)
+
+		_, err = client.RecordBEEFTransaction(ctx, beefHex)
+		require.ErrorIs(t, err, ErrInvalidBEEF)
+	})
+
+	t.Run("mined subject transaction", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+			WithCustomChainstate(&chainStateEverythingOnChain{}))
+		defer deferMe()
+
+		bumps, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+		bumps[0].Path[0] = append(bumps[0].Path[0], BUMPLeaf{Offset: 2, Hash: txs[1].TxID(), TxID: true})
+		beefHex, err := toBeefHex(bumps, txs)
+		require.NoError(t, err)
+
+		_, err = client.RecordBEEFTransaction(ctx, beefHex)
+		require.ErrorIs(t, err, ErrInvalidBEEF)
+
+		_, err = client.GetTransaction(ctx, "", txs[1].TxID())
+		require.ErrorIs(t, err, ErrMissingTransaction)
+	})
+
+	t.Run("mined transaction not in its BUMP", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+			WithCustomChainstate(&chainStateEverythingOnChain{}))
+		defer deferMe()
+
+		// an unrelated transaction is only a sibling hash of the BUMP (not a txid leaf)
+		bumps, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+		_, others := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+		bumps[0].Path[0][1].Hash = others[0].TxID()
+		beefHex, err := toBeefHex(bumps, []*bt.Tx{others[0], txs[0], txs[1]})
+		require.NoError(t, err)
+
+		_, err = client.RecordBEEFTransaction(ctx, beefHex)
+		require.ErrorIs(t, err, ErrInvalidBEEF)
+
+		_, err = client.GetTransaction(ctx, "", others[0].TxID())
+		require.ErrorIs(t, err, ErrMissingTransaction)
+	})
+
+	t.Run("invalid merkle roots", func(t *testing.T) {
+		ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+			WithCustomChainstate(&chainStateMerkleRootsInvalid{}))
+		defer deferMe()
+
+		beefHex, subject := createTestBEEF(t, testLockingScript, 10000, 100)
+
+		_, err := client.RecordBEEFTransaction(ctx, beefHex)
+		require.ErrorIs(t, err, ErrBEEFVerificationFailed)
+
+		_, err = client.GetTransaction(ctx, "", subject.TxID())
+		require.ErrorIs(t, err, ErrMissingTransaction)
+	})
+}
+
+// Test_checkBEEFFee will test the method checkBEEFFee()
+func Test_checkBEEFFee(t *testing.T) {
+	feeUnit := &utils.FeeUnit{Satoshis: 1, Bytes: 1}
+
+	t.Run("enough fee", func(t *testing.T) {
+		beefHex, _ := createTestBEEF(t, testLockingScript, 10000, 1000)
+		dBeef, err := beef.DecodeBEEF(beefHex)
+		require.NoError(t, err)
+		require.NoError(t, checkBEEFFee(dBeef, feeUnit))
+	})
+
+	t.Run("fee too low", func(t *testing.T) {
+		beefHex, _ := createTestBEEF(t, testLockingScript, 10000, 10)
+		dBeef, err := beef.DecodeBEEF(beefHex)
+		require.NoError(t, err)
+		require.ErrorIs(t, checkBEEFFee(dBeef, feeUnit), ErrBEEFFeeTooLow)
+	})
+
+	t.Run("no fee unit", func(t *testing.T) {
+		beefHex, _ := createTestBEEF(t, testLockingScript, 10000, 0)
+		dBeef, err := beef.DecodeBEEF(beefHex)
+		require.NoError(t, err)
+		require.NoError(t, checkBEEFFee(dBeef, nil))
+	})
+}
@@ -167,7 +167,7 @@ func _getBroadcastSyncStatus(tx *Transaction) SyncStatus {
 
 	for _, o := range outputs {
 		if o.PaymailP4 != nil {
-			if o.PaymailP4.Format == BeefPaymailPayloadFormat {
+			if o.PaymailP4.Format.isBeef() {
 				broadcast = SyncStatusSkipped // postpone broadcasting if tx contains outputs in BEEF
 
 				break
@@ -175,6 +175,13 @@ func _getBroadcastSyncStatus(tx *Transaction) SyncStatus {
 		}
 	}
 
+	// delay broadcasting until the broadcast time (see SyncConfig.DelayToBroadcast)
+	// the broadcast time is only set if broadcasting is enabled (see newSyncTransaction)
+	if sync := tx.draftTransaction.Configuration.Sync; broadcast == SyncStatusReady && sync != nil &&
+		sync.Broadcast && sync.DelayToBroadcast > 0 {
+		broadcast = SyncStatusScheduled
+	}
+
 	return broadcast
 }
 
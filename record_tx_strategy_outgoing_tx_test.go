@@ -0,0 +1,46 @@
+package bux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_getBroadcastSyncStatus will test the method _getBroadcastSyncStatus()
+func Test_getBroadcastSyncStatus(t *testing.T) {
+	newTx := func(sync *SyncConfig, outputs ...*TransactionOutput) *Transaction {
+		if len(outputs) == 0 {
+			outputs = []*TransactionOutput{{To: "1A1PjKqjWMNBzTVdcBru27EV1PHcXWc63W", Satoshis: 1000}}
+		}
+		return &Transaction{draftTransaction: &DraftTransaction{
+			Configuration: TransactionConfig{Outputs: outputs, Sync: sync},
+		}}
+	}
+
+	t.Run("broadcast immediately", func(t *testing.T) {
+		assert.Equal(t, SyncStatusReady, _getBroadcastSyncStatus(newTx(&SyncConfig{Broadcast: true})))
+		assert.Equal(t, SyncStatusReady, _getBroadcastSyncStatus(newTx(nil)))
+	})
+
+	t.Run("delayed broadcast is scheduled", func(t *testing.T) {
+		assert.Equal(t, SyncStatusScheduled, _getBroadcastSyncStatus(
+			newTx(&SyncConfig{Broadcast: true, DelayToBroadcast: time.Hour}),
+		))
+	})
+
+	t.Run("delay without broadcast is not scheduled", func(t *testing.T) {
+		tx := newTx(&SyncConfig{DelayToBroadcast: time.Hour})
+		assert.Equal(t, SyncStatusReady, _getBroadcastSyncStatus(tx))
+
+		syncTx := newSyncTransaction("", tx.draftTransaction.Configuration.Sync)
+		assert.False(t, syncTx.BroadcastAt.Valid)
+	})
+
+	t.Run("BEEF outputs postpone the broadcast", func(t *testing.T) {
+		assert.Equal(t, SyncStatusSkipped, _getBroadcastSyncStatus(newTx(
+			&SyncConfig{Broadcast: true, DelayToBroadcast: time.Hour},
+			&TransactionOutput{PaymailP4: &PaymailP4{Format: BeefPaymailPayloadFormat}},
+		)))
+	})
+}
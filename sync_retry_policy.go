@@ -0,0 +1,99 @@
+package bux
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/BuxOrg/bux/chainstate"
+)
+
+// SyncErrorClassNotFound is the error class when the transaction is not found by the providers (yet)
+const SyncErrorClassNotFound = "not_found"
+
+// maxSyncRetryDelay is the upper bound of the delay (an unlimited MaxDelay would overflow the duration)
+const maxSyncRetryDelay = 30 * 24 * time.Hour
+
+// SyncRetryPolicy is the retry policy of the failed sync transactions (broadcast, sync and p2p)
+//
+// The delay of the next attempt grows exponentially: InitialDelay * BackoffFactor^(attempts-1),
+// capped at MaxDelay, plus a random jitter. Exhausted sync transactions are moved to the dead-letter status.
+type SyncRetryPolicy struct {
+	BackoffFactor float64                  `json:"backoff_factor" toml:"backoff_factor" yaml:"backoff_factor"` // Growth of the delay per attempt (1: constant)
+	InitialDelay  time.Duration            `json:"initial_delay" toml:"initial_delay" yaml:"initial_delay"`    // Delay after the first failed attempt
+	Jitter        float64                  `json:"jitter" toml:"jitter" yaml:"jitter"`                         // Max random part added to the delay (IE: 0.2 = up to 20%)
+	MaxAttempts   uint32                   `json:"max_attempts" toml:"max_attempts" yaml:"max_attempts"`       // Max attempts before the dead-letter status (0: unlimited)
+	MaxDelay      time.Duration            `json:"max_delay" toml:"max_delay" yaml:"max_delay"`                // Max delay between attempts (0: up to 30 days)
+	Rules         map[string]SyncRetryRule `json:"rules" toml:"rules" yaml:"rules"`                            // Rules per error class (IE: chainstate.ErrorClassRejected)
+}
+
+// SyncRetryRule overrides the retry policy for an error class (zero values use the policy)
+type SyncRetryRule struct {
+	InitialDelay time.Duration `json:"initial_delay" toml:"initial_delay" yaml:"initial_delay"` // Delay after the first failed attempt
+	MaxAttempts  uint32        `json:"max_attempts" toml:"max_attempts" yaml:"max_attempts"`    // Max attempts before the dead-letter status
+}
+
+// DefaultSyncRetryPolicy will return the default retry policy
+//
+// Transient failures (network, provider, timeout, not found) are retried forever with a delay of up to an hour,
+// rejected transactions are moved to the dead-letter status after 10 attempts
+func DefaultSyncRetryPolicy() *SyncRetryPolicy {
+	return &SyncRetryPolicy{
+		BackoffFactor: 2,
+		InitialDelay:  30 * time.Second,
+		Jitter:        0.2,
+		MaxDelay:      time.Hour,
+		Rules: map[string]SyncRetryRule{
+			chainstate.ErrorClassRejected: {MaxAttempts: 10},
+		},
+	}
+}
+
+// nextAttempt will return the time of the next attempt after the given number of failed attempts
+//
+// False is returned if the attempts are exhausted for the error class
+func (p *SyncRetryPolicy) nextAttempt(attempts uint32, errorClass string, now time.Time) (time.Time, bool) {
+	initialDelay, maxAttempts := p.InitialDelay, p.MaxAttempts
+	if rule, ok := p.Rules[errorClass]; ok {
+		if rule.InitialDelay > 0 {
+			initialDelay = rule.InitialDelay
+		}
+		if rule.MaxAttempts > 0 {
+			maxAttempts = rule.MaxAttempts
+		}
+	}
+	if maxAttempts > 0 && attempts >= maxAttempts {
+		return time.Time{}, false
+	} else if attempts == 0 {
+		attempts = 1
+	}
+
+	// Exponential backoff (capped)
+	factor := p.BackoffFactor
+	if factor < 1 {
+		factor = 1
+	}
+	delay := float64(initialDelay) * math.Pow(factor, float64(attempts-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	// Random jitter, spreads the retries of the transactions which failed together
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * rand.Float64() //nolint:gosec // no need for a secure random
+	}
+	if delay > float64(maxSyncRetryDelay) {
+		delay = float64(maxSyncRetryDelay)
+	}
+
+	return now.Add(time.Duration(delay)), true
+}
+
+// syncErrorClass will return the error class of a failed sync attempt
+func syncErrorClass(err error) string {
+	if errors.Is(err, chainstate.ErrTransactionNotFound) {
+		return SyncErrorClassNotFound
+	}
+	return chainstate.ClassifyBroadcastError(err)
+}
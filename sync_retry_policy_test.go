@@ -0,0 +1,81 @@
+package bux
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BuxOrg/bux/chainstate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSyncRetryPolicy_nextAttempt will test the method nextAttempt()
+func TestSyncRetryPolicy_nextAttempt(t *testing.T) {
+	now := time.Now().UTC()
+	policy := &SyncRetryPolicy{
+		BackoffFactor: 2,
+		InitialDelay:  time.Minute,
+		MaxAttempts:   5,
+		MaxDelay:      5 * time.Minute,
+		Rules: map[string]SyncRetryRule{
+			chainstate.ErrorClassRejected: {InitialDelay: 3 * time.Minute, MaxAttempts: 2},
+		},
+	}
+
+	t.Run("exponential backoff", func(t *testing.T) {
+		for attempts, delay := range map[uint32]time.Duration{
+			1: time.Minute,
+			2: 2 * time.Minute,
+			3: 4 * time.Minute,
+			4: 5 * time.Minute, // capped
+		} {
+			next, ok := policy.nextAttempt(attempts, chainstate.ErrorClassNetwork, now)
+			require.True(t, ok)
+			assert.Equal(t, now.Add(delay), next)
+		}
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		_, ok := policy.nextAttempt(5, chainstate.ErrorClassNetwork, now)
+		assert.False(t, ok)
+	})
+
+	t.Run("error class rule", func(t *testing.T) {
+		next, ok := policy.nextAttempt(1, chainstate.ErrorClassRejected, now)
+		require.True(t, ok)
+		assert.Equal(t, now.Add(3*time.Minute), next)
+
+		_, ok = policy.nextAttempt(2, chainstate.ErrorClassRejected, now)
+		assert.False(t, ok)
+	})
+
+	t.Run("jitter", func(t *testing.T) {
+		next, ok := (&SyncRetryPolicy{InitialDelay: time.Minute, Jitter: 0.5}).nextAttempt(
+			10, chainstate.ErrorClassNetwork, now,
+		)
+		require.True(t, ok)
+		assert.GreaterOrEqual(t, next.Sub(now), time.Minute)
+		assert.LessOrEqual(t, next.Sub(now), 90*time.Second)
+	})
+
+	t.Run("unlimited max delay does not overflow", func(t *testing.T) {
+		unlimited := &SyncRetryPolicy{BackoffFactor: 2, InitialDelay: time.Minute, Jitter: 0.2}
+		for _, attempts := range []uint32{40, 64, 1000, 1<<32 - 1} {
+			next, ok := unlimited.nextAttempt(attempts, chainstate.ErrorClassNetwork, now)
+			require.True(t, ok)
+			assert.Equal(t, now.Add(maxSyncRetryDelay), next)
+		}
+	})
+
+	t.Run("default policy retries forever", func(t *testing.T) {
+		_, ok := DefaultSyncRetryPolicy().nextAttempt(1000, SyncErrorClassNotFound, now)
+		assert.True(t, ok)
+	})
+}
+
+// Test_syncErrorClass will test the method syncErrorClass()
+func Test_syncErrorClass(t *testing.T) {
+	assert.Equal(t, SyncErrorClassNotFound, syncErrorClass(chainstate.ErrTransactionNotFound))
+	assert.Equal(t, chainstate.ErrorClassTimeout, syncErrorClass(errors.New("context deadline exceeded")))
+}
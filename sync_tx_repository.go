@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/hex"
 	"errors"
+	"time"
 
 	"github.com/libsv/go-bt/v2"
 	"github.com/mrz1836/go-datastore"
@@ -60,9 +61,7 @@ func getTransactionsToBroadcast(ctx context.Context, queryParams *datastore.Quer
 	// Get the records by status
 	scTxs, err := _getSyncTransactionsByConditions(
 		ctx,
-		map[string]interface{}{
-			broadcastStatusField: SyncStatusReady.String(),
-		},
+		dueConditions(broadcastStatusField, time.Now().UTC()),
 		queryParams, opts...,
 	)
 	if err != nil {
@@ -101,6 +100,20 @@ func getTransactionsToBroadcast(ctx context.Context, queryParams *datastore.Quer
 	return res, nil
 }
 
+// getScheduledBroadcasts will get the sync transactions with a scheduled broadcast which is due
+func getScheduledBroadcasts(ctx context.Context, dueAt time.Time, opts ...ModelOps) ([]*SyncTransaction, error) {
+	return _getSyncTransactionsByConditions(
+		ctx,
+		map[string]interface{}{
+			broadcastStatusField: SyncStatusScheduled.String(),
+			broadcastAtField: map[string]interface{}{
+				conditionLessThanOrEqual: dueAt,
+			},
+		},
+		nil, opts...,
+	)
+}
+
 // getTransactionsToSync will get the sync transactions to sync
 func getTransactionsToSync(ctx context.Context, queryParams *datastore.QueryParams,
 	opts ...ModelOps,
@@ -108,19 +121,95 @@ func getTransactionsToSync(ctx context.Context, queryParams *datastore.QueryPara
 	// Get the records by status
 	txs, err := _getSyncTransactionsByConditions(
 		ctx,
-		map[string]interface{}{
-			syncStatusField: SyncStatusReady.String(),
-		},
+		dueConditions(syncStatusField, time.Now().UTC()),
 		queryParams, opts...,
 	)
 	if err != nil {
 		return nil, err
 	}
+
+	// hydrate (the xpubs are used for the scheduling)
+	for _, syncTx := range txs {
+		if syncTx.transaction, err = getTransactionByID(
+			ctx, "", syncTx.ID, opts...,
+		); err != nil {
+			return nil, err
+		}
+	}
 	return txs, nil
 }
 
+// getSyncTransactions will get the sync transactions using the given conditions
+func getSyncTransactions(ctx context.Context, conditions *map[string]interface{},
+	queryParams *datastore.QueryParams, opts ...ModelOps,
+) ([]*SyncTransaction, error) {
+	modelItems := make([]*SyncTransaction, 0)
+	if err := getModelsByConditions(
+		ctx, ModelSyncTransaction, &modelItems, nil, conditions, queryParams, opts...,
+	); err != nil {
+		return nil, err
+	}
+
+	// Set the client (for saving the models)
+	for _, item := range modelItems {
+		item.enrich(ModelSyncTransaction, opts...)
+	}
+	return modelItems, nil
+}
+
+// getSyncTransactionsCount will get a count of the sync transactions using the given conditions
+func getSyncTransactionsCount(ctx context.Context, conditions *map[string]interface{},
+	opts ...ModelOps,
+) (int64, error) {
+	return getModelCountByConditions(ctx, ModelSyncTransaction, SyncTransaction{}, nil, conditions, opts...)
+}
+
+// getSyncTransactionsPerStatus will get the count of the sync transactions per status of the given status field
+func getSyncTransactionsPerStatus(ctx context.Context, statusField string,
+	opts ...ModelOps,
+) (map[string]int64, error) {
+	modelItems := make([]*SyncTransaction, 0)
+	results, err := getModelsAggregateByConditions(
+		ctx, ModelSyncTransaction, &modelItems, nil, nil, statusField, opts...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(results))
+	for status, count := range results {
+		if pointer, ok := count.(*interface{}); ok && pointer != nil {
+			count = *pointer // SQL engines scan into pointers
+		}
+		switch value := count.(type) {
+		case int64:
+			counts[status] = value
+		case int32:
+			counts[status] = int64(value)
+		case int:
+			counts[status] = int64(value)
+		case float64:
+			counts[status] = int64(value)
+		}
+	}
+	return counts, nil
+}
+
 /*** /public unexported funcs ***/
 
+// dueConditions will return the conditions of the sync transactions which are ready and due at now
+//
+// The next attempt is always set (see newSyncTransaction) and the time bound stays at the top level,
+// Mongo converts the nested conditions ($and, $or) to JSON which turns the time into a string
+func dueConditions(statusField string, now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		statusField: SyncStatusReady.String(),
+		nextAttemptField: map[string]interface{}{
+			conditionLessThanOrEqual: now,
+		},
+	}
+}
+
 // getTransactionsToSync will get the sync transactions to sync
 func _getSyncTransactionsByConditions(ctx context.Context, conditions map[string]interface{},
 	queryParams *datastore.QueryParams, opts ...ModelOps,
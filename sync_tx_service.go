@@ -6,8 +6,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"runtime"
-	"sync"
 	"time"
 
 	"github.com/BuxOrg/bux/chainstate"
@@ -36,15 +34,17 @@ func processSyncTransactions(ctx context.Context, maxTransactions int, opts ...M
 		return nil
 	}
 
-	for index := range records {
-		if err = _syncTxDataFromChain(
-			ctx, records[index], nil,
-		); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	// Process the transactions with the workers, fairly across the xpubs (claimed first, see claimSyncTransaction)
+	return processSyncWorkerPool(
+		ctx, records[0].Client().SyncWorkers(), records, false,
+		func(ctx context.Context, syncTx *SyncTransaction) error {
+			claimed, err := claimSyncTransaction(ctx, syncTx, syncActionSync)
+			if err != nil || claimed == nil {
+				return err
+			}
+			return _syncTxDataFromChain(ctx, claimed, claimed.transaction)
+		},
+	)
 }
 
 // processBroadcastTransactions will process sync transaction records
@@ -56,6 +56,11 @@ func processBroadcastTransactions(ctx context.Context, maxTransactions int, opts
 		SortDirection: datastore.SortAsc,
 	}
 
+	// The scheduled broadcasts which are due are ready to broadcast
+	if err := readyScheduledBroadcasts(ctx, opts...); err != nil {
+		return err
+	}
+
 	// Get maxTransactions records, grouped by xpub
 	snTxs, err := getTransactionsToBroadcast(ctx, queryParams, opts...)
 	if err != nil {
@@ -64,34 +69,18 @@ func processBroadcastTransactions(ctx context.Context, maxTransactions int, opts
 		return nil
 	}
 
-	// Process the transactions per xpub, in parallel
-	txsByXpub := _groupByXpub(snTxs)
-
-	// we limit the number of concurrent broadcasts to the number of cpus*2, since there is lots of IO wait
-	limit := make(chan bool, runtime.NumCPU()*2)
-	wg := new(sync.WaitGroup)
-
-	for xPubID := range txsByXpub {
-		limit <- true // limit the number of routines running at the same time
-		wg.Add(1)
-		go func(xPubID string) {
-			defer wg.Done()
-			defer func() { <-limit }()
-
-			for _, tx := range txsByXpub[xPubID] {
-				if err = broadcastSyncTransaction(
-					ctx, tx,
-				); err != nil {
-					tx.Client().Logger().Error().
-						Str("txID", tx.ID).
-						Str("xpubID", xPubID).
-						Msgf("error running broadcast tx: %s", err.Error())
-					return // stop processing transactions for this xpub if we found an error
-				}
+	// Process the transactions with the workers, fairly across the xpubs (claimed first, see claimSyncTransaction)
+	// the errors are logged, the next transactions of a xpub are not processed after an error
+	_ = processSyncWorkerPool(
+		ctx, snTxs[0].Client().SyncWorkers(), snTxs, true,
+		func(ctx context.Context, syncTx *SyncTransaction) error {
+			claimed, err := claimSyncTransaction(ctx, syncTx, syncActionBroadcast)
+			if err != nil || claimed == nil {
+				return err
 			}
-		}(xPubID)
-	}
-	wg.Wait()
+			return broadcastSyncTransaction(ctx, claimed)
+		},
+	)
 
 	return nil
 }
@@ -110,14 +99,10 @@ func broadcastSyncTransaction(ctx context.Context, syncTx *SyncTransaction) erro
 		return err
 	}
 
-	// Get the transaction HEX
-	var txHex string
-	if syncTx.transaction != nil && syncTx.transaction.Hex != "" {
-		// the transaction has already been retrieved and added to the syncTx object, just use that
-		txHex = syncTx.transaction.Hex
-	} else {
-		// else get hex from DB
-		var transaction *Transaction
+	// Get the transaction
+	transaction := syncTx.transaction
+	if transaction == nil || transaction.Hex == "" {
+		// the transaction has not been retrieved and added to the syncTx object, get it from DB
 		transaction, err = getTransactionByID(
 			ctx, "", syncTx.ID, syncTx.GetOptions(false)...,
 		)
@@ -128,16 +113,27 @@ func broadcastSyncTransaction(ctx context.Context, syncTx *SyncTransaction) erro
 		if transaction == nil {
 			return errors.New("transaction was expected but not found, using ID: " + syncTx.ID)
 		}
+	}
 
+	// Broadcast the transaction in Extended Format when the previous outputs are known (raw hex otherwise)
+	txHex, isEf := ToEfHex(ctx, transaction, syncTx.Client())
+	if !isEf {
 		txHex = transaction.Hex
 	}
 
-	// Broadcast
+	// Broadcast (using a specific miner if configured)
+	broadcastCtx := ctx
+	if len(syncTx.Configuration.Miner) > 0 {
+		broadcastCtx = chainstate.WithBroadcastProvider(
+			ctx, syncTx.Configuration.Miner, syncTx.Configuration.MinerFailover,
+		)
+	}
+
 	var provider string
 	if provider, err = syncTx.Client().Chainstate().Broadcast(
-		ctx, syncTx.ID, txHex, defaultBroadcastTimeout,
+		broadcastCtx, syncTx.ID, txHex, defaultBroadcastTimeout,
 	); err != nil {
-		_bailAndSaveSyncTransaction(ctx, syncTx, SyncStatusReady, syncActionBroadcast, provider, err.Error())
+		_retrySyncTransaction(ctx, syncTx, syncActionBroadcast, provider, err)
 		return err
 	}
 
@@ -146,6 +142,7 @@ func broadcastSyncTransaction(ctx context.Context, syncTx *SyncTransaction) erro
 
 	// Update the sync information
 	syncTx.BroadcastStatus = SyncStatusComplete
+	syncTx.resetAttempts()
 	syncTx.Results.LastMessage = message
 	syncTx.LastAttempt = customTypes.NullTime{
 		NullTime: sql.NullTime{
@@ -180,6 +177,22 @@ func broadcastSyncTransaction(ctx context.Context, syncTx *SyncTransaction) erro
 	return nil
 }
 
+// readyScheduledBroadcasts will set the scheduled broadcasts which are due as ready to broadcast
+func readyScheduledBroadcasts(ctx context.Context, opts ...ModelOps) error {
+	syncTxs, err := getScheduledBroadcasts(ctx, time.Now().UTC(), opts...)
+	if err != nil {
+		return err
+	}
+
+	for _, syncTx := range syncTxs {
+		syncTx.BroadcastStatus = SyncStatusReady
+		if err = syncTx.Save(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 /////////////////
 
 // _syncTxDataFromChain will process the sync transaction record, or save the failure
@@ -202,6 +215,11 @@ func _syncTxDataFromChain(ctx context.Context, syncTx *SyncTransaction, transact
 		return ErrMissingTransaction
 	}
 
+	// SPV only: no lookup, the transaction is only synced by the broadcast callback or if it already has its BUMP
+	if syncTx.Client().IsSPVOnlyEnabled() {
+		return _syncTxDataSPVOnly(ctx, syncTx, transaction)
+	}
+
 	// Find on-chain
 	var txInfo *chainstate.TransactionInfo
 	// only mAPI currently provides merkle proof, so QueryTransaction should be used here
@@ -213,9 +231,7 @@ func _syncTxDataFromChain(ctx context.Context, syncTx *SyncTransaction, transact
 				Str("txID", syncTx.ID).
 				Msgf("Transaction not found on-chain, will try again later")
 
-			_bailAndSaveSyncTransaction(
-				ctx, syncTx, SyncStatusReady, syncActionSync, "all", "transaction not found on-chain",
-			)
+			_retrySyncTransaction(ctx, syncTx, syncActionSync, "all", err)
 			return nil
 		}
 		return err
@@ -240,7 +256,18 @@ func processSyncTxSave(ctx context.Context, txInfo *chainstate.TransactionInfo,
 
 	transaction.setChainInfo(txInfo)
 
-	message := "transaction was found on-chain by " + chainstate.ProviderBroadcastClient
+	// SPV only: the BUMP is not trusted without the header service
+	if syncTx.Client().IsSPVOnlyEnabled() {
+		if err := _verifyBUMP(ctx, syncTx.Client(), transaction.BUMP); errors.Is(err, ErrBlockHeightNotSynced) {
+			_retrySyncTransaction(ctx, syncTx, syncActionSync, "spv", err)
+			return nil
+		} else if err != nil {
+			_bailAndSaveSyncTransaction(
+				ctx, syncTx, SyncStatusUnverifiable, syncActionSync, "spv", err.Error(),
+			)
+			return nil
+		}
+	}
 
 	if err := transaction.Save(ctx); err != nil {
 		_bailAndSaveSyncTransaction(
@@ -249,12 +276,58 @@ func processSyncTxSave(ctx context.Context, txInfo *chainstate.TransactionInfo,
 		return err
 	}
 
+	return _completeSyncTransaction(
+		ctx, syncTx, chainstate.ProviderBroadcastClient,
+		"transaction was found on-chain by "+chainstate.ProviderBroadcastClient,
+	)
+}
+
+// _syncTxDataSPVOnly will complete the sync of a transaction which has a (verified) BUMP,
+// otherwise the transaction is unverifiable until its broadcast callback is received
+func _syncTxDataSPVOnly(ctx context.Context, syncTx *SyncTransaction, transaction *Transaction) error {
+	if len(transaction.BUMP.Path) == 0 {
+		_bailAndSaveSyncTransaction(
+			ctx, syncTx, SyncStatusUnverifiable, syncActionSync, "spv", ErrTransactionUnverifiable.Error(),
+		)
+		return nil
+	}
+
+	// the local header store is not synced up to the block yet
+	if err := _verifyBUMP(ctx, syncTx.Client(), transaction.BUMP); errors.Is(err, ErrBlockHeightNotSynced) {
+		_retrySyncTransaction(ctx, syncTx, syncActionSync, "spv", err)
+		return nil
+	} else if err != nil {
+		_bailAndSaveSyncTransaction(
+			ctx, syncTx, SyncStatusUnverifiable, syncActionSync, "spv", err.Error(),
+		)
+		return nil
+	}
+
+	return _completeSyncTransaction(ctx, syncTx, "spv", "transaction BUMP was verified with the header service")
+}
+
+// _verifyBUMP will verify the merkle root of the BUMP with the header service
+func _verifyBUMP(ctx context.Context, client ClientInterface, bump BUMP) error {
+	merkleRoot, err := bump.calculateMerkleRoot()
+	if err != nil {
+		return err
+	}
+
+	return client.Chainstate().VerifyMerkleRoots(ctx, []chainstate.MerkleRootConfirmationRequestItem{{
+		MerkleRoot:  merkleRoot,
+		BlockHeight: bump.BlockHeight,
+	}})
+}
+
+// _completeSyncTransaction will mark the on-chain sync of the transaction as complete
+func _completeSyncTransaction(ctx context.Context, syncTx *SyncTransaction, provider, message string) error {
 	syncTx.SyncStatus = SyncStatusComplete
+	syncTx.resetAttempts()
 	syncTx.Results.LastMessage = message
 	syncTx.Results.Results = append(syncTx.Results.Results, &SyncResult{
 		Action:        syncActionSync,
 		ExecutedAt:    time.Now().UTC(),
-		Provider:      chainstate.ProviderBroadcastClient,
+		Provider:      provider,
 		StatusMessage: message,
 	})
 
@@ -295,9 +368,7 @@ func processP2PTransaction(ctx context.Context, tx *Transaction) error {
 	// Notify any P2P paymail providers associated to the transaction
 	var results []*SyncResult
 	if results, err = _notifyPaymailProviders(ctx, tx); err != nil {
-		_bailAndSaveSyncTransaction(
-			ctx, syncTx, SyncStatusReady, syncActionP2P, "", err.Error(),
-		)
+		_retrySyncTransaction(ctx, syncTx, syncActionP2P, "", err)
 		return err
 	}
 
@@ -309,6 +380,7 @@ func processP2PTransaction(ctx context.Context, tx *Transaction) error {
 
 	// Save the record
 	syncTx.P2PStatus = SyncStatusComplete
+	syncTx.resetAttempts()
 
 	// Update sync status to be ready now
 	if syncTx.SyncStatus == SyncStatusPending {
@@ -377,13 +449,7 @@ func _groupByXpub(scTxs []*SyncTransaction) map[string][]*SyncTransaction {
 
 	// group transactions by xpub and return including the tx itself
 	for _, tx := range scTxs {
-		xPubID := "" // fallback if we have no input xpubs
-		if len(tx.transaction.XpubInIDs) > 0 {
-			// use the first xpub for the grouping
-			// in most cases when we are broadcasting, there should be only 1 xpub in
-			xPubID = tx.transaction.XpubInIDs[0]
-		}
-
+		xPubID := tx.xPubID()
 		if txsByXpub[xPubID] == nil {
 			txsByXpub[xPubID] = make([]*SyncTransaction, 0)
 		}
@@ -393,6 +459,31 @@ func _groupByXpub(scTxs []*SyncTransaction) map[string][]*SyncTransaction {
 	return txsByXpub
 }
 
+// _retrySyncTransaction will save the failed attempt of a sync tx and schedule the next attempt (see SyncRetryPolicy)
+//
+// The sync tx is moved to the dead-letter status (with a notification) when the attempts are exhausted
+func _retrySyncTransaction(ctx context.Context, syncTx *SyncTransaction, action, provider string, err error) {
+	now := time.Now().UTC()
+	errorClass := syncErrorClass(err)
+	syncTx.Attempts++
+
+	next, ok := syncTx.Client().SyncRetryPolicy().nextAttempt(syncTx.Attempts, errorClass, now)
+	if !ok {
+		syncTx.Client().Logger().Warn().
+			Str("txID", syncTx.ID).
+			Str("action", action).
+			Msgf("sync transaction moved to dead-letter after %d attempts (%s): %s", syncTx.Attempts, errorClass, err.Error())
+
+		syncTx.NextAttempt = nextAttemptAt(now)
+		_bailAndSaveSyncTransaction(ctx, syncTx, SyncStatusDeadLetter, action, provider, err.Error())
+		notify(notifications.EventTypeDeadLetter, syncTx)
+		return
+	}
+
+	syncTx.NextAttempt = nextAttemptAt(next)
+	_bailAndSaveSyncTransaction(ctx, syncTx, SyncStatusReady, action, provider, err.Error())
+}
+
 // _bailAndSaveSyncTransaction will save the error message for a sync tx
 func _bailAndSaveSyncTransaction(ctx context.Context, syncTx *SyncTransaction, status SyncStatus,
 	action, provider, message string,
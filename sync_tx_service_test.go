@@ -0,0 +1,316 @@
+package bux
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/BuxOrg/bux/chainstate"
+	"github.com/BuxOrg/bux/tester/simchain"
+	broadcast_client_mock "github.com/bitcoin-sv/go-broadcast-client/broadcast/broadcast-client-mock"
+	"github.com/libsv/go-bc"
+	"github.com/libsv/go-p2p/chaincfg/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test_syncTxDataFromChain_SPVOnly will test the on-chain sync without transaction lookup
+func Test_syncTxDataFromChain_SPVOnly(t *testing.T) {
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+		WithCustomChainstate(&chainStateEverythingOnChain{}), WithSPVOnly())
+	defer deferMe()
+	require.True(t, client.IsSPVOnlyEnabled())
+
+	_, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+	opts := append(client.DefaultModelOptions(), New())
+
+	t.Run("unverifiable without BUMP", func(t *testing.T) {
+		tx, err := txFromHex(txs[0].String(), opts...)
+		require.NoError(t, err)
+		require.NoError(t, tx.Save(ctx))
+		syncTx := newSyncTransaction(tx.ID, &SyncConfig{SyncOnChain: true}, opts...)
+		require.NoError(t, syncTx.Save(ctx))
+
+		require.NoError(t, _syncTxDataFromChain(ctx, syncTx, tx))
+		assert.Equal(t, SyncStatusUnverifiable, syncTx.SyncStatus)
+		assert.Equal(t, ErrTransactionUnverifiable.Error(), syncTx.Results.LastMessage)
+
+		// the transaction was not queried
+		tx, err = client.GetTransaction(ctx, "", tx.ID)
+		require.NoError(t, err)
+		assert.Zero(t, tx.BlockHeight)
+
+		syncTx, err = GetSyncTransactionByTxID(ctx, tx.ID, client.DefaultModelOptions()...)
+		require.NoError(t, err)
+		assert.Equal(t, SyncStatusUnverifiable, syncTx.SyncStatus)
+	})
+
+	t.Run("complete with verified BUMP", func(t *testing.T) {
+		tx, err := txFromHex(txs[1].String(), opts...)
+		require.NoError(t, err)
+		tx.BUMP, _ = createTestBlockBUMPs(t, tx.ID)
+		tx.BlockHeight = tx.BUMP.BlockHeight
+		require.NoError(t, tx.Save(ctx))
+		syncTx := newSyncTransaction(tx.ID, &SyncConfig{SyncOnChain: true}, opts...)
+		require.NoError(t, syncTx.Save(ctx))
+
+		require.NoError(t, _syncTxDataFromChain(ctx, syncTx, tx))
+		assert.Equal(t, SyncStatusComplete, syncTx.SyncStatus)
+	})
+}
+
+// Test_syncTxDataFromChain_HeaderNotSynced will test the SPV sync when the local header store is behind
+func Test_syncTxDataFromChain_HeaderNotSynced(t *testing.T) {
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+		WithLocalHeaderStore(nil), WithSPVOnly())
+	defer deferMe()
+
+	_, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+	opts := append(client.DefaultModelOptions(), New())
+	tx, err := txFromHex(txs[0].String(), opts...)
+	require.NoError(t, err)
+	tx.BUMP, _ = createTestBlockBUMPs(t, tx.ID)
+	tx.BlockHeight = tx.BUMP.BlockHeight
+	require.NoError(t, tx.Save(ctx))
+	syncTx := newSyncTransaction(tx.ID, &SyncConfig{SyncOnChain: true}, opts...)
+	require.NoError(t, syncTx.Save(ctx))
+
+	// retried later (not unverifiable)
+	require.NoError(t, _syncTxDataFromChain(ctx, syncTx, tx))
+	assert.Equal(t, SyncStatusReady, syncTx.SyncStatus)
+	assert.Equal(t, uint32(1), syncTx.Attempts)
+	assert.Contains(t, syncTx.Results.LastMessage, ErrBlockHeightNotSynced.Error())
+}
+
+// Test_syncTransaction_SimChain will test the broadcast and the on-chain sync with the simulated chain
+func Test_syncTransaction_SimChain(t *testing.T) {
+	chain := simchain.New(simchain.WithFeeUnit(nil), simchain.WithStartHeight(800000))
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+		WithCustomChainstate(chain))
+	defer deferMe()
+
+	_, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+	opts := append(client.DefaultModelOptions(), New())
+	tx, err := txFromHex(txs[0].String(), opts...)
+	require.NoError(t, err)
+	require.NoError(t, tx.Save(ctx))
+	syncTx := newSyncTransaction(tx.ID, &SyncConfig{SyncOnChain: true, Broadcast: true}, opts...)
+	require.NoError(t, syncTx.Save(ctx))
+
+	require.NoError(t, broadcastSyncTransaction(ctx, syncTx))
+	assert.Equal(t, SyncStatusComplete, syncTx.BroadcastStatus)
+	assert.Equal(t, []string{tx.ID}, chain.Mempool())
+
+	// not mined yet
+	require.NoError(t, _syncTxDataFromChain(ctx, syncTx, nil))
+	assert.Equal(t, SyncStatusReady, syncTx.SyncStatus)
+
+	chain.MineBlock()
+	require.NoError(t, _syncTxDataFromChain(ctx, syncTx, nil))
+	assert.Equal(t, SyncStatusComplete, syncTx.SyncStatus)
+
+	tx, err = client.GetTransaction(ctx, "", tx.ID)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(800000), tx.BlockHeight)
+	require.NoError(t, _verifyBUMP(ctx, client, tx.BUMP))
+
+	// the block is orphaned
+	_, err = chain.Reorg(1)
+	require.NoError(t, err)
+	chain.MineBlock()
+	require.ErrorIs(t, _verifyBUMP(ctx, client, tx.BUMP), simchain.ErrMerkleRootNotFound)
+}
+
+// Test_syncTxDataFromChain_Node will test the on-chain sync with a bitcoind-compatible node (regtest)
+func Test_syncTxDataFromChain_Node(t *testing.T) {
+	_, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+	txID := txs[0].TxID()
+	blockTxIDs := []string{txs[1].TxID(), txID, testTxID}
+	blockHash := "0000000000000000000000000000000000000000000000000000000000000001"
+
+	// the node knows the transaction & its block
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var request struct {
+			Method string `json:"method"`
+		}
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&request))
+
+		var result interface{}
+		switch request.Method {
+		case "getrawtransaction":
+			result = map[string]interface{}{
+				"blockhash": blockHash, "blockheight": 800000, "confirmations": 1, "txid": txID,
+			}
+		case "getblock":
+			result = map[string]interface{}{"hash": blockHash, "height": 800000, "tx": blockTxIDs}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+	defer server.Close()
+
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+		WithNetwork(chainstate.RegTestNet), WithNode(server.URL, ""))
+	defer deferMe()
+	require.Equal(t, chainstate.ProviderNode, client.Chainstate().(*chainstate.Client).ActiveProvider())
+
+	opts := append(client.DefaultModelOptions(), New())
+	tx, err := txFromHex(txs[0].String(), opts...)
+	require.NoError(t, err)
+	require.NoError(t, tx.Save(ctx))
+	syncTx := newSyncTransaction(tx.ID, &SyncConfig{SyncOnChain: true}, opts...)
+	require.NoError(t, syncTx.Save(ctx))
+
+	require.NoError(t, _syncTxDataFromChain(ctx, syncTx, nil))
+	assert.Equal(t, SyncStatusComplete, syncTx.SyncStatus)
+
+	tx, err = client.GetTransaction(ctx, "", tx.ID)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(800000), tx.BlockHeight)
+	assert.Equal(t, blockHash, tx.BlockHash)
+
+	// the BUMP proves the transaction in the block
+	hashes := make([]*chainhash.Hash, 0, len(blockTxIDs))
+	for _, id := range blockTxIDs {
+		hash, hashErr := chainhash.NewHashFromStr(id)
+		require.NoError(t, hashErr)
+		hashes = append(hashes, hash)
+	}
+	merkles := bc.BuildMerkleTreeStoreChainHash(hashes)
+	root, err := tx.BUMP.calculateMerkleRoot()
+	require.NoError(t, err)
+	assert.Equal(t, merkles[len(merkles)-1].String(), root)
+}
+
+// Test_processBroadcastTransactions_Scheduled will test the delayed broadcast (SyncConfig.DelayToBroadcast)
+func Test_processBroadcastTransactions_Scheduled(t *testing.T) {
+	chain := simchain.New(simchain.WithFeeUnit(nil))
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+		WithCustomChainstate(chain))
+	defer deferMe()
+
+	_, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+	opts := append(client.DefaultModelOptions(), New())
+	tx, err := txFromHex(txs[0].String(), opts...)
+	require.NoError(t, err)
+	require.NoError(t, tx.Save(ctx))
+	syncTx := newSyncTransaction(tx.ID, &SyncConfig{Broadcast: true, DelayToBroadcast: time.Hour}, opts...)
+	require.NoError(t, syncTx.Save(ctx))
+	assert.Equal(t, SyncStatusScheduled, syncTx.BroadcastStatus)
+	assert.True(t, syncTx.BroadcastAt.Valid)
+
+	// not due yet
+	require.NoError(t, processBroadcastTransactions(ctx, 10, client.DefaultModelOptions()...))
+	syncTx, err = GetSyncTransactionByID(ctx, tx.ID, client.DefaultModelOptions()...)
+	require.NoError(t, err)
+	assert.Equal(t, SyncStatusScheduled, syncTx.BroadcastStatus)
+	assert.Empty(t, chain.Mempool())
+
+	// due
+	syncTx.BroadcastAt.Time = time.Now().UTC().Add(-time.Minute)
+	require.NoError(t, syncTx.Save(ctx))
+	require.NoError(t, processBroadcastTransactions(ctx, 10, client.DefaultModelOptions()...))
+	syncTx, err = GetSyncTransactionByID(ctx, tx.ID, client.DefaultModelOptions()...)
+	require.NoError(t, err)
+	assert.Equal(t, SyncStatusComplete, syncTx.BroadcastStatus)
+	assert.Equal(t, []string{tx.ID}, chain.Mempool())
+}
+
+// Test_broadcastSyncTransaction_Miner will test the broadcast using a specific miner (SyncConfig.Miner)
+func Test_broadcastSyncTransaction_Miner(t *testing.T) {
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+		WithBroadcastClient(broadcast_client_mock.Builder().WithMockArc(broadcast_client_mock.MockSuccess).Build()))
+	defer deferMe()
+
+	_, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+	opts := append(client.DefaultModelOptions(), New())
+
+	t.Run("unknown miner", func(t *testing.T) {
+		tx, err := txFromHex(txs[0].String(), opts...)
+		require.NoError(t, err)
+		require.NoError(t, tx.Save(ctx))
+		syncTx := newSyncTransaction(tx.ID, &SyncConfig{Broadcast: true, Miner: "unknown"}, opts...)
+		require.NoError(t, syncTx.Save(ctx))
+
+		require.ErrorIs(t, broadcastSyncTransaction(ctx, syncTx), chainstate.ErrBroadcastProviderNotFound)
+		assert.Equal(t, SyncStatusReady, syncTx.BroadcastStatus)
+	})
+
+	t.Run("unknown miner with failover", func(t *testing.T) {
+		tx, err := txFromHex(txs[1].String(), opts...)
+		require.NoError(t, err)
+		require.NoError(t, tx.Save(ctx))
+		syncTx := newSyncTransaction(tx.ID, &SyncConfig{Broadcast: true, Miner: "unknown", MinerFailover: true}, opts...)
+		require.NoError(t, syncTx.Save(ctx))
+
+		require.NoError(t, broadcastSyncTransaction(ctx, syncTx))
+		assert.Equal(t, SyncStatusComplete, syncTx.BroadcastStatus)
+		assert.Equal(t, chainstate.ProviderBroadcastClient, syncTx.Results.Results[len(syncTx.Results.Results)-1].Provider)
+	})
+
+	t.Run("miner", func(t *testing.T) {
+		syncTx, err := GetSyncTransactionByID(ctx, txs[0].TxID(), client.DefaultModelOptions()...)
+		require.NoError(t, err)
+		require.NotNil(t, syncTx)
+		syncTx.Configuration.Miner = chainstate.ProviderBroadcastClient
+
+		require.NoError(t, broadcastSyncTransaction(ctx, syncTx))
+		assert.Equal(t, SyncStatusComplete, syncTx.BroadcastStatus)
+	})
+}
+
+// Test_processSyncTransactions_Retry will test the retry policy of the sync (backoff and dead-letter)
+func Test_processSyncTransactions_Retry(t *testing.T) {
+	chain := simchain.New(simchain.WithFeeUnit(nil))
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup(),
+		WithCustomChainstate(chain), WithSyncRetryPolicy(&SyncRetryPolicy{
+			BackoffFactor: 2,
+			InitialDelay:  time.Hour,
+			Rules: map[string]SyncRetryRule{
+				SyncErrorClassNotFound: {MaxAttempts: 2},
+			},
+		}))
+	defer deferMe()
+
+	_, txs := createTestBEEFTxs(t, testLockingScript, 10000, 100)
+	opts := append(client.DefaultModelOptions(), New())
+	tx, err := txFromHex(txs[0].String(), opts...)
+	require.NoError(t, err)
+	require.NoError(t, tx.Save(ctx))
+	syncTx := newSyncTransaction(tx.ID, &SyncConfig{SyncOnChain: true, Broadcast: true}, opts...)
+	require.NoError(t, syncTx.Save(ctx))
+	require.NoError(t, broadcastSyncTransaction(ctx, syncTx))
+
+	// not mined yet: the next attempt is in an hour
+	require.NoError(t, processSyncTransactions(ctx, 10, client.DefaultModelOptions()...))
+	syncTx, err = GetSyncTransactionByID(ctx, tx.ID, client.DefaultModelOptions()...)
+	require.NoError(t, err)
+	assert.Equal(t, SyncStatusReady, syncTx.SyncStatus)
+	assert.Equal(t, uint32(1), syncTx.Attempts)
+	require.True(t, syncTx.NextAttempt.Valid)
+	assert.WithinDuration(t, time.Now().UTC().Add(time.Hour), syncTx.NextAttempt.Time, time.Minute)
+
+	// not due yet
+	require.NoError(t, processSyncTransactions(ctx, 10, client.DefaultModelOptions()...))
+	syncTx, err = GetSyncTransactionByID(ctx, tx.ID, client.DefaultModelOptions()...)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), syncTx.Attempts)
+
+	// due: the attempts are exhausted
+	syncTx.NextAttempt.Time = time.Now().UTC().Add(-time.Minute)
+	require.NoError(t, syncTx.Save(ctx))
+	require.NoError(t, processSyncTransactions(ctx, 10, client.DefaultModelOptions()...))
+	syncTx, err = GetSyncTransactionByID(ctx, tx.ID, client.DefaultModelOptions()...)
+	require.NoError(t, err)
+	assert.Equal(t, SyncStatusDeadLetter, syncTx.SyncStatus)
+	assert.Equal(t, uint32(2), syncTx.Attempts)
+	assert.WithinDuration(t, time.Now().UTC(), syncTx.NextAttempt.Time, time.Minute)
+
+	// dead-letter is not picked anymore
+	chain.MineBlock()
+	require.NoError(t, processSyncTransactions(ctx, 10, client.DefaultModelOptions()...))
+	syncTx, err = GetSyncTransactionByID(ctx, tx.ID, client.DefaultModelOptions()...)
+	require.NoError(t, err)
+	assert.Equal(t, SyncStatusDeadLetter, syncTx.SyncStatus)
+}
@@ -0,0 +1,181 @@
+package bux
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// syncWorkerPool processes a batch of sync transactions with a number of workers, fairly across the xPubs
+//
+// The xPubs are served round-robin: a worker takes the next transaction of the next xPub in line,
+// so a xPub with a large backlog does not starve the others. The transactions of a xPub are processed
+// in order and one at a time (a xPub is never in line twice).
+type syncWorkerPool struct {
+	err         error                         // First error of the batch
+	line        chan string                   // xPubs waiting for a worker
+	mu          sync.Mutex                    // Guards the fields below
+	remaining   int                           // xPubs with transactions left (the line is closed at 0)
+	stopOnError bool                          // If a xPub is not processed anymore after an error
+	txsByXpub   map[string][]*SyncTransaction // Transactions left per xPub
+}
+
+// processSyncWorkerPool will process the sync transactions with the workers and return the first error
+//
+// With stopOnError the next transactions of a xPub are not processed after an error
+// (IE: broadcasting, the next transactions may spend the failed one)
+func processSyncWorkerPool(ctx context.Context, workers int, syncTxs []*SyncTransaction, stopOnError bool,
+	process func(ctx context.Context, syncTx *SyncTransaction) error,
+) error {
+	if len(syncTxs) == 0 {
+		return nil
+	}
+
+	pool := &syncWorkerPool{
+		stopOnError: stopOnError,
+		txsByXpub:   _groupByXpub(syncTxs),
+	}
+	pool.remaining = len(pool.txsByXpub)
+	pool.line = make(chan string, pool.remaining)
+
+	// The xPubs are in line in the order of their first transaction
+	inLine := make(map[string]bool, pool.remaining)
+	for _, syncTx := range syncTxs {
+		if xPubID := syncTx.xPubID(); !inLine[xPubID] {
+			inLine[xPubID] = true
+			pool.line <- xPubID
+		}
+	}
+
+	// No more workers than xpubs (a xpub is processed by one worker at a time)
+	if workers < 1 {
+		workers = 1
+	} else if workers > pool.remaining {
+		workers = pool.remaining
+	}
+	wg := new(sync.WaitGroup)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for xPubID := range pool.line {
+				syncTx := pool.next(xPubID)
+				pool.done(xPubID, syncTx, process(ctx, syncTx))
+			}
+		}()
+	}
+	wg.Wait()
+
+	return pool.err
+}
+
+// next will return the next transaction of the xPub
+func (p *syncWorkerPool) next(xPubID string) *SyncTransaction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	syncTx := p.txsByXpub[xPubID][0]
+	p.txsByXpub[xPubID] = p.txsByXpub[xPubID][1:]
+	return syncTx
+}
+
+// done will put the xPub back in line (at the end) if it has transactions left
+func (p *syncWorkerPool) done(xPubID string, syncTx *SyncTransaction, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err != nil {
+		syncTx.Client().Logger().Error().
+			Str("txID", syncTx.ID).
+			Str("xpubID", xPubID).
+			Msgf("error processing sync tx: %s", err.Error())
+		if p.err == nil {
+			p.err = err
+		}
+	}
+
+	if len(p.txsByXpub[xPubID]) > 0 && (err == nil || !p.stopOnError) {
+		p.line <- xPubID
+		return
+	}
+
+	delete(p.txsByXpub, xPubID)
+	if p.remaining--; p.remaining == 0 {
+		close(p.line)
+	}
+}
+
+// claimSyncTransaction will claim the sync transaction for the action, nil is returned if it cannot be claimed
+//
+// The claim is a lease on the next attempt (other nodes do not pick the sync transaction until the lease ends,
+// the processing sets the next attempt). It is cluster-safe with a shared cachestore (IE: redis),
+// the status is checked again from the datastore under the claim lock.
+func claimSyncTransaction(ctx context.Context, syncTx *SyncTransaction, action string) (*SyncTransaction, error) {
+	unlock, err := newWriteLock(
+		ctx, fmt.Sprintf(lockKeyClaimSyncTx, syncTx.ID), syncTx.Client().Cachestore(),
+	)
+	defer unlock()
+	if err != nil {
+		return nil, nil //nolint:nilerr // claimed by another node
+	}
+
+	var current *SyncTransaction
+	if current, err = GetSyncTransactionByID(ctx, syncTx.ID, syncTx.GetOptions(false)...); err != nil {
+		return nil, err
+	} else if current == nil {
+		return nil, nil
+	}
+
+	// Still ready and due?
+	now := time.Now().UTC()
+	if current.actionStatus(action) != SyncStatusReady ||
+		(current.NextAttempt.Valid && current.NextAttempt.Time.After(now)) {
+		return nil, nil
+	}
+
+	current.NextAttempt = nextAttemptAt(now.Add(defaultSyncClaimLease))
+	if err = current.Save(ctx); err != nil {
+		return nil, err
+	}
+
+	current.claimedAction = action
+	current.transaction = syncTx.transaction
+	return current, nil
+}
+
+// saveClaimed will save the sync transaction processed by a worker, unless the claimed action was changed meanwhile
+//
+// The admin actions (IE: CancelSyncTransactions) take the claim lock as well, the worker does not overwrite them
+func (m *SyncTransaction) saveClaimed(ctx context.Context) error {
+	unlock, err := newWaitWriteLock(ctx, fmt.Sprintf(lockKeyClaimSyncTx, m.ID), m.Client().Cachestore())
+	defer unlock()
+	if err != nil {
+		return err
+	}
+
+	var current *SyncTransaction
+	if current, err = GetSyncTransactionByID(ctx, m.ID, m.GetOptions(false)...); err != nil {
+		return err
+	} else if current != nil && current.actionStatus(m.claimedAction) != SyncStatusReady {
+		return ErrSyncTransactionChanged
+	}
+
+	if err = Save(ctx, m); err != nil {
+		return err
+	}
+	m.claimedAction = "" // the claim ends with the result of the action
+	return nil
+}
+
+// actionStatus will return the status of the action (broadcast, p2p, sync)
+func (m *SyncTransaction) actionStatus(action string) SyncStatus {
+	switch action {
+	case syncActionBroadcast:
+		return m.BroadcastStatus
+	case syncActionP2P:
+		return m.P2PStatus
+	default:
+		return m.SyncStatus
+	}
+}
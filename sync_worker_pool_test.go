@@ -0,0 +1,186 @@
+package bux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSyncTxs will return sync transactions of the given xpubs (in order)
+func newTestSyncTxs(opts []ModelOps, xPubIDs ...string) []*SyncTransaction {
+	syncTxs := make([]*SyncTransaction, 0, len(xPubIDs))
+	for index, xPubID := range xPubIDs {
+		syncTx := newSyncTransaction(fmt.Sprintf("%s-%d", xPubID, index), &SyncConfig{Broadcast: true}, opts...)
+		syncTx.transaction = &Transaction{XpubInIDs: IDs{xPubID}}
+		syncTxs = append(syncTxs, syncTx)
+	}
+	return syncTxs
+}
+
+// Test_processSyncWorkerPool will test the method processSyncWorkerPool()
+func Test_processSyncWorkerPool(t *testing.T) {
+	_, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup())
+	defer deferMe()
+	opts := append(client.DefaultModelOptions(), New())
+
+	t.Run("round-robin across the xpubs", func(t *testing.T) {
+		var processed []string
+		err := processSyncWorkerPool(
+			context.Background(), 1, newTestSyncTxs(opts, "a", "a", "a", "b", "c"), false,
+			func(_ context.Context, syncTx *SyncTransaction) error {
+				processed = append(processed, syncTx.ID)
+				return nil
+			},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a-0", "b-3", "c-4", "a-1", "a-2"}, processed)
+	})
+
+	t.Run("stop on error", func(t *testing.T) {
+		var processed []string
+		failed := errors.New("failed")
+		err := processSyncWorkerPool(
+			context.Background(), 1, newTestSyncTxs(opts, "a", "a", "b", "b"), true,
+			func(_ context.Context, syncTx *SyncTransaction) error {
+				processed = append(processed, syncTx.ID)
+				if syncTx.ID == "a-0" {
+					return failed
+				}
+				return nil
+			},
+		)
+		require.ErrorIs(t, err, failed)
+		assert.Equal(t, []string{"a-0", "b-2", "b-3"}, processed)
+	})
+
+	t.Run("a xpub is never processed concurrently", func(t *testing.T) {
+		var mu sync.Mutex
+		inFlight := make(map[string]int)
+		count := 0
+		err := processSyncWorkerPool(
+			context.Background(), 4, newTestSyncTxs(opts, "a", "a", "a", "a", "b", "b", "c", "d", "e"), false,
+			func(_ context.Context, syncTx *SyncTransaction) error {
+				xPubID := syncTx.xPubID()
+				mu.Lock()
+				inFlight[xPubID]++
+				assert.Equal(t, 1, inFlight[xPubID])
+				count++
+				mu.Unlock()
+
+				mu.Lock()
+				inFlight[xPubID]--
+				mu.Unlock()
+				return nil
+			},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, 9, count)
+	})
+}
+
+// Test_claimSyncTransaction will test the method claimSyncTransaction()
+func Test_claimSyncTransaction(t *testing.T) {
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup())
+	defer deferMe()
+
+	syncTx := newSyncTransaction(testTxID, &SyncConfig{Broadcast: true}, append(client.DefaultModelOptions(), New())...)
+	require.NoError(t, syncTx.Save(ctx))
+
+	t.Run("claimed by another node", func(t *testing.T) {
+		unlock, err := newWriteLock(ctx, fmt.Sprintf(lockKeyClaimSyncTx, testTxID), client.Cachestore())
+		require.NoError(t, err)
+		defer unlock()
+
+		claimed, err := claimSyncTransaction(ctx, syncTx, syncActionBroadcast)
+		require.NoError(t, err)
+		assert.Nil(t, claimed)
+	})
+
+	t.Run("claim and lease", func(t *testing.T) {
+		claimed, err := claimSyncTransaction(ctx, syncTx, syncActionBroadcast)
+		require.NoError(t, err)
+		require.NotNil(t, claimed)
+		assert.True(t, claimed.NextAttempt.Valid)
+
+		// the lease is not due
+		claimed, err = claimSyncTransaction(ctx, syncTx, syncActionBroadcast)
+		require.NoError(t, err)
+		assert.Nil(t, claimed)
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		claimed, err := claimSyncTransaction(ctx, syncTx, syncActionSync)
+		require.NoError(t, err)
+		assert.Nil(t, claimed)
+	})
+}
+
+// Test_saveClaimed will test that a worker does not overwrite an admin action (see saveClaimed)
+func Test_saveClaimed(t *testing.T) {
+	ctx, client, deferMe := CreateTestSQLiteClient(t, false, false, withTaskManagerMockup())
+	defer deferMe()
+	opts := append(client.DefaultModelOptions(), New())
+
+	newClaimed := func(t *testing.T, id string) *SyncTransaction {
+		syncTx := newSyncTransaction(id, &SyncConfig{SyncOnChain: true}, opts...)
+		require.NoError(t, syncTx.Save(ctx))
+		claimed, err := claimSyncTransaction(ctx, syncTx, syncActionSync)
+		require.NoError(t, err)
+		require.NotNil(t, claimed)
+		return claimed
+	}
+
+	t.Run("canceled while processed", func(t *testing.T) {
+		claimed := newClaimed(t, "canceled")
+		_, err := client.CancelSyncTransactions(ctx, []string{claimed.ID})
+		require.NoError(t, err)
+
+		err = _completeSyncTransaction(ctx, claimed, syncProviderAdmin, "test")
+		require.ErrorIs(t, err, ErrSyncTransactionChanged)
+
+		syncTx, err := GetSyncTransactionByID(ctx, claimed.ID, client.DefaultModelOptions()...)
+		require.NoError(t, err)
+		assert.Equal(t, SyncStatusCanceled, syncTx.SyncStatus)
+	})
+
+	t.Run("saved when not changed", func(t *testing.T) {
+		claimed := newClaimed(t, "completed")
+		require.NoError(t, _completeSyncTransaction(ctx, claimed, syncProviderAdmin, "test"))
+
+		syncTx, err := GetSyncTransactionByID(ctx, claimed.ID, client.DefaultModelOptions()...)
+		require.NoError(t, err)
+		assert.Equal(t, SyncStatusComplete, syncTx.SyncStatus)
+	})
+
+	t.Run("admin and worker race", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			claimed := newClaimed(t, fmt.Sprintf("race-%d", i))
+
+			var wg sync.WaitGroup
+			var canceled []*SyncTransaction
+			var cancelErr error
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				canceled, cancelErr = client.CancelSyncTransactions(ctx, []string{claimed.ID})
+			}()
+			go func() {
+				defer wg.Done()
+				_ = _completeSyncTransaction(ctx, claimed, syncProviderAdmin, "test")
+			}()
+			wg.Wait()
+			require.NoError(t, cancelErr)
+			require.Len(t, canceled, 1)
+
+			// the last writer wins, the admin result is never overwritten by a stale copy
+			syncTx, err := GetSyncTransactionByID(ctx, claimed.ID, client.DefaultModelOptions()...)
+			require.NoError(t, err)
+			assert.Equal(t, canceled[0].SyncStatus, syncTx.SyncStatus)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package simchain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/BuxOrg/bux/chainstate"
+	"github.com/BuxOrg/bux/utils"
+	"github.com/bitcoin-sv/go-broadcast-client/broadcast"
+	"github.com/libsv/go-bc"
+	"github.com/libsv/go-p2p/chaincfg/chainhash"
+)
+
+// MineBlock will mine all the transactions of the mempool into a new block
+//
+// The first transaction of the block is a (simulated) coinbase, so each mined transaction has a merkle path
+func (c *Chain) MineBlock() *Block {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	height := c.tipHeight() + 1
+	block := &Block{
+		Height: height,
+		Time:   defaultGenesisTime.Add(time.Duration(height) * defaultBlockInterval),
+		TxIDs:  c.mempool,
+	}
+	if tip := c.blockAt(c.tipHeight()); tip != nil {
+		block.PreviousHash = tip.Hash
+	}
+
+	// Simulated coinbase, different after each reorg (the new blocks have new hashes)
+	coinbaseID := utils.Hash(fmt.Sprintf("coinbase-%d-%d", height, c.generation))
+	hashes := make([]*chainhash.Hash, 0, len(block.TxIDs)+1)
+	for _, txID := range append([]string{coinbaseID}, block.TxIDs...) {
+		hash, _ := chainhash.NewHashFromStr(txID) // txids are always valid hashes
+		hashes = append(hashes, hash)
+	}
+	merkleTree := bc.BuildMerkleTreeStoreChainHash(hashes)
+	block.MerkleRoot = merkleTree[len(merkleTree)-1].String()
+	block.Hash = utils.Hash(fmt.Sprintf("%s%s%d", block.PreviousHash, block.MerkleRoot, height))
+
+	for i, txID := range block.TxIDs {
+		stx := c.txs[txID]
+		stx.block = block
+		stx.bump, _ = bc.NewBUMPFromMerkleTreeAndIndex(height, merkleTree, uint64(i+1)) // the tree is never empty
+
+		// The txid leaf is not always in the order of the offsets (BRC-74)
+		leaves := stx.bump.Path[0]
+		sort.Slice(leaves, func(i, j int) bool {
+			return *leaves[i].Offset < *leaves[j].Offset
+		})
+	}
+
+	c.blocks = append(c.blocks, block)
+	c.mempool = nil
+	return block
+}
+
+// Reorg will orphan the last blocks, their transactions are back into the mempool (before the current ones)
+//
+// The next mined blocks replace the orphaned blocks with new hashes and merkle roots,
+// the merkle roots of the orphaned blocks are not verified anymore by the header service
+func (c *Chain) Reorg(depth int) ([]*Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if depth <= 0 || depth > len(c.blocks) {
+		return nil, ErrInvalidReorgDepth
+	}
+
+	orphaned := c.blocks[len(c.blocks)-depth:]
+	c.blocks = c.blocks[:len(c.blocks)-depth]
+
+	txIDs := make([]string, 0)
+	for _, block := range orphaned {
+		for _, txID := range block.TxIDs {
+			stx := c.txs[txID]
+			stx.block = nil
+			stx.bump = nil
+			txIDs = append(txIDs, txID)
+		}
+	}
+	c.mempool = append(txIDs, c.mempool...)
+	c.generation++
+	return orphaned, nil
+}
+
+// VerifyMerkleRoots will verify that the merkle roots are the merkle roots of the main chain blocks
+func (c *Chain) VerifyMerkleRoots(_ context.Context, merkleRoots []chainstate.MerkleRootConfirmationRequestItem) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, item := range merkleRoots {
+		if block := c.blockAt(item.BlockHeight); block == nil || block.MerkleRoot != item.MerkleRoot {
+			return fmt.Errorf("%w: %s at height %d", ErrMerkleRootNotFound, item.MerkleRoot, item.BlockHeight)
+		}
+	}
+	return nil
+}
+
+// QueryTransaction will get the transaction info (with the BUMP if mined)
+func (c *Chain) QueryTransaction(
+	_ context.Context, id string, requiredIn chainstate.RequiredIn, _ time.Duration,
+) (*chainstate.TransactionInfo, error) {
+	if len(id) < 50 {
+		return nil, chainstate.ErrInvalidTransactionID
+	} else if requiredIn != chainstate.RequiredInMempool && requiredIn != chainstate.RequiredOnChain {
+		return nil, chainstate.ErrInvalidRequirements
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stx, ok := c.txs[id]
+	if !ok || len(stx.rejected) > 0 {
+		return nil, chainstate.ErrTransactionNotFound
+	}
+
+	if stx.block == nil {
+		if requiredIn == chainstate.RequiredOnChain {
+			return nil, chainstate.ErrTransactionNotFound
+		}
+		return &chainstate.TransactionInfo{
+			ID:       id,
+			Provider: ProviderSimChain,
+			TxStatus: broadcast.SeenOnNetwork,
+		}, nil
+	}
+
+	return &chainstate.TransactionInfo{
+		BlockHash:     stx.block.Hash,
+		BlockHeight:   int64(stx.block.Height),
+		BUMP:          stx.bump,
+		Confirmations: int64(c.tipHeight()-stx.block.Height) + 1,
+		ID:            id,
+		Provider:      ProviderSimChain,
+		TxStatus:      broadcast.Mined,
+	}, nil
+}
+
+// QueryTransactionFastest will get the transaction info (same as QueryTransaction, there is a single provider)
+func (c *Chain) QueryTransactionFastest(
+	ctx context.Context, id string, requiredIn chainstate.RequiredIn, timeout time.Duration,
+) (*chainstate.TransactionInfo, error) {
+	return c.QueryTransaction(ctx, id, requiredIn, timeout)
+}
@@ -0,0 +1,24 @@
+package simchain
+
+import "errors"
+
+// ErrDoubleSpend is when a transaction spends an output already spent by another transaction
+var ErrDoubleSpend = errors.New("258: txn-mempool-conflict")
+
+// ErrFeeTooLow is when the fee of a transaction is lower than the fee policy of the mempool
+var ErrFeeTooLow = errors.New("66: mempool min fee not met")
+
+// ErrMissingInputs is when a transaction spends an output which does not exist
+var ErrMissingInputs = errors.New("16: missing inputs")
+
+// ErrInputsBelowOutputs is when a transaction spends more satoshis than its inputs
+var ErrInputsBelowOutputs = errors.New("16: bad-txns-in-belowout")
+
+// ErrInvalidReorgDepth is when the reorg depth is zero or higher than the number of mined blocks
+var ErrInvalidReorgDepth = errors.New("invalid reorg depth")
+
+// ErrTransactionNotInMempool is when a double spend is injected for a transaction which is not in the mempool
+var ErrTransactionNotInMempool = errors.New("transaction is not in the mempool")
+
+// ErrMerkleRootNotFound is when a merkle root is not the merkle root of the block at the given height
+var ErrMerkleRootNotFound = errors.New("merkle root not found in the main chain")
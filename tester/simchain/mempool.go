@@ -0,0 +1,179 @@
+package simchain
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/BuxOrg/bux/chainstate"
+	"github.com/BuxOrg/bux/utils"
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+)
+
+// Broadcast will validate the transaction and accept it into the mempool
+//
+// txHex can be the raw transaction or the transaction in Extended Format (BRC-30). Inputs spending
+// unknown transactions are considered funded from outside the simulated chain (see Fund), the fee
+// policy is only checked if the satoshis of all the inputs are known
+func (c *Chain) Broadcast(_ context.Context, id, txHex string, _ time.Duration) (string, error) {
+	if len(id) < 50 {
+		return "", chainstate.ErrInvalidTransactionID
+	}
+
+	tx, err := bt.NewTxFromString(txHex)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", chainstate.ErrInvalidTransactionHex, err.Error())
+	} else if tx.TxID() != id {
+		return "", chainstate.ErrTransactionIDMismatch
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Already known: same as "txn-already-known"
+	if known, ok := c.txs[id]; ok {
+		if len(known.rejected) > 0 {
+			return "", ErrDoubleSpend
+		}
+		return ProviderSimChain, nil
+	}
+
+	if err = c.validate(tx); err != nil {
+		return "", err
+	}
+
+	c.accept(tx)
+	return ProviderSimChain, nil
+}
+
+// Fund will accept into the mempool a transaction paying the satoshis to the locking script
+//
+// The transaction spends an output from outside the simulated chain, it is the starting point of a payment flow
+func (c *Chain) Fund(lockingScript *bscript.Script, satoshis uint64) (*bt.Tx, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tx := bt.NewTx()
+	if err := tx.From(
+		utils.Hash(fmt.Sprintf("funding-%d", len(c.txs))), 0, lockingScript.String(), satoshis+1,
+	); err != nil {
+		return nil, err
+	}
+	tx.AddOutput(&bt.Output{LockingScript: lockingScript, Satoshis: satoshis})
+
+	c.accept(tx)
+	return tx, nil
+}
+
+// DoubleSpend will simulate a conflicting transaction winning over the transaction in the mempool
+//
+// The transaction and all its descendants are removed from the mempool and rejected,
+// the ids of the rejected transactions are returned
+func (c *Chain) DoubleSpend(txID string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stx, ok := c.txs[txID]
+	if !ok || stx.block != nil || len(stx.rejected) > 0 {
+		return nil, ErrTransactionNotInMempool
+	}
+	return c.reject(txID, true), nil
+}
+
+// validate will check the inputs and the fee of the transaction (lock must be held)
+func (c *Chain) validate(tx *bt.Tx) error {
+	var inputSatoshis uint64
+	inputsKnown := true
+	for _, input := range tx.Inputs {
+		if _, ok := c.spent[outpoint(input.PreviousTxIDStr(), input.PreviousTxOutIndex)]; ok {
+			return ErrDoubleSpend
+		}
+
+		satoshis := input.PreviousTxSatoshis
+		if parent, ok := c.txs[input.PreviousTxIDStr()]; ok {
+			if len(parent.rejected) > 0 || int(input.PreviousTxOutIndex) >= len(parent.tx.Outputs) {
+				return ErrMissingInputs
+			}
+			satoshis = parent.tx.Outputs[input.PreviousTxOutIndex].Satoshis
+		} else if satoshis == 0 {
+			inputsKnown = false
+		}
+		inputSatoshis += satoshis
+	}
+
+	if !inputsKnown || c.feeUnit == nil {
+		return nil
+	}
+
+	outputSatoshis := tx.TotalOutputSatoshis()
+	if inputSatoshis < outputSatoshis {
+		return ErrInputsBelowOutputs
+	}
+
+	requiredFee := uint64(math.Ceil(float64(tx.Size()) * (float64(c.feeUnit.Satoshis) / float64(c.feeUnit.Bytes))))
+	if inputSatoshis-outputSatoshis < requiredFee {
+		return ErrFeeTooLow
+	}
+	return nil
+}
+
+// accept will add the transaction to the mempool and mark its inputs as spent (lock must be held)
+func (c *Chain) accept(tx *bt.Tx) {
+	txID := tx.TxID()
+	c.txs[txID] = &simTx{tx: tx}
+	c.mempool = append(c.mempool, txID)
+	for _, input := range tx.Inputs {
+		c.spent[outpoint(input.PreviousTxIDStr(), input.PreviousTxOutIndex)] = txID
+	}
+}
+
+// reject will remove the transaction and its descendants from the mempool (lock must be held)
+//
+// The inputs of the conflicting transaction stay spent (by the winning transaction),
+// the inputs of the descendants are spendable again
+func (c *Chain) reject(txID string, conflict bool) []string {
+	stx := c.txs[txID]
+	stx.rejected = ErrDoubleSpend.Error()
+	c.removeFromMempool(txID)
+
+	if !conflict {
+		for _, input := range stx.tx.Inputs {
+			key := outpoint(input.PreviousTxIDStr(), input.PreviousTxOutIndex)
+			if c.spent[key] == txID {
+				delete(c.spent, key)
+			}
+		}
+	}
+
+	rejected := []string{txID}
+	for _, childID := range append([]string{}, c.mempool...) {
+		child := c.txs[childID]
+		if len(child.rejected) > 0 {
+			continue
+		}
+		for _, input := range child.tx.Inputs {
+			if input.PreviousTxIDStr() == txID {
+				rejected = append(rejected, c.reject(childID, false)...)
+				break
+			}
+		}
+	}
+	return rejected
+}
+
+// removeFromMempool will remove the transaction from the mempool (lock must be held)
+func (c *Chain) removeFromMempool(txID string) {
+	for i, id := range c.mempool {
+		if id == txID {
+			c.mempool = append(c.mempool[:i], c.mempool[i+1:]...)
+			return
+		}
+	}
+}
+
+// outpoint will return the key of the output
+func outpoint(txID string, index uint32) string {
+	return fmt.Sprintf("%s:%d", txID, index)
+}
@@ -0,0 +1,246 @@
+/*
+Package simchain is an in-process simulated blockchain for integration tests
+
+The Chain implements chainstate.ClientInterface (and chainstate.HeaderService) with a real mempool:
+broadcast transactions are validated (double spends & fee policy), MineBlock assigns block heights
+and builds the BUMPs of the mined transactions, and DoubleSpend & Reorg inject the failures of a real network.
+
+Everything is deterministic: block hashes, merkle roots and block times only depend on the mined transactions.
+*/
+package simchain
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/BuxOrg/bux/chainstate"
+	"github.com/BuxOrg/bux/utils"
+	"github.com/bitcoin-sv/go-broadcast-client/broadcast"
+	"github.com/libsv/go-bc"
+	"github.com/libsv/go-bt/v2"
+	"github.com/tonicpow/go-minercraft/v2"
+)
+
+// ProviderSimChain is the name of the provider returned by Broadcast and QueryTransaction
+const ProviderSimChain = "simchain"
+
+// Chain defaults
+const (
+	defaultBlockInterval = 10 * time.Minute
+	defaultQueryTimeout  = 10 * time.Second
+	defaultStartHeight   = 1
+)
+
+// defaultGenesisTime is the time of the block before the first mined block
+var defaultGenesisTime = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Block is a block mined by the simulated chain
+type Block struct {
+	Hash         string    `json:"hash"`
+	Height       uint64    `json:"height"`
+	MerkleRoot   string    `json:"merkle_root"`
+	PreviousHash string    `json:"previous_hash"`
+	Time         time.Time `json:"time"`
+	TxIDs        []string  `json:"tx_ids"` // Mined transactions (without the coinbase)
+}
+
+// simTx is a transaction known by the simulated chain
+type simTx struct {
+	block    *Block // nil while in the mempool
+	bump     *bc.BUMP
+	rejected string // reason of the rejection (double spend)
+	tx       *bt.Tx
+}
+
+// Chain is the simulated chain
+type Chain struct {
+	blocks      []*Block           // Main chain (index 0 is at startHeight)
+	debug       bool               // For extra logs
+	feeUnit     *utils.FeeUnit     // Fee policy of the mempool (nil to accept any fee)
+	generation  uint32             // Incremented on each reorg, the new blocks have different hashes
+	mempool     []string           // Transactions waiting to be mined (in order of acceptance)
+	mu          sync.RWMutex       // Guards the chain state
+	network     chainstate.Network // Network returned by Network()
+	spent       map[string]string  // Outpoint (txid:vout) to the txid spending it
+	startHeight uint64             // Height of the first mined block
+	txs         map[string]*simTx  // All the known transactions
+}
+
+// Option allows functional options to be supplied to New
+type Option func(c *Chain)
+
+// WithFeeUnit will set the minimum fee accepted by the mempool (nil accepts any fee)
+func WithFeeUnit(feeUnit *utils.FeeUnit) Option {
+	return func(c *Chain) {
+		c.feeUnit = feeUnit
+	}
+}
+
+// WithNetwork will set the network returned by Network()
+func WithNetwork(network chainstate.Network) Option {
+	return func(c *Chain) {
+		if len(network) > 0 {
+			c.network = network
+		}
+	}
+}
+
+// WithStartHeight will set the height of the first mined block
+func WithStartHeight(height uint64) Option {
+	return func(c *Chain) {
+		if height > 0 {
+			c.startHeight = height
+		}
+	}
+}
+
+// New will create a new simulated chain without any block
+//
+// The default fee policy is the chainstate.MockDefaultFee
+func New(opts ...Option) *Chain {
+	c := &Chain{
+		feeUnit:     chainstate.MockDefaultFee,
+		network:     chainstate.MainNet,
+		spent:       make(map[string]string),
+		startHeight: defaultStartHeight,
+		txs:         make(map[string]*simTx),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Height will return the height of the chain tip (startHeight - 1 if no block was mined)
+func (c *Chain) Height() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.tipHeight()
+}
+
+// Block will return the block of the main chain at the given height (nil if not found)
+func (c *Chain) Block(height uint64) *Block {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.blockAt(height)
+}
+
+// Mempool will return the transaction ids waiting to be mined
+func (c *Chain) Mempool() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]string{}, c.mempool...)
+}
+
+// HeaderService will return the header service of the chain (to verify the merkle roots of the BUMPs)
+func (c *Chain) HeaderService() chainstate.HeaderService {
+	return c
+}
+
+// ProviderStatus will return the health of the (single) simulated provider
+func (c *Chain) ProviderStatus() []*chainstate.ProviderStatus {
+	return []*chainstate.ProviderStatus{{
+		CircuitState: chainstate.CircuitClosed,
+		Name:         ProviderSimChain,
+		Score:        1,
+	}}
+}
+
+// Minercraft will return nil, the simulated chain has no miners
+func (c *Chain) Minercraft() minercraft.ClientInterface {
+	return nil
+}
+
+// BroadcastClient will return nil, the simulated chain has no broadcast client
+func (c *Chain) BroadcastClient() broadcast.Client {
+	return nil
+}
+
+// Close will close the client (nothing to close)
+func (c *Chain) Close(context.Context) {}
+
+// Debug will set the debug flag
+func (c *Chain) Debug(on bool) {
+	c.debug = on
+}
+
+// DebugLog will do nothing, the simulated chain has no logger
+func (c *Chain) DebugLog(string) {}
+
+// HTTPClient will return the default HTTP client (never used by the simulated chain)
+func (c *Chain) HTTPClient() chainstate.HTTPInterface {
+	return http.DefaultClient
+}
+
+// IsDebug will return if debugging is enabled
+func (c *Chain) IsDebug() bool {
+	return c.debug
+}
+
+// IsNewRelicEnabled will return false
+func (c *Chain) IsNewRelicEnabled() bool {
+	return false
+}
+
+// Network will return the network of the chain
+func (c *Chain) Network() chainstate.Network {
+	return c.network
+}
+
+// QueryTimeout will return the query timeout
+func (c *Chain) QueryTimeout() time.Duration {
+	return defaultQueryTimeout
+}
+
+// FeeUnit will return the fee policy of the mempool (a zero fee if any fee is accepted)
+func (c *Chain) FeeUnit() *utils.FeeUnit {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.feeUnit == nil {
+		return &utils.FeeUnit{Satoshis: 0, Bytes: 1000}
+	}
+	return c.feeUnit
+}
+
+// FeeUnitForPriority will return the fee unit of the priority (default multipliers over the fee policy of the mempool)
+func (c *Chain) FeeUnitForPriority(priority chainstate.FeePriority) (*utils.FeeUnit, error) {
+	return chainstate.DefaultFeePriorities().FeeUnit(c.FeeUnit(), priority)
+}
+
+// RefreshFeeUnit will return the fee policy of the mempool as the single fee quote
+func (c *Chain) RefreshFeeUnit(context.Context) (*chainstate.FeeUnitRefresh, error) {
+	feeUnit := c.FeeUnit()
+	return &chainstate.FeeUnitRefresh{
+		FeeUnit:  feeUnit,
+		Previous: feeUnit,
+		Provider: ProviderSimChain,
+		Quotes:   []utils.FeeUnit{*feeUnit},
+	}, nil
+}
+
+// SetFeeUnit will set the fee policy of the mempool
+func (c *Chain) SetFeeUnit(feeUnit *utils.FeeUnit) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.feeUnit = feeUnit
+}
+
+// tipHeight will return the height of the chain tip (lock must be held)
+func (c *Chain) tipHeight() uint64 {
+	return c.startHeight + uint64(len(c.blocks)) - 1
+}
+
+// blockAt will return the block of the main chain at the given height (lock must be held)
+func (c *Chain) blockAt(height uint64) *Block {
+	if height < c.startHeight || height > c.tipHeight() {
+		return nil
+	}
+	return c.blocks[height-c.startHeight]
+}
@@ -0,0 +1,239 @@
+package simchain
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/BuxOrg/bux/chainstate"
+	"github.com/bitcoin-sv/go-broadcast-client/broadcast"
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLockingScript = "76a9147ff514e6ae3deb46e6644caac5cdd0bf2388906588ac"
+
+// newTestSpend will create a transaction spending the first output of the parent
+func newTestSpend(t *testing.T, parent *bt.Tx, satoshis uint64) *bt.Tx {
+	script, err := bscript.NewFromHexString(testLockingScript)
+	require.NoError(t, err)
+
+	tx := bt.NewTx()
+	require.NoError(t, tx.From(parent.TxID(), 0, testLockingScript, parent.Outputs[0].Satoshis))
+	tx.AddOutput(&bt.Output{LockingScript: script, Satoshis: satoshis})
+	return tx
+}
+
+// newTestChain will create a chain with a funding transaction (10000 satoshis)
+func newTestChain(t *testing.T, opts ...Option) (*Chain, *bt.Tx) {
+	script, err := bscript.NewFromHexString(testLockingScript)
+	require.NoError(t, err)
+
+	chain := New(opts...)
+	var funding *bt.Tx
+	funding, err = chain.Fund(script, 10000)
+	require.NoError(t, err)
+	return chain, funding
+}
+
+// TestChain_Broadcast will test the method Broadcast()
+func TestChain_Broadcast(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("accepted into the mempool", func(t *testing.T) {
+		chain, funding := newTestChain(t)
+		tx := newTestSpend(t, funding, 9900)
+
+		provider, err := chain.Broadcast(ctx, tx.TxID(), tx.String(), 0)
+		require.NoError(t, err)
+		assert.Equal(t, ProviderSimChain, provider)
+		assert.Equal(t, []string{funding.TxID(), tx.TxID()}, chain.Mempool())
+
+		// already known
+		_, err = chain.Broadcast(ctx, tx.TxID(), tx.String(), 0)
+		require.NoError(t, err)
+
+		var info *chainstate.TransactionInfo
+		info, err = chain.QueryTransaction(ctx, tx.TxID(), chainstate.RequiredInMempool, 0)
+		require.NoError(t, err)
+		assert.Equal(t, broadcast.SeenOnNetwork, info.TxStatus)
+
+		_, err = chain.QueryTransaction(ctx, tx.TxID(), chainstate.RequiredOnChain, 0)
+		require.ErrorIs(t, err, chainstate.ErrTransactionNotFound)
+	})
+
+	t.Run("extended format", func(t *testing.T) {
+		chain, funding := newTestChain(t)
+		tx := newTestSpend(t, funding, 9900)
+
+		_, err := chain.Broadcast(ctx, tx.TxID(), hex.EncodeToString(tx.ExtendedBytes()), 0)
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid transactions", func(t *testing.T) {
+		chain, funding := newTestChain(t)
+		tx := newTestSpend(t, funding, 9900)
+
+		_, err := chain.Broadcast(ctx, "", tx.String(), 0)
+		require.ErrorIs(t, err, chainstate.ErrInvalidTransactionID)
+
+		_, err = chain.Broadcast(ctx, funding.TxID(), tx.String(), 0)
+		require.ErrorIs(t, err, chainstate.ErrTransactionIDMismatch)
+
+		_, err = chain.Broadcast(ctx, tx.TxID(), "invalid", 0)
+		require.ErrorIs(t, err, chainstate.ErrInvalidTransactionHex)
+	})
+
+	t.Run("fee policy", func(t *testing.T) {
+		chain, funding := newTestChain(t)
+
+		tx := newTestSpend(t, funding, 10000)
+		_, err := chain.Broadcast(ctx, tx.TxID(), tx.String(), 0)
+		require.ErrorIs(t, err, ErrFeeTooLow)
+
+		tx = newTestSpend(t, funding, 20000)
+		_, err = chain.Broadcast(ctx, tx.TxID(), tx.String(), 0)
+		require.ErrorIs(t, err, ErrInputsBelowOutputs)
+
+		// no fee policy
+		chain, funding = newTestChain(t, WithFeeUnit(nil))
+		tx = newTestSpend(t, funding, 10000)
+		_, err = chain.Broadcast(ctx, tx.TxID(), tx.String(), 0)
+		require.NoError(t, err)
+	})
+
+	t.Run("double spend", func(t *testing.T) {
+		chain, funding := newTestChain(t)
+		tx := newTestSpend(t, funding, 9900)
+		_, err := chain.Broadcast(ctx, tx.TxID(), tx.String(), 0)
+		require.NoError(t, err)
+
+		conflict := newTestSpend(t, funding, 9800)
+		_, err = chain.Broadcast(ctx, conflict.TxID(), conflict.String(), 0)
+		require.ErrorIs(t, err, ErrDoubleSpend)
+	})
+}
+
+// TestChain_MineBlock will test the method MineBlock()
+func TestChain_MineBlock(t *testing.T) {
+	ctx := context.Background()
+	chain, funding := newTestChain(t, WithStartHeight(800000))
+	tx := newTestSpend(t, funding, 9900)
+	_, err := chain.Broadcast(ctx, tx.TxID(), tx.String(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(799999), chain.Height())
+
+	block := chain.MineBlock()
+	assert.Equal(t, uint64(800000), block.Height)
+	assert.Equal(t, []string{funding.TxID(), tx.TxID()}, block.TxIDs)
+	assert.Empty(t, chain.Mempool())
+	assert.Equal(t, block, chain.Block(800000))
+
+	next := chain.MineBlock()
+	assert.Equal(t, block.Hash, next.PreviousHash)
+	assert.Empty(t, next.TxIDs)
+
+	info, err := chain.QueryTransaction(ctx, tx.TxID(), chainstate.RequiredOnChain, 0)
+	require.NoError(t, err)
+	assert.True(t, info.Valid())
+	assert.Equal(t, broadcast.Mined, info.TxStatus)
+	assert.Equal(t, block.Hash, info.BlockHash)
+	assert.Equal(t, int64(800000), info.BlockHeight)
+	assert.Equal(t, int64(2), info.Confirmations)
+
+	// the BUMP is verified by the header service
+	merkleRoot, err := info.BUMP.CalculateRootGivenTxid(tx.TxID())
+	require.NoError(t, err)
+	assert.Equal(t, block.MerkleRoot, merkleRoot)
+	require.NoError(t, chain.HeaderService().VerifyMerkleRoots(ctx, []chainstate.MerkleRootConfirmationRequestItem{
+		{MerkleRoot: merkleRoot, BlockHeight: info.BUMP.BlockHeight},
+	}))
+	require.ErrorIs(t, chain.VerifyMerkleRoots(ctx, []chainstate.MerkleRootConfirmationRequestItem{
+		{MerkleRoot: merkleRoot, BlockHeight: next.Height},
+	}), ErrMerkleRootNotFound)
+
+	// deterministic
+	other, otherFunding := newTestChain(t, WithStartHeight(800000))
+	require.Equal(t, funding.TxID(), otherFunding.TxID())
+	_, err = other.Broadcast(ctx, tx.TxID(), tx.String(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, block, other.MineBlock())
+}
+
+// TestChain_DoubleSpend will test the method DoubleSpend()
+func TestChain_DoubleSpend(t *testing.T) {
+	ctx := context.Background()
+	chain, funding := newTestChain(t)
+	tx := newTestSpend(t, funding, 9900)
+	_, err := chain.Broadcast(ctx, tx.TxID(), tx.String(), 0)
+	require.NoError(t, err)
+	child := newTestSpend(t, tx, 9800)
+	_, err = chain.Broadcast(ctx, child.TxID(), child.String(), 0)
+	require.NoError(t, err)
+
+	rejected, err := chain.DoubleSpend(tx.TxID())
+	require.NoError(t, err)
+	assert.Equal(t, []string{tx.TxID(), child.TxID()}, rejected)
+	assert.Equal(t, []string{funding.TxID()}, chain.Mempool())
+
+	_, err = chain.QueryTransaction(ctx, tx.TxID(), chainstate.RequiredInMempool, 0)
+	require.ErrorIs(t, err, chainstate.ErrTransactionNotFound)
+	_, err = chain.Broadcast(ctx, tx.TxID(), tx.String(), 0)
+	require.ErrorIs(t, err, ErrDoubleSpend)
+	_, err = chain.Broadcast(ctx, child.TxID(), child.String(), 0)
+	require.ErrorIs(t, err, ErrDoubleSpend)
+
+	_, err = chain.DoubleSpend(tx.TxID())
+	require.ErrorIs(t, err, ErrTransactionNotInMempool)
+}
+
+// TestChain_Reorg will test the method Reorg()
+func TestChain_Reorg(t *testing.T) {
+	ctx := context.Background()
+	chain, funding := newTestChain(t)
+	tx := newTestSpend(t, funding, 9900)
+	_, err := chain.Broadcast(ctx, tx.TxID(), tx.String(), 0)
+	require.NoError(t, err)
+
+	_, err = chain.Reorg(1)
+	require.ErrorIs(t, err, ErrInvalidReorgDepth)
+
+	block := chain.MineBlock()
+	chain.MineBlock()
+	minedInfo, err := chain.QueryTransaction(ctx, tx.TxID(), chainstate.RequiredOnChain, 0)
+	require.NoError(t, err)
+
+	orphaned, err := chain.Reorg(2)
+	require.NoError(t, err)
+	require.Len(t, orphaned, 2)
+	assert.Equal(t, block, orphaned[0])
+	assert.Equal(t, []string{funding.TxID(), tx.TxID()}, chain.Mempool())
+	assert.Equal(t, uint64(0), chain.Height())
+
+	_, err = chain.QueryTransaction(ctx, tx.TxID(), chainstate.RequiredOnChain, 0)
+	require.ErrorIs(t, err, chainstate.ErrTransactionNotFound)
+
+	// mined again in another block, the orphaned merkle root is not valid anymore
+	replacement := chain.MineBlock()
+	assert.Equal(t, block.Height, replacement.Height)
+	assert.NotEqual(t, block.Hash, replacement.Hash)
+	assert.NotEqual(t, block.MerkleRoot, replacement.MerkleRoot)
+	require.ErrorIs(t, chain.VerifyMerkleRoots(ctx, []chainstate.MerkleRootConfirmationRequestItem{
+		{MerkleRoot: block.MerkleRoot, BlockHeight: minedInfo.BUMP.BlockHeight},
+	}), ErrMerkleRootNotFound)
+
+	info, err := chain.QueryTransaction(ctx, tx.TxID(), chainstate.RequiredOnChain, 0)
+	require.NoError(t, err)
+	assert.Equal(t, replacement.Hash, info.BlockHash)
+}
+
+// TestChain_ClientInterface will test that the chain can be used as the chainstate client
+func TestChain_ClientInterface(t *testing.T) {
+	var client chainstate.ClientInterface = New(WithNetwork(chainstate.TestNet))
+	assert.Equal(t, chainstate.TestNet, client.Network())
+	assert.Equal(t, chainstate.MockDefaultFee, client.FeeUnit())
+	require.Len(t, client.ProviderStatus(), 1)
+	assert.Equal(t, ProviderSimChain, client.ProviderStatus()[0].Name)
+}
@@ -23,6 +23,10 @@ func getTransactionByID(ctx context.Context, xPubID, txID string, opts ...ModelO
 		return nil, err
 	}
 
+	if err := loadTransactionBUMPs(ctx, []*Transaction{tx}, opts...); err != nil {
+		return nil, err
+	}
+
 	return tx, nil
 }
 
@@ -35,6 +39,10 @@ func getTransactions(ctx context.Context, metadata *Metadata, conditions *map[st
 		return nil, err
 	}
 
+	if err := loadTransactionBUMPs(ctx, modelItems, opts...); err != nil {
+		return nil, err
+	}
+
 	return modelItems, nil
 }
 
@@ -178,6 +186,10 @@ func getTransactionsInternal(ctx context.Context, conditions map[string]interfac
 		transactions = append(transactions, tx)
 	}
 
+	if err := loadTransactionBUMPs(ctx, transactions, opts...); err != nil {
+		return nil, err
+	}
+
 	return transactions, nil
 }
 
@@ -3,8 +3,10 @@ package bux
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 
+	"github.com/BuxOrg/bux/chainstate"
 	"github.com/BuxOrg/bux/utils"
 )
 
@@ -55,6 +57,103 @@ func saveRawTransaction(ctx context.Context, c ClientInterface, allowUnknown boo
 	return tx, nil
 }
 
+// saveHistoryTransaction will save a past transaction of the addresses of an xPub (see RescanXpub)
+//
+// The transaction is not broadcast, the unmined transactions and the mined transactions (without BUMP) are synced
+func saveHistoryTransaction(ctx context.Context, c ClientInterface, historyTx *AddressHistoryTransaction,
+	opts ...ModelOps,
+) (*Transaction, error) {
+	tx, err := txFromHex(historyTx.Hex, c.DefaultModelOptions(append(opts, New())...)...)
+	if err != nil {
+		return nil, ErrMissingTxHex
+	}
+	tx.BlockHash = historyTx.BlockHash
+	tx.BlockHeight = historyTx.BlockHeight
+
+	if err = tx._processHistoryInputs(ctx); err != nil {
+		return nil, err
+	}
+	if err = tx._processOutputs(ctx); err != nil {
+		return nil, err
+	}
+	tx.TotalValue, tx.Fee = tx.getValues()
+	tx.NumberOfInputs = uint32(len(tx.parsedTx.Inputs))
+	tx.NumberOfOutputs = uint32(len(tx.parsedTx.Outputs))
+
+	sync := newSyncTransaction(tx.GetID(), c.DefaultSyncConfig(), tx.GetOptions(true)...)
+	sync.BroadcastStatus = SyncStatusSkipped
+	sync.P2PStatus = SyncStatusSkipped
+	tx.syncTransaction = sync
+
+	if err = tx.Save(ctx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// processRescannedUtxos will record the utxos of an already recorded transaction which were unknown when it
+// was recorded: the outputs paying to and the inputs spending from the destinations created by RescanXpub
+//
+// Only the new utxos (and the newly spent ones) are saved and update the output values and the xPub balances
+func (m *Transaction) processRescannedUtxos(ctx context.Context) error {
+	if err := m.setID(); err != nil {
+		return err
+	}
+
+	recordedValue := m.XpubOutputValue
+	m.XpubOutputValue = XpubOutputValue{}
+	if err := m._processHistoryInputs(ctx); err != nil {
+		return err
+	}
+	if err := m._processOutputs(ctx); err != nil {
+		return err
+	}
+
+	// The utxos of the outputs recorded with the transaction are already counted
+	utxos := make([]Utxo, 0, len(m.utxos))
+	for _, utxo := range m.utxos {
+		if utxo.TransactionID == m.ID && !utxo.IsNew() {
+			m.XpubOutputValue[utxo.XpubID] -= int64(utxo.Satoshis)
+			continue
+		}
+		utxos = append(utxos, utxo)
+	}
+	values := m.XpubOutputValue
+	m.utxos = utxos
+	m.XpubOutputValue = recordedValue
+	if len(m.utxos) == 0 {
+		return nil
+	}
+
+	if m.XpubOutputValue == nil {
+		m.XpubOutputValue = XpubOutputValue{}
+	}
+	for xPubID, value := range values {
+		m.XpubOutputValue[xPubID] += value
+	}
+	if err := m.Save(ctx); err != nil {
+		return err
+	}
+
+	// update the xpub balances (not updated by the hooks, the transaction is not created)
+	opts := m.GetOptions(false)
+	for xPubID, value := range values {
+		if value == 0 {
+			continue
+		}
+		xPub, err := getXpubWithCache(ctx, m.Client(), "", xPubID, opts...)
+		if err != nil {
+			return err
+		} else if xPub == nil {
+			return ErrMissingRequiredXpub
+		}
+		if err = xPub.incrementBalance(ctx, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // processUtxos will process the inputs and outputs for UTXOs
 func (m *Transaction) processUtxos(ctx context.Context) error {
 	// Input should be processed only for outgoing transactions
@@ -115,21 +214,7 @@ func (m *Transaction) _processInputs(ctx context.Context) (err error) {
 				}
 			}
 
-			// Update the output value
-			if _, ok := m.XpubOutputValue[utxo.XpubID]; !ok {
-				m.XpubOutputValue[utxo.XpubID] = 0
-			}
-			m.XpubOutputValue[utxo.XpubID] -= int64(utxo.Satoshis)
-
-			// Mark utxo as spent
-			utxo.SpendingTxID.Valid = true
-			utxo.SpendingTxID.String = m.ID
-			m.utxos = append(m.utxos, *utxo)
-
-			// Add the xPub ID
-			if !utils.StringInSlice(utxo.XpubID, m.XpubInIDs) {
-				m.XpubInIDs = append(m.XpubInIDs, utxo.XpubID)
-			}
+			m._spendUtxo(utxo)
 		}
 
 		// todo: what if the utxo is nil (not found)?
@@ -138,6 +223,43 @@ func (m *Transaction) _processInputs(ctx context.Context) (err error) {
 	return
 }
 
+// _processHistoryInputs will spend the known utxos of the inputs of a past transaction (see RescanXpub)
+//
+// Unlike _processInputs, the utxos are not reserved by a draft transaction
+func (m *Transaction) _processHistoryInputs(ctx context.Context) error {
+	opts := m.GetOptions(false)
+	for _, input := range m.TransactionBase.parsedTx.Inputs {
+		utxo, err := m.transactionService.getUtxo(ctx,
+			hex.EncodeToString(input.PreviousTxID()), input.PreviousTxOutIndex, opts...,
+		)
+		if err != nil {
+			return err
+		} else if utxo != nil && len(utxo.SpendingTxID.String) == 0 {
+			m._spendUtxo(utxo)
+		}
+	}
+	return nil
+}
+
+// _spendUtxo will mark the utxo as spent by the transaction
+func (m *Transaction) _spendUtxo(utxo *Utxo) {
+	// Update the output value
+	if _, ok := m.XpubOutputValue[utxo.XpubID]; !ok {
+		m.XpubOutputValue[utxo.XpubID] = 0
+	}
+	m.XpubOutputValue[utxo.XpubID] -= int64(utxo.Satoshis)
+
+	// Mark utxo as spent
+	utxo.SpendingTxID.Valid = true
+	utxo.SpendingTxID.String = m.ID
+	m.utxos = append(m.utxos, *utxo)
+
+	// Add the xPub ID
+	if !utils.StringInSlice(utxo.XpubID, m.XpubInIDs) {
+		m.XpubInIDs = append(m.XpubInIDs, utxo.XpubID)
+	}
+}
+
 // processTxOutputs will process the transaction outputs
 func (m *Transaction) _processOutputs(ctx context.Context) (err error) {
 	// Pre-build the options
@@ -193,3 +315,95 @@ func (m *Transaction) _processOutputs(ctx context.Context) (err error) {
 
 	return
 }
+
+// migrateLegacyMerkleProofs will backfill the BUMP of the mined transactions which have no BUMP
+//
+// The BUMP is converted from the legacy TSC merkle proof if found, otherwise the transaction is queried
+// again through chainstate. Only the mined transactions without a block BUMP are read; the transactions
+// which cannot be resolved are marked (see blockBUMPIDUnresolved) and not tried again by the next runs,
+// the transactions which could not be queried (IE: timeout) are left for the next runs
+func migrateLegacyMerkleProofs(ctx context.Context, client ClientInterface, opts ...ModelOps) (*MerkleProofMigration, error) {
+	result := &MerkleProofMigration{}
+	conditions := map[string]interface{}{
+		blockHeightField: map[string]interface{}{conditionGreaterThan: 0},
+		conditionOr: []map[string]interface{}{
+			{blockBUMPIDField: nil},
+			{blockBUMPIDField: ""},
+		},
+	}
+
+	err := iterateModels[*Transaction](ctx, ModelTransaction, &conditions, func(tx *Transaction) error {
+		tx.enrich(ModelTransaction, opts...)
+
+		// BUMP stored on the transaction: move it to the block BUMP
+		if len(tx.BUMP.Path) > 0 {
+			if err := tx.Save(ctx); err != nil {
+				return err
+			} else if tx.BlockBUMPID != "" {
+				result.Moved++
+				return nil
+			}
+			result.Failed++
+			return tx.markBUMPUnresolved(ctx)
+		}
+
+		if tx.MerkleProof != nil {
+			bump, err := tx.MerkleProof.ToBUMP(tx.BlockHeight)
+			if err == nil {
+				tx.BUMP = bump
+				result.Converted++
+				return tx.Save(ctx)
+			}
+			client.Logger().Warn().
+				Str("txID", tx.ID).
+				Err(err).
+				Msg("could not convert the legacy merkle proof, querying the transaction")
+		}
+
+		// SPV only: the transaction cannot be queried
+		if client.IsSPVOnlyEnabled() {
+			result.Failed++
+			return tx.markBUMPUnresolved(ctx)
+		}
+
+		txInfo, err := client.Chainstate().QueryTransaction(
+			ctx, tx.ID, chainstate.RequiredOnChain, defaultQueryTxTimeout,
+		)
+		if err != nil && !errors.Is(err, chainstate.ErrTransactionNotFound) {
+			// not a definitive answer (IE: timeout, provider outage): tried again by the next run
+			client.Logger().Warn().
+				Str("txID", tx.ID).
+				Err(err).
+				Msg("could not query the transaction for its BUMP, skipping it")
+			result.Failed++
+			return nil
+		} else if err != nil || !txInfo.Valid() {
+			client.Logger().Warn().
+				Str("txID", tx.ID).
+				Err(err).
+				Msg("could not query the transaction for its BUMP")
+			result.Failed++
+			return tx.markBUMPUnresolved(ctx)
+		}
+
+		tx.setChainInfo(txInfo)
+		if len(tx.BUMP.Path) == 0 {
+			result.Failed++
+			return tx.markBUMPUnresolved(ctx)
+		}
+		result.Queried++
+		return tx.Save(ctx)
+	}, opts...)
+
+	return result, err
+}
+
+// markBUMPUnresolved will mark the transaction as unresolved by the merkle proofs migration
+//
+// The mark is replaced when the transaction is saved with a BUMP (IE: synced again)
+func (m *Transaction) markBUMPUnresolved(ctx context.Context) error {
+	if m.BlockBUMPID == "" {
+		m.BlockBUMPID = blockBUMPIDUnresolved
+	}
+	return m.Save(ctx)
+}
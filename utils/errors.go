@@ -7,6 +7,9 @@ import (
 // ErrXpubInvalidLength is when the length of the xpub does not match the desired length
 var ErrXpubInvalidLength = errors.New("xpub is an invalid length")
 
+// ErrXpubInvalidVersion is when the version bytes of the xpub are not a public key of mainnet or testnet/regtest
+var ErrXpubInvalidVersion = errors.New("xpub has invalid version bytes")
+
 // ErrXpubNoMatch is when the derived xpub key does not match the key given
 var ErrXpubNoMatch = errors.New("xpub key does not match raw key")
 
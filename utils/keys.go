@@ -4,6 +4,7 @@ import (
 	"github.com/bitcoinschema/go-bitcoin/v2"
 	"github.com/libsv/go-bk/bec"
 	"github.com/libsv/go-bk/bip32"
+	"github.com/libsv/go-bk/chaincfg"
 	"github.com/libsv/go-bt/v2/bscript"
 )
 
@@ -40,7 +41,14 @@ func DerivePublicKey(hdKey *bip32.ExtendedKey, chain uint32, num uint32) (*bec.P
 	return pubKeys[chain], nil
 }
 
-// ValidateXPub will check the xPub key for length & validation
+// IsTestNetKey will return true if the extended key has the test version bytes (tpub/tprv)
+//
+// Test keys are used by testnet and regtest, their addresses use the test version byte (m/n addresses)
+func IsTestNetKey(hdKey *bip32.ExtendedKey) bool {
+	return hdKey != nil && hdKey.IsForNet(&chaincfg.TestNet)
+}
+
+// ValidateXPub will check the xPub key for length, version (xpub or tpub) & validation
 func ValidateXPub(rawKey string) (*bip32.ExtendedKey, error) {
 
 	// Validate the xpub (length)
@@ -52,13 +60,15 @@ func ValidateXPub(rawKey string) (*bip32.ExtendedKey, error) {
 	hdKey, err := bitcoin.GetHDKeyFromExtendedPublicKey(rawKey)
 	if err != nil {
 		return nil, err
+	} else if hdKey.IsPrivate() || (!hdKey.IsForNet(&chaincfg.MainNet) && !IsTestNetKey(hdKey)) {
+		return nil, ErrXpubInvalidVersion
 	} else if hdKey.String() != rawKey { // Sanity check (might not be needed)
 		return nil, ErrXpubNoMatch
 	}
 	return hdKey, nil
 }
 
-// DeriveAddress will derive the given address from a key
+// DeriveAddress will derive the given address from a key (using the network of the key, see IsTestNetKey)
 func DeriveAddress(hdKey *bip32.ExtendedKey, chain uint32, num uint32) (address string, err error) {
 
 	// Don't panic
@@ -78,7 +88,7 @@ func DeriveAddress(hdKey *bip32.ExtendedKey, chain uint32, num uint32) (address
 	}
 
 	var addressScript *bscript.Address
-	if addressScript, err = bitcoin.GetAddressFromPubKey(pubKey, true); err != nil {
+	if addressScript, err = bscript.NewAddressFromPublicKey(pubKey, !IsTestNetKey(hdKey)); err != nil {
 		// Should never error if the pubKeys are valid keys
 		return "", err
 	}
@@ -86,7 +96,7 @@ func DeriveAddress(hdKey *bip32.ExtendedKey, chain uint32, num uint32) (address
 	return addressScript.AddressString, nil
 }
 
-// DeriveAddresses will derive the internal and external address from a key
+// DeriveAddresses will derive the internal and external address from a key (using the network of the key)
 func DeriveAddresses(hdKey *bip32.ExtendedKey, num uint32) (external, internal string, err error) {
 
 	// Don't panic
@@ -94,15 +104,25 @@ func DeriveAddresses(hdKey *bip32.ExtendedKey, num uint32) (external, internal s
 		return "", "", ErrHDKeyNil
 	}
 
-	// Derive the address
-	var addresses []string
-	if addresses, err = bitcoin.GetAddressesForPath(
+	// Derive the public keys
+	var pubKeys []*bec.PublicKey
+	if pubKeys, err = bitcoin.GetPublicKeysForPath(
 		hdKey, num,
 	); err != nil {
 		return
-	} else if len(addresses) != 2 { // Sanity check might not be needed
+	} else if len(pubKeys) != 2 { // Sanity check might not be needed
 		return "", "", ErrDeriveFailed
 	}
+
+	// Derive the addresses
+	addresses := make([]string, 0, len(pubKeys))
+	for _, pubKey := range pubKeys {
+		var address *bscript.Address
+		if address, err = bscript.NewAddressFromPublicKey(pubKey, !IsTestNetKey(hdKey)); err != nil {
+			return "", "", err
+		}
+		addresses = append(addresses, address.AddressString)
+	}
 	external = addresses[0]
 	internal = addresses[1]
 	return
@@ -9,6 +9,7 @@ import (
 )
 
 const (
+	testTPub = "tpubD6NzVbkrYhZ4XeNA3WvGU7WUxAmW1q5ET9reVdEJRwGwm3mMaFVnZqwLJzRVgShUKNoh294q2bZ7692QNiBz1B68ymvAgwVPGchPWP14p9y"
 	testXPub = "xpub661MyMwAqRbcFrBJbKwBGCB7d3fr2SaAuXGM95BA62X41m6eW2ehRQGW4xLi9wkEXUGnQZYxVVj4PxXnyrLk7jdqvBAs1Qq9gf6ykMvjR7J"
 )
 
@@ -31,6 +32,17 @@ func Test_DeriveAddresses(t *testing.T) {
 		assert.Equal(t, "13St2SHkw1b8ZuaExyMf6ZMEzNjYbWRqL4", internal)
 		assert.Equal(t, "19jswATg9vBFta1aRnEjPHa2KMwafkmANj", external)
 	})
+
+	t.Run("DeriveAddresses tpub (testnet & regtest)", func(t *testing.T) {
+		tPub, err := bip32.NewKeyFromString(testTPub)
+		require.NoError(t, err)
+
+		var external, internal string
+		external, internal, err = DeriveAddresses(tPub, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "n3vTN83Lb6kQt1njfFeNBzBSRWxQyA7b7g", internal)
+		assert.Equal(t, "mmBnQSrkLZ2WFNZPJ23ukmFK5B5PDjXJhD", external)
+	})
 }
 
 // Test_DeriveAddress will test the method DeriveAddress()
@@ -58,6 +70,19 @@ func Test_DeriveAddress(t *testing.T) {
 		assert.Equal(t, "13St2SHkw1b8ZuaExyMf6ZMEzNjYbWRqL4", internal)
 		assert.Equal(t, "19jswATg9vBFta1aRnEjPHa2KMwafkmANj", external)
 	})
+
+	t.Run("DeriveAddress tpub (testnet & regtest)", func(t *testing.T) {
+		tPub, err := bip32.NewKeyFromString(testTPub)
+		require.NoError(t, err)
+
+		var internal, external string
+		internal, err = DeriveAddress(tPub, ChainInternal, 2)
+		require.NoError(t, err)
+		external, err = DeriveAddress(tPub, ChainExternal, 2)
+		require.NoError(t, err)
+		assert.Equal(t, "mhxqKVNjk32PM23rgYL2vUZZrNLFWCnabs", internal)
+		assert.Equal(t, "mpFqEDYexwcWfgVC9MD7DCnMBMYHXk7wzJ", external)
+	})
 }
 
 // Benchmark_DeriveAddresses will benchmark the method DeriveAddresses()
@@ -76,6 +76,19 @@ func TestValidateXPub(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("valid tpub (testnet & regtest)", func(t *testing.T) {
+		hdKey, err := ValidateXPub(testTPub)
+		require.NotNil(t, hdKey)
+		require.NoError(t, err)
+		assert.True(t, IsTestNetKey(hdKey))
+	})
+
+	t.Run("private key", func(t *testing.T) {
+		hdKey, err := ValidateXPub(testXpriv)
+		assert.Nil(t, hdKey)
+		require.ErrorIs(t, err, ErrXpubInvalidVersion)
+	})
+
 	t.Run("invalid length", func(t *testing.T) {
 		hdKey, err := ValidateXPub("1234567890")
 		assert.Nil(t, hdKey)